@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	"github.com/lambda/internal/service"
+)
+
+// Error codes the auth subgraph puts in extensions.code, so clients can
+// tell a wrong OTP from an expired session without parsing messages.
+const (
+	CodeBadUserInput       = "BAD_USER_INPUT"
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeInvalidOTP         = "INVALID_OTP"
+	CodeSessionExpired     = "SESSION_EXPIRED"
+	CodeUserExists         = "USER_ALREADY_EXISTS"
+	CodeUserNotConfirmed   = "USER_NOT_CONFIRMED"
+	CodeTooManyAttempts    = "TOO_MANY_ATTEMPTS"
+	CodeInvalidInvitation  = "INVALID_INVITATION"
+	CodeInvitationNotFound = "INVITATION_NOT_FOUND"
+	CodeInvitationExpired  = "INVITATION_EXPIRED"
+	CodeInvitationUsed     = "INVITATION_USED"
+	CodeInvitationRevoked  = "INVITATION_REVOKED"
+	CodeUnauthenticated    = "UNAUTHENTICATED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeInternal           = "INTERNAL_SERVER_ERROR"
+)
+
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{service.ErrInvalidInput, CodeBadUserInput},
+	{service.ErrInvalidCredentials, CodeInvalidCredentials},
+	{service.ErrInvalidOTP, CodeInvalidOTP},
+	{service.ErrSessionExpired, CodeSessionExpired},
+	{service.ErrUserExists, CodeUserExists},
+	{service.ErrUserNotConfirmed, CodeUserNotConfirmed},
+	{service.ErrTooManyAttempts, CodeTooManyAttempts},
+	{service.ErrInvalidInvitation, CodeInvalidInvitation},
+	{service.ErrInvitationNotFound, CodeInvitationNotFound},
+	{service.ErrInvitationExpired, CodeInvitationExpired},
+	{service.ErrInvitationUsed, CodeInvitationUsed},
+	{service.ErrInvitationRevoked, CodeInvitationRevoked},
+	{auth.ErrUnauthenticated, CodeUnauthenticated},
+	{auth.ErrInvalidToken, CodeUnauthenticated},
+	{authz.ErrForbidden, CodeForbidden},
+}
+
+// ErrorPresenter gives the services' errors their code. Any other error is
+// logged and reported as an internal error, so Cognito and database
+// details do not reach clients.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			presented.Extensions = map[string]interface{}{"code": known.code}
+			return presented
+		}
+	}
+
+	log.Printf("auth subgraph error at %v: %v", presented.Path, err)
+	return &gqlerror.Error{
+		Message:    "internal server error",
+		Path:       presented.Path,
+		Extensions: map[string]interface{}{"code": CodeInternal},
+	}
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	"github.com/lambda/internal/service"
+)
+
+func TestErrorPresenterCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{fmt.Errorf("%w: password is too short", service.ErrInvalidInput), CodeBadUserInput},
+		{service.ErrInvalidCredentials, CodeInvalidCredentials},
+		{service.ErrInvalidOTP, CodeInvalidOTP},
+		{service.ErrSessionExpired, CodeSessionExpired},
+		{service.ErrUserExists, CodeUserExists},
+		{service.ErrUserNotConfirmed, CodeUserNotConfirmed},
+		{service.ErrTooManyAttempts, CodeTooManyAttempts},
+		{fmt.Errorf("%w: token is malformed", service.ErrInvalidInvitation), CodeInvalidInvitation},
+		{service.ErrInvitationNotFound, CodeInvitationNotFound},
+		{service.ErrInvitationExpired, CodeInvitationExpired},
+		{service.ErrInvitationUsed, CodeInvitationUsed},
+		{service.ErrInvitationRevoked, CodeInvitationRevoked},
+		{auth.ErrUnauthenticated, CodeUnauthenticated},
+		{fmt.Errorf("%w: token is expired", auth.ErrInvalidToken), CodeUnauthenticated},
+		{fmt.Errorf("%w: only navigator_admin may manage invitations", authz.ErrForbidden), CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			// Resolver errors reach the presenter wrapped in a gqlerror.
+			for _, err := range []error{tt.err, &gqlerror.Error{Message: tt.err.Error(), Err: tt.err}} {
+				presented := ErrorPresenter(context.Background(), err)
+				if code := presented.Extensions["code"]; code != tt.code {
+					t.Errorf("code for %v = %v, want %s", err, code, tt.code)
+				}
+				if presented.Message != tt.err.Error() {
+					t.Errorf("message = %q, want %q", presented.Message, tt.err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestErrorPresenterHidesInternalErrors(t *testing.T) {
+	err := errors.New(`pq: relation "invitations" does not exist`)
+	presented := ErrorPresenter(context.Background(), err)
+	if presented.Extensions["code"] != CodeInternal {
+		t.Errorf("code = %v, want %s", presented.Extensions["code"], CodeInternal)
+	}
+	if presented.Message != "internal server error" {
+		t.Errorf("message = %q, want the generic message", presented.Message)
+	}
+}
+
+func TestEveryCodeIsMapped(t *testing.T) {
+	mapped := map[string]bool{}
+	for _, known := range errorCodes {
+		mapped[known.code] = true
+	}
+	for _, code := range []string{
+		CodeBadUserInput, CodeInvalidCredentials, CodeInvalidOTP, CodeSessionExpired,
+		CodeUserExists, CodeUserNotConfirmed, CodeTooManyAttempts, CodeInvalidInvitation,
+		CodeInvitationNotFound, CodeInvitationExpired, CodeInvitationUsed,
+		CodeInvitationRevoked, CodeUnauthenticated, CodeForbidden,
+	} {
+		if !mapped[code] {
+			t.Errorf("no error maps to %s", code)
+		}
+	}
+}
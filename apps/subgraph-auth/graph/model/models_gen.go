@@ -2,12 +2,34 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
 type AuthResponse struct {
 	AccessToken  *string `json:"accessToken,omitempty"`
 	IDToken      *string `json:"idToken,omitempty"`
 	RefreshToken *string `json:"refreshToken,omitempty"`
 }
 
+type Invitation struct {
+	ID        string           `json:"id"`
+	Email     string           `json:"email"`
+	Role      string           `json:"role"`
+	Status    InvitationStatus `json:"status"`
+	InvitedBy string           `json:"invitedBy"`
+	ExpiresAt string           `json:"expiresAt"`
+	CreatedAt string           `json:"createdAt"`
+}
+
+// Errors carry extensions.code: BAD_USER_INPUT, INVALID_CREDENTIALS,
+// INVALID_OTP, SESSION_EXPIRED, USER_ALREADY_EXISTS, USER_NOT_CONFIRMED,
+// TOO_MANY_ATTEMPTS, INVALID_INVITATION, INVITATION_NOT_FOUND,
+// INVITATION_EXPIRED, INVITATION_USED, INVITATION_REVOKED, UNAUTHENTICATED,
+// FORBIDDEN or INTERNAL_SERVER_ERROR.
 type Mutation struct {
 }
 
@@ -21,3 +43,62 @@ type SessionResponse struct {
 type TokenResponse struct {
 	Token *string `json:"token,omitempty"`
 }
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusExpired  InvitationStatus = "expired"
+)
+
+var AllInvitationStatus = []InvitationStatus{
+	InvitationStatusPending,
+	InvitationStatusAccepted,
+	InvitationStatusRevoked,
+	InvitationStatusExpired,
+}
+
+func (e InvitationStatus) IsValid() bool {
+	switch e {
+	case InvitationStatusPending, InvitationStatusAccepted, InvitationStatusRevoked, InvitationStatusExpired:
+		return true
+	}
+	return false
+}
+
+func (e InvitationStatus) String() string {
+	return string(e)
+}
+
+func (e *InvitationStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = InvitationStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid InvitationStatus", str)
+	}
+	return nil
+}
+
+func (e InvitationStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *InvitationStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e InvitationStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
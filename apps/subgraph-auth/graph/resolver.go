@@ -11,4 +11,5 @@ import (
 type Resolver struct{
 	AuthService       *service.AuthService
 	InvitationService *service.InvitationService
+	UserService       *service.UserService
 }
\ No newline at end of file
@@ -6,66 +6,103 @@ package graph
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/lambda/apps/subgraph-auth/graph/generated"
 	"github.com/lambda/apps/subgraph-auth/graph/model"
+	"github.com/lambda/internal/domain"
 )
 
 // InviteUser is the resolver for the inviteUser field.
 func (r *mutationResolver) InviteUser(ctx context.Context, email string, role string) (*model.TokenResponse, error) {
-	// MOCK: Normally this would call InvitationService
-	mockToken := "mock-jwt-token-for-" + email
-	return &model.TokenResponse{Token: &mockToken}, nil
+	token, err := r.InvitationService.CreateInvitation(ctx, &domain.Invitation{
+		Email: email,
+		Role:  domain.Role(role),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.TokenResponse{Token: &token}, nil
 }
 
 // ValidateInvite is the resolver for the validateInvite field.
 func (r *mutationResolver) ValidateInvite(ctx context.Context, token string) (*string, error) {
-	// MOCK: validation logic
-	if token == "invalid" {
-		return nil, fmt.Errorf("invalid token")
+	invitation, err := r.InvitationService.ValidateInvitationToken(ctx, token)
+	if err != nil {
+		return nil, err
 	}
-	msg := "Token is valid (MOCK)"
-	return &msg, nil
+	return &invitation.Email, nil
+}
+
+// AcceptInvitation is the resolver for the acceptInvitation field.
+func (r *mutationResolver) AcceptInvitation(ctx context.Context, token string, password string) (*string, error) {
+	invitation, err := r.InvitationService.AcceptInvitation(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+	sub := invitation.AcceptedBy.String()
+	return &sub, nil
+}
+
+// RevokeInvitation is the resolver for the revokeInvitation field.
+func (r *mutationResolver) RevokeInvitation(ctx context.Context, id string) (*model.Invitation, error) {
+	invitation, err := r.InvitationService.RevokeInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return convertInvitationToModel(invitation), nil
+}
+
+// ResendInvitation is the resolver for the resendInvitation field.
+func (r *mutationResolver) ResendInvitation(ctx context.Context, id string) (*model.TokenResponse, error) {
+	invitation, err := r.InvitationService.ResendInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TokenResponse{Token: &invitation.Token}, nil
 }
 
 // RegisterUser is the resolver for the registerUser field.
 func (r *mutationResolver) RegisterUser(ctx context.Context, email string, password string, role string, tenantID string, navigatorAdminID string) (*string, error) {
-	// MOCK: Cognito registration
-	sub := "mock-user-sub-12345"
+	user, err := r.UserService.RegisterUser(ctx, email, password, domain.Role(role), tenantID, navigatorAdminID)
+	if err != nil {
+		return nil, err
+	}
+	sub := user.ID.String()
 	return &sub, nil
 }
 
 // SendOtp is the resolver for the sendOtp field.
 func (r *mutationResolver) SendOtp(ctx context.Context, email string, password string) (*model.SessionResponse, error) {
-	// MOCK: Start OTP
-	session := "mock-session-id-555"
-	return &model.SessionResponse{Session: &session}, nil
+	resp, err := r.AuthService.StartOTPChallenge(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SessionResponse{Session: resp.Session}, nil
 }
 
 // VerifyOtp is the resolver for the verifyOtp field.
 func (r *mutationResolver) VerifyOtp(ctx context.Context, email string, otp string, session string) (*model.AuthResponse, error) {
-	// MOCK: Verify OTP
-	if otp != "123456" {
-		return nil, fmt.Errorf("invalid OTP")
+	result, err := r.AuthService.VerifyOTPChallenge(ctx, email, otp, session)
+	if err != nil {
+		return nil, err
 	}
-
-	accessToken := "mock-access-token"
-	idToken := "mock-id-token"
-	refreshToken := "mock-refresh-token"
-
 	return &model.AuthResponse{
-		AccessToken:  &accessToken,
-		IDToken:      &idToken,
-		RefreshToken: &refreshToken,
+		AccessToken:  result.AccessToken,
+		IDToken:      result.IdToken,
+		RefreshToken: result.RefreshToken,
 	}, nil
 }
 
 // LoginUser is the resolver for the loginUser field.
 func (r *mutationResolver) LoginUser(ctx context.Context, email string, password string) (*model.SessionResponse, error) {
-	// MOCK: Login
-	session := "mock-session-id-login-999"
-	return &model.SessionResponse{Session: &session}, nil
+	// Login and sendOtp are the same custom auth flow: the password is
+	// checked and an OTP challenge issued.
+	resp, err := r.AuthService.StartOTPChallenge(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SessionResponse{Session: resp.Session}, nil
 }
 
 // Health is the resolver for the health field.
@@ -74,6 +111,24 @@ func (r *queryResolver) Health(ctx context.Context) (*string, error) {
 	return &status, nil
 }
 
+// PendingInvitations is the resolver for the pendingInvitations field.
+func (r *queryResolver) PendingInvitations(ctx context.Context, invitedBy *string) ([]*model.Invitation, error) {
+	var inviter string
+	if invitedBy != nil {
+		inviter = *invitedBy
+	}
+	invitations, err := r.InvitationService.ListPendingInvitations(ctx, inviter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Invitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, convertInvitationToModel(invitation))
+	}
+	return result, nil
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
@@ -81,4 +136,16 @@ func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResol
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
 type mutationResolver struct{ *Resolver }
-type queryResolver struct{ *Resolver }
\ No newline at end of file
+type queryResolver struct{ *Resolver }
+
+func convertInvitationToModel(i *domain.Invitation) *model.Invitation {
+	return &model.Invitation{
+		ID:        i.ID.String(),
+		Email:     i.Email,
+		Role:      string(i.Role),
+		Status:    model.InvitationStatus(i.Status),
+		InvitedBy: i.InvitedBy.String(),
+		ExpiresAt: i.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: i.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/auth"
+	appdb "github.com/lambda/internal/db"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/service"
+)
+
+// These tests run the subgraph against a migrated write model at
+// TEST_DATABASE_URL and the user pool of template.yaml, with its OTP
+// triggers, in LocalStack or cognito-local at LOCALSTACK_URL
+// (COGNITO_USER_POOL_ID, COGNITO_CLIENT_ID). They are skipped when any of
+// those is unset.
+
+const (
+	testIssuer   = "https://cognito-idp.us-east-1.amazonaws.com/us-east-1_integration"
+	testClientID = "integration-client"
+	testKeyID    = "integration-key"
+	testPassword = "Integration-test-1"
+)
+
+type subgraph struct {
+	url     string
+	db      *gorm.DB
+	auth    *service.AuthService
+	key     *rsa.PrivateKey
+	tenant  string
+	adminID string
+}
+
+func newSubgraph(t *testing.T) *subgraph {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	poolID := os.Getenv("COGNITO_USER_POOL_ID")
+	clientID := os.Getenv("COGNITO_CLIENT_ID")
+	if dsn == "" || os.Getenv("LOCALSTACK_URL") == "" || poolID == "" || clientID == "" {
+		t.Skip("TEST_DATABASE_URL, LOCALSTACK_URL, COGNITO_USER_POOL_ID and COGNITO_CLIENT_ID must be set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		t.Fatalf("enable tenant isolation: %v", err)
+	}
+	cfg, err := cognitoConfig(context.Background())
+	if err != nil {
+		t.Fatalf("load SDK config: %v", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	jwks, err := json.Marshal(auth.JWKS{Keys: []auth.JWK{auth.NewJWK(testKeyID, &key.PublicKey)}})
+	if err != nil {
+		t.Fatalf("Marshal JWKS: %v", err)
+	}
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, jwks, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	verifier, err := auth.NewVerifier(auth.Config{Issuer: testIssuer, ClientID: testClientID, JWKSFile: jwksFile})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	s := &subgraph{
+		db:      db,
+		auth:    service.NewAuthService(cfg, poolID, clientID),
+		key:     key,
+		tenant:  "integration-" + uuid.NewString(),
+		adminID: uuid.NewString(),
+	}
+	server := httptest.NewServer(newHandler(db, verifier, s.auth, "integration-secret"))
+	t.Cleanup(server.Close)
+	s.url = server.URL
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM invitations WHERE tenant_id = ?", s.tenant)
+		db.Exec("DELETE FROM users WHERE tenant_id = ?", s.tenant)
+	})
+	return s
+}
+
+// token is an ID token of the subgraph's tenant for role.
+func (s *subgraph) token(t *testing.T, role string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub":              s.adminID,
+		"iss":              testIssuer,
+		"aud":              testClientID,
+		"token_use":        "id",
+		"exp":              time.Now().Add(time.Hour).Unix(),
+		"custom:tenant_id": s.tenant,
+		"custom:role":      role,
+	})
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// do posts a GraphQL request, with the bearer token when there is one.
+func (s *subgraph) do(t *testing.T, token, query string, variables map[string]interface{}) graphQLResponse {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return result
+}
+
+// ok runs a request that must succeed and decodes its field into out.
+func (s *subgraph) ok(t *testing.T, token, query string, variables map[string]interface{}, field string, out interface{}) {
+	t.Helper()
+	result := s.do(t, token, query, variables)
+	if len(result.Errors) > 0 {
+		t.Fatalf("%s: %s (%v)", field, result.Errors[0].Message, result.Errors[0].Extensions["code"])
+	}
+	if err := json.Unmarshal(result.Data[field], out); err != nil {
+		t.Fatalf("decode %s: %v", field, err)
+	}
+}
+
+// fails runs a request that must fail with code.
+func (s *subgraph) fails(t *testing.T, token, query string, variables map[string]interface{}, code string) {
+	t.Helper()
+	result := s.do(t, token, query, variables)
+	if len(result.Errors) == 0 {
+		t.Fatalf("request succeeded, want %s", code)
+	}
+	if got := result.Errors[0].Extensions["code"]; got != code {
+		t.Fatalf("code = %v (%s), want %s", got, result.Errors[0].Message, code)
+	}
+}
+
+const (
+	inviteUser       = `mutation($email: String!, $role: String!) { inviteUser(email: $email, role: $role) { token } }`
+	acceptInvitation = `mutation($token: String!, $password: String!) { acceptInvitation(token: $token, password: $password) }`
+	revokeInvitation = `mutation($id: ID!) { revokeInvitation(id: $id) { id status } }`
+	sendOtp          = `mutation($email: String!, $password: String!) { sendOtp(email: $email, password: $password) { session } }`
+	verifyOtp        = `mutation($email: String!, $otp: String!, $session: String!) { verifyOtp(email: $email, otp: $otp, session: $session) { idToken } }`
+	registerUser     = `mutation($email: String!, $password: String!, $role: String!, $tenantId: String!, $navigatorAdminId: String!) { registerUser(email: $email, password: $password, role: $role, tenantId: $tenantId, navigatorAdminId: $navigatorAdminId) }`
+)
+
+// invite has the tenant's navigator admin invite a new person, and
+// returns their email and the invitation token.
+func (s *subgraph) invite(t *testing.T) (string, string) {
+	t.Helper()
+	email := "integration-" + uuid.NewString() + "@example.com"
+	var resp struct{ Token string }
+	s.ok(t, s.token(t, "navigator_admin"), inviteUser, map[string]interface{}{"email": email, "role": "patient_navigator"}, "inviteUser", &resp)
+	return email, resp.Token
+}
+
+// accept accepts the invitation, removes the user it creates when the
+// test ends, and returns the user's ID.
+func (s *subgraph) accept(t *testing.T, email, token string) string {
+	t.Helper()
+	var sub string
+	s.ok(t, "", acceptInvitation, map[string]interface{}{"token": token, "password": testPassword}, "acceptInvitation", &sub)
+	t.Cleanup(func() { s.auth.DeleteCognitoUser(context.Background(), email) })
+	return sub
+}
+
+func TestInvitationManagementNeedsNavigatorAdmin(t *testing.T) {
+	s := newSubgraph(t)
+	variables := map[string]interface{}{"email": "someone@example.com", "role": "patient_navigator"}
+
+	s.fails(t, "", inviteUser, variables, "UNAUTHENTICATED")
+	s.fails(t, "not-a-token", inviteUser, variables, "UNAUTHENTICATED")
+	s.fails(t, s.token(t, "patient_navigator"), inviteUser, variables, "FORBIDDEN")
+	s.fails(t, s.token(t, "patient_navigator"), revokeInvitation, map[string]interface{}{"id": uuid.NewString()}, "FORBIDDEN")
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	s := newSubgraph(t)
+
+	t.Run("stores the user", func(t *testing.T) {
+		email, token := s.invite(t)
+		sub := s.accept(t, email, token)
+
+		var user domain.User
+		if err := s.db.Where("id = ?", sub).Take(&user).Error; err != nil {
+			t.Fatalf("find user %s: %v", sub, err)
+		}
+		if user.TenantID != s.tenant || user.Email != email || user.Role != domain.RolePatientNavigator ||
+			user.NavigatorAdminID == nil || user.NavigatorAdminID.String() != s.adminID {
+			t.Fatalf("user = %+v, want a patient_navigator of the tenant reporting to the inviting admin", user)
+		}
+	})
+
+	t.Run("used", func(t *testing.T) {
+		email, token := s.invite(t)
+		s.accept(t, email, token)
+		s.fails(t, "", acceptInvitation, map[string]interface{}{"token": token, "password": testPassword}, "INVITATION_USED")
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		_, token := s.invite(t)
+		var id string
+		if err := s.db.Table("invitations").Where("token = ?", token).Pluck("id", &id).Error; err != nil {
+			t.Fatalf("find invitation: %v", err)
+		}
+		var revoked struct{ Status string }
+		s.ok(t, s.token(t, "navigator_admin"), revokeInvitation, map[string]interface{}{"id": id}, "revokeInvitation", &revoked)
+		s.fails(t, "", acceptInvitation, map[string]interface{}{"token": token, "password": testPassword}, "INVITATION_REVOKED")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, token := s.invite(t)
+		if err := s.db.Exec("UPDATE invitations SET expires_at = NOW() - INTERVAL '1 hour' WHERE token = ?", token).Error; err != nil {
+			t.Fatalf("expire invitation: %v", err)
+		}
+		s.fails(t, "", acceptInvitation, map[string]interface{}{"token": token, "password": testPassword}, "INVITATION_EXPIRED")
+	})
+
+	t.Run("existing user", func(t *testing.T) {
+		email, token := s.invite(t)
+		s.accept(t, email, token)
+
+		// A second invitation to the same email cannot register them again.
+		var resp struct{ Token string }
+		s.ok(t, s.token(t, "navigator_admin"), inviteUser, map[string]interface{}{"email": email, "role": "patient_navigator"}, "inviteUser", &resp)
+		s.fails(t, "", acceptInvitation, map[string]interface{}{"token": resp.Token, "password": testPassword}, "USER_ALREADY_EXISTS")
+	})
+}
+
+func TestRegisterUser(t *testing.T) {
+	s := newSubgraph(t)
+	email := "integration-" + uuid.NewString() + "@example.com"
+	variables := map[string]interface{}{"email": email, "password": testPassword, "role": "patient_navigator", "tenantId": "", "navigatorAdminId": ""}
+
+	s.fails(t, "", registerUser, variables, "UNAUTHENTICATED")
+	s.fails(t, s.token(t, "patient_navigator"), registerUser, variables, "FORBIDDEN")
+	variables["tenantId"] = "another-tenant"
+	s.fails(t, s.token(t, "navigator_admin"), registerUser, variables, "FORBIDDEN")
+	variables["tenantId"] = s.tenant
+
+	var sub string
+	s.ok(t, s.token(t, "navigator_admin"), registerUser, variables, "registerUser", &sub)
+	t.Cleanup(func() { s.auth.DeleteCognitoUser(context.Background(), email) })
+
+	var user domain.User
+	if err := s.db.Where("id = ?", sub).Take(&user).Error; err != nil {
+		t.Fatalf("find user %s: %v", sub, err)
+	}
+	if user.TenantID != s.tenant || user.Role != domain.RolePatientNavigator ||
+		user.NavigatorAdminID == nil || user.NavigatorAdminID.String() != s.adminID {
+		t.Fatalf("user = %+v, want a patient_navigator of the tenant reporting to the registering admin", user)
+	}
+}
+
+func TestVerifyOtp(t *testing.T) {
+	s := newSubgraph(t)
+	email, token := s.invite(t)
+	s.accept(t, email, token)
+
+	var started struct{ Session string }
+	s.ok(t, "", sendOtp, map[string]interface{}{"email": email, "password": testPassword}, "sendOtp", &started)
+
+	// A wrong code ends the session, so answering it again finds it expired.
+	answer := map[string]interface{}{"email": email, "otp": "000000", "session": started.Session}
+	s.fails(t, "", verifyOtp, answer, "INVALID_OTP")
+	s.fails(t, "", verifyOtp, answer, "SESSION_EXPIRED")
+}
@@ -15,7 +15,10 @@ import (
 
 	"github.com/lambda/apps/subgraph-auth/graph"
 	"github.com/lambda/apps/subgraph-auth/graph/generated"
+	"github.com/lambda/internal/auth"
+	appdb "github.com/lambda/internal/db"
 	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/requestctx"
 	"github.com/lambda/internal/service"
 )
 
@@ -36,41 +39,28 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		log.Fatalf("failed to enable tenant isolation: %v", err)
+	}
 
-	// 2. AWS Config for Cognito (LocalStack)
-	customResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
-		return aws.Endpoint{
-			PartitionID:   "aws",
-			URL:           "http://localhost:4566",
-			SigningRegion: "us-east-1",
-		}, nil
-	})
+	// Signing in and accepting invitations are anonymous; managing
+	// invitations and registering users need a navigator admin's token.
+	verifier, err := auth.NewVerifierFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure token verification: %v", err)
+	}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion("us-east-1"),
-		config.WithEndpointResolverWithOptions(customResolver),
-		config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
-			return aws.Credentials{
-				AccessKeyID:     "test",
-				SecretAccessKey: "test",
-				SessionToken:    "test",
-			}, nil
-		})),
-	)
+	// 2. AWS Config for Cognito (LocalStack, or any stand-in at LOCALSTACK_URL)
+	cfg, err := cognitoConfig(context.TODO())
 	if err != nil {
 		log.Fatalf("unable to load SDK config, %v", err)
 	}
 
-	// 3. Repositories
-	invitationRepo := repository.NewInvitationRepository(db)
-	// userRepo := repository.NewUserRepository(db) // Unused in this main.go, but available
-
-	// 4. Services
+	// 3. Services
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		jwtSecret = "local-secret-key"
 	}
-	invitationService := service.NewInvitationService(invitationRepo, jwtSecret)
 
 	userPoolID := os.Getenv("COGNITO_USER_POOL_ID")
 	if userPoolID == "" {
@@ -84,17 +74,55 @@ func main() {
 
 	authService := service.NewAuthService(cfg, userPoolID, clientID)
 
-	// 5. Resolver Injection
+	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	http.Handle("/query", newHandler(db, verifier, authService, jwtSecret))
+
+	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
+
+// cognitoConfig points the AWS SDK at LOCALSTACK_URL with dummy
+// credentials.
+func cognitoConfig(ctx context.Context) (aws.Config, error) {
+	localstackURL := os.Getenv("LOCALSTACK_URL")
+	if localstackURL == "" {
+		localstackURL = "http://localhost:4566"
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	customResolver := aws.EndpointResolverWithOptionsFunc(func(service, r string, options ...interface{}) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			PartitionID:   "aws",
+			URL:           localstackURL,
+			SigningRegion: region,
+		}, nil
+	})
+
+	return config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithEndpointResolverWithOptions(customResolver),
+		config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     "test",
+				SecretAccessKey: "test",
+				SessionToken:    "test",
+			}, nil
+		})),
+	)
+}
+
+// newHandler serves the subgraph's GraphQL endpoint, verifying the
+// caller's token, when there is one, with verifier.
+func newHandler(db *gorm.DB, verifier *auth.Verifier, authService *service.AuthService, jwtSecret string) http.Handler {
 	resolver := &graph.Resolver{
 		AuthService:       authService,
-		InvitationService: invitationService,
+		InvitationService: service.NewInvitationService(repository.NewInvitationRepository(db), authService, jwtSecret),
+		UserService:       service.NewUserService(repository.NewUserRepository(db), authService),
 	}
 
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
-
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
-
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+	return requestctx.Middleware(auth.OptionalMiddleware(verifier)(srv))
 }
@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/lambda/apps/subgraph-intervention/graph/generated"
+	"github.com/lambda/apps/subgraph-intervention/graph/model"
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+)
+
+// Directives implements the schema's directives.
+func Directives() generated.DirectiveRoot {
+	return generated.DirectiveRoot{HasRole: HasRole}
+}
+
+// HasRole resolves the field only when the caller's role is one of roles.
+func HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, roles []model.Role) (interface{}, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if string(role) == principal.Role {
+			return next(ctx)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s requires one of the roles %v", authz.ErrForbidden, graphql.GetFieldContext(ctx).Field.Name, roles)
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/lambda/apps/subgraph-intervention/graph/generated"
+	"github.com/lambda/apps/subgraph-intervention/graph/model"
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+)
+
+// fieldActions is the policy action behind each field @hasRole guards.
+var fieldActions = map[string]authz.Action{
+	"Query.intervention":            authz.ReadIntervention,
+	"Query.interventions":           authz.ReadIntervention,
+	"Query.searchInterventions":     authz.ReadIntervention,
+	"Query.barrierCounts":           authz.ViewAnalytics,
+	"Query.workload":                authz.ViewAnalytics,
+	"Mutation.createInterventions":  authz.CreateIntervention,
+	"Mutation.updateIntervention":   authz.UpdateIntervention,
+	"Mutation.completeIntervention": authz.CompleteIntervention,
+	"Mutation.cancelIntervention":   authz.CancelIntervention,
+	"Mutation.startIntervention":    authz.StartIntervention,
+	"Mutation.reopenIntervention":   authz.ReopenIntervention,
+}
+
+// publicFields need no role: the health check and federation's own.
+var publicFields = map[string]bool{
+	"Query.health":    true,
+	"Query._service":  true,
+	"Query._entities": true,
+}
+
+func TestHasRoleMatchesPolicy(t *testing.T) {
+	schema := generated.NewExecutableSchema(generated.Config{}).Schema()
+
+	for _, object := range []*ast.Definition{schema.Query, schema.Mutation} {
+		for _, field := range object.Fields {
+			name := object.Name + "." + field.Name
+			if publicFields[name] || field.Name == "__schema" || field.Name == "__type" {
+				continue
+			}
+			action, ok := fieldActions[name]
+			if !ok {
+				t.Errorf("%s has no policy action to check its roles against", name)
+				continue
+			}
+			directive := field.Directives.ForName("hasRole")
+			if directive == nil {
+				t.Errorf("%s has no @hasRole", name)
+				continue
+			}
+
+			var got []string
+			for _, role := range directive.Arguments.ForName("roles").Value.Children {
+				got = append(got, role.Value.Raw)
+			}
+			var want []string
+			for _, rule := range authz.DefaultPolicy[action] {
+				for _, role := range rule.Roles {
+					want = append(want, string(role))
+				}
+			}
+			sort.Strings(got)
+			sort.Strings(want)
+			if len(got) != len(want) {
+				t.Errorf("%s @hasRole roles = %v, policy grants %s to %v", name, got, action, want)
+				continue
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("%s @hasRole roles = %v, policy grants %s to %v", name, got, action, want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	roles := []model.Role{model.RolePatientNavigator, model.RoleNavigatorAdmin}
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		wantErr   error
+	}{
+		{"no principal", nil, auth.ErrUnauthenticated},
+		{"listed role", &auth.Principal{UserID: "user-1", TenantID: "tenant-a", Role: "navigator_admin"}, nil},
+		{"other role", &auth.Principal{UserID: "user-1", TenantID: "tenant-a", Role: "social_worker"}, authz.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := graphql.WithFieldContext(context.Background(), &graphql.FieldContext{
+				Field: graphql.CollectedField{Field: &ast.Field{Name: "cancelIntervention"}},
+			})
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, tt.principal)
+			}
+			resolved := false
+			next := func(ctx context.Context) (interface{}, error) {
+				resolved = true
+				return "ok", nil
+			}
+
+			_, err := HasRole(ctx, nil, next, roles)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("HasRole: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HasRole err = %v, want %v", err, tt.wantErr)
+			}
+			if resolved != (tt.wantErr == nil) {
+				t.Errorf("resolver ran = %v, want %v", resolved, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -43,6 +43,7 @@ type ResolverRoot interface {
 }
 
 type DirectiveRoot struct {
+	HasRole func(ctx context.Context, obj any, next graphql.Resolver, roles []model.Role) (res any, err error)
 }
 
 type ComplexityRoot struct {
@@ -98,9 +99,23 @@ type ComplexityRoot struct {
 
 	InterventionList struct {
 		Interventions func(childComplexity int) int
+		PageInfo      func(childComplexity int) int
 		Total         func(childComplexity int) int
 	}
 
+	InterventionSearchHit struct {
+		Intervention   func(childComplexity int) int
+		Rank           func(childComplexity int) int
+		Snippet        func(childComplexity int) int
+		TitleHighlight func(childComplexity int) int
+	}
+
+	InterventionSearchResults struct {
+		PageInfo func(childComplexity int) int
+		Results  func(childComplexity int) int
+		Total    func(childComplexity int) int
+	}
+
 	MessageResponse struct {
 		Message func(childComplexity int) int
 	}
@@ -109,14 +124,23 @@ type ComplexityRoot struct {
 		CancelIntervention   func(childComplexity int, id string, reason *string) int
 		CompleteIntervention func(childComplexity int, id string, notes *string) int
 		CreateInterventions  func(childComplexity int, input model.CreateInterventionsInput) int
+		ReopenIntervention   func(childComplexity int, id string, reason *string) int
+		StartIntervention    func(childComplexity int, id string) int
 		UpdateIntervention   func(childComplexity int, id string, updates model.UpdateInterventionInput) int
 	}
 
+	PageInfo struct {
+		EndCursor   func(childComplexity int) int
+		HasNextPage func(childComplexity int) int
+	}
+
 	Query struct {
-		BarrierCounts func(childComplexity int, filters *model.BarrierFilters) int
-		Health        func(childComplexity int) int
-		Intervention  func(childComplexity int, id string) int
-		Interventions func(childComplexity int, filters *model.InterventionFilters) int
+		BarrierCounts       func(childComplexity int, filters *model.BarrierFilters) int
+		Health              func(childComplexity int) int
+		Intervention        func(childComplexity int, id string) int
+		Interventions       func(childComplexity int, filters *model.InterventionFilters, first *int, after *string, sortBy *model.InterventionSortField, sortDirection *model.SortDirection) int
+		SearchInterventions func(childComplexity int, query string, filters *model.InterventionFilters, first *int, after *string) int
+		Workload            func(childComplexity int) int
 	}
 
 	User struct {
@@ -128,6 +152,18 @@ type ComplexityRoot struct {
 		UpdatedAt func(childComplexity int) int
 		Username  func(childComplexity int) int
 	}
+
+	WorkloadCount struct {
+		Completed func(childComplexity int) int
+		Name      func(childComplexity int) int
+		Open      func(childComplexity int) int
+		Overdue   func(childComplexity int) int
+	}
+
+	WorkloadResponse struct {
+		ByAssignee func(childComplexity int) int
+		ByTeam     func(childComplexity int) int
+	}
 }
 
 type MutationResolver interface {
@@ -135,12 +171,16 @@ type MutationResolver interface {
 	UpdateIntervention(ctx context.Context, id string, updates model.UpdateInterventionInput) (*model.MessageResponse, error)
 	CompleteIntervention(ctx context.Context, id string, notes *string) (*model.MessageResponse, error)
 	CancelIntervention(ctx context.Context, id string, reason *string) (*model.MessageResponse, error)
+	StartIntervention(ctx context.Context, id string) (*model.MessageResponse, error)
+	ReopenIntervention(ctx context.Context, id string, reason *string) (*model.MessageResponse, error)
 }
 type QueryResolver interface {
 	Health(ctx context.Context) (*string, error)
 	Intervention(ctx context.Context, id string) (*model.Intervention, error)
-	Interventions(ctx context.Context, filters *model.InterventionFilters) (*model.InterventionList, error)
+	Interventions(ctx context.Context, filters *model.InterventionFilters, first *int, after *string, sortBy *model.InterventionSortField, sortDirection *model.SortDirection) (*model.InterventionList, error)
+	SearchInterventions(ctx context.Context, query string, filters *model.InterventionFilters, first *int, after *string) (*model.InterventionSearchResults, error)
 	BarrierCounts(ctx context.Context, filters *model.BarrierFilters) (*model.BarrierResponse, error)
+	Workload(ctx context.Context) (*model.WorkloadResponse, error)
 }
 
 type executableSchema struct {
@@ -366,6 +406,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.InterventionList.Interventions(childComplexity), true
+	case "InterventionList.pageInfo":
+		if e.complexity.InterventionList.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.InterventionList.PageInfo(childComplexity), true
 	case "InterventionList.total":
 		if e.complexity.InterventionList.Total == nil {
 			break
@@ -373,6 +419,50 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.InterventionList.Total(childComplexity), true
 
+	case "InterventionSearchHit.intervention":
+		if e.complexity.InterventionSearchHit.Intervention == nil {
+			break
+		}
+
+		return e.complexity.InterventionSearchHit.Intervention(childComplexity), true
+	case "InterventionSearchHit.rank":
+		if e.complexity.InterventionSearchHit.Rank == nil {
+			break
+		}
+
+		return e.complexity.InterventionSearchHit.Rank(childComplexity), true
+	case "InterventionSearchHit.snippet":
+		if e.complexity.InterventionSearchHit.Snippet == nil {
+			break
+		}
+
+		return e.complexity.InterventionSearchHit.Snippet(childComplexity), true
+	case "InterventionSearchHit.titleHighlight":
+		if e.complexity.InterventionSearchHit.TitleHighlight == nil {
+			break
+		}
+
+		return e.complexity.InterventionSearchHit.TitleHighlight(childComplexity), true
+
+	case "InterventionSearchResults.pageInfo":
+		if e.complexity.InterventionSearchResults.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.InterventionSearchResults.PageInfo(childComplexity), true
+	case "InterventionSearchResults.results":
+		if e.complexity.InterventionSearchResults.Results == nil {
+			break
+		}
+
+		return e.complexity.InterventionSearchResults.Results(childComplexity), true
+	case "InterventionSearchResults.total":
+		if e.complexity.InterventionSearchResults.Total == nil {
+			break
+		}
+
+		return e.complexity.InterventionSearchResults.Total(childComplexity), true
+
 	case "MessageResponse.message":
 		if e.complexity.MessageResponse.Message == nil {
 			break
@@ -413,6 +503,28 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Mutation.CreateInterventions(childComplexity, args["input"].(model.CreateInterventionsInput)), true
+	case "Mutation.reopenIntervention":
+		if e.complexity.Mutation.ReopenIntervention == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_reopenIntervention_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ReopenIntervention(childComplexity, args["id"].(string), args["reason"].(*string)), true
+	case "Mutation.startIntervention":
+		if e.complexity.Mutation.StartIntervention == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_startIntervention_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.StartIntervention(childComplexity, args["id"].(string)), true
 	case "Mutation.updateIntervention":
 		if e.complexity.Mutation.UpdateIntervention == nil {
 			break
@@ -425,6 +537,19 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Mutation.UpdateIntervention(childComplexity, args["id"].(string), args["updates"].(model.UpdateInterventionInput)), true
 
+	case "PageInfo.endCursor":
+		if e.complexity.PageInfo.EndCursor == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.EndCursor(childComplexity), true
+	case "PageInfo.hasNextPage":
+		if e.complexity.PageInfo.HasNextPage == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.HasNextPage(childComplexity), true
+
 	case "Query.barrierCounts":
 		if e.complexity.Query.BarrierCounts == nil {
 			break
@@ -463,7 +588,24 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.Interventions(childComplexity, args["filters"].(*model.InterventionFilters)), true
+		return e.complexity.Query.Interventions(childComplexity, args["filters"].(*model.InterventionFilters), args["first"].(*int), args["after"].(*string), args["sortBy"].(*model.InterventionSortField), args["sortDirection"].(*model.SortDirection)), true
+	case "Query.searchInterventions":
+		if e.complexity.Query.SearchInterventions == nil {
+			break
+		}
+
+		args, err := ec.field_Query_searchInterventions_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.SearchInterventions(childComplexity, args["query"].(string), args["filters"].(*model.InterventionFilters), args["first"].(*int), args["after"].(*string)), true
+	case "Query.workload":
+		if e.complexity.Query.Workload == nil {
+			break
+		}
+
+		return e.complexity.Query.Workload(childComplexity), true
 
 	case "User.createdAt":
 		if e.complexity.User.CreatedAt == nil {
@@ -508,6 +650,44 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.User.Username(childComplexity), true
 
+	case "WorkloadCount.completed":
+		if e.complexity.WorkloadCount.Completed == nil {
+			break
+		}
+
+		return e.complexity.WorkloadCount.Completed(childComplexity), true
+	case "WorkloadCount.name":
+		if e.complexity.WorkloadCount.Name == nil {
+			break
+		}
+
+		return e.complexity.WorkloadCount.Name(childComplexity), true
+	case "WorkloadCount.open":
+		if e.complexity.WorkloadCount.Open == nil {
+			break
+		}
+
+		return e.complexity.WorkloadCount.Open(childComplexity), true
+	case "WorkloadCount.overdue":
+		if e.complexity.WorkloadCount.Overdue == nil {
+			break
+		}
+
+		return e.complexity.WorkloadCount.Overdue(childComplexity), true
+
+	case "WorkloadResponse.byAssignee":
+		if e.complexity.WorkloadResponse.ByAssignee == nil {
+			break
+		}
+
+		return e.complexity.WorkloadResponse.ByAssignee(childComplexity), true
+	case "WorkloadResponse.byTeam":
+		if e.complexity.WorkloadResponse.ByTeam == nil {
+			break
+		}
+
+		return e.complexity.WorkloadResponse.ByTeam(childComplexity), true
+
 	}
 	return 0, false
 }
@@ -618,18 +798,67 @@ func (ec *executionContext) introspectType(name string) (*introspection.Type, er
 }
 
 var sources = []*ast.Source{
-	{Name: "../../schema.graphqls", Input: `type Query {
+	{Name: "../../schema.graphqls", Input: `"""
+Limits a field to callers whose token carries one of the roles. It rejects
+other roles before the resolver runs; the services still apply the full
+policy, including which interventions the caller may touch. The roles of
+each field are those the authorization policy grants its action.
+"""
+directive @hasRole(roles: [Role!]!) on FIELD_DEFINITION
+
+type Query {
   health: String
   intervention(id: ID!): Intervention
-  interventions(filters: InterventionFilters): InterventionList!
+    @hasRole(roles: [patient_navigator, nurse_navigator, social_worker, registered_dietitian, navigator_admin, patient])
+  interventions(
+    filters: InterventionFilters
+    first: Int
+    after: String
+    sortBy: InterventionSortField
+    sortDirection: SortDirection
+  ): InterventionList!
+    @hasRole(roles: [patient_navigator, nurse_navigator, social_worker, registered_dietitian, navigator_admin, patient])
+  """
+  Full-text search over titles, descriptions, notes, problems and referral
+  reasons, most relevant first. The query accepts quoted phrases, OR, and a
+  leading - to exclude a word.
+  """
+  searchInterventions(
+    query: String!
+    filters: InterventionFilters
+    first: Int
+    after: String
+  ): InterventionSearchResults!
+    @hasRole(roles: [patient_navigator, nurse_navigator, social_worker, registered_dietitian, navigator_admin, patient])
   barrierCounts(filters: BarrierFilters): BarrierResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, navigator_admin])
+  "Open, overdue and completed interventions per assignee and per team."
+  workload: WorkloadResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, navigator_admin])
 }
 
 type Mutation {
   createInterventions(input: CreateInterventionsInput!): CreateInterventionsResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, navigator_admin])
   updateIntervention(id: ID!, updates: UpdateInterventionInput!): MessageResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, social_worker, registered_dietitian, navigator_admin])
   completeIntervention(id: ID!, notes: String): MessageResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, social_worker, registered_dietitian, navigator_admin])
   cancelIntervention(id: ID!, reason: String): MessageResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, navigator_admin])
+  startIntervention(id: ID!): MessageResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, social_worker, registered_dietitian, navigator_admin])
+  reopenIntervention(id: ID!, reason: String): MessageResponse!
+    @hasRole(roles: [patient_navigator, nurse_navigator, navigator_admin])
+}
+
+enum Role {
+  patient
+  patient_navigator
+  social_worker
+  navigator_admin
+  nurse_navigator
+  registered_dietitian
 }
 
 enum InterventionType {
@@ -657,6 +886,19 @@ enum InterventionStatus {
   cancelled
 }
 
+enum InterventionSortField {
+  created_at
+  updated_at
+  due_at
+  priority
+  status
+}
+
+enum SortDirection {
+  asc
+  desc
+}
+
 type Intervention {
   id: ID!
   tenantId: String!
@@ -693,19 +935,39 @@ type User {
 
 input InterventionFilters {
   status: InterventionStatus
+  statuses: [InterventionStatus!]
   type: InterventionType
+  types: [InterventionType!]
   assignedTeam: String
+  assignedTo: String
+  "Only interventions assigned to the signed-in user."
+  assignedToMe: Boolean
+  "Only interventions with neither an assignee nor a team."
+  unassigned: Boolean
+  "Only open interventions whose due date has passed."
+  overdue: Boolean
   patientId: String
   screeningId: String
   screeningIds: [String!]
   createdBy: String
   createdByIds: [String!]
+  "RFC 3339 timestamps; After is inclusive, Before exclusive."
+  dueAfter: String
+  dueBefore: String
+  createdAfter: String
+  createdBefore: String
+  "Only interventions listing at least one of these problems."
+  problemsAny: [String!]
+  "Only interventions listing every one of these problems."
+  problemsAll: [String!]
 }
 
 input BarrierFilters {
+  "Calendar year of creation, in UTC."
   year: String
-  disease: String
+  disease: String @deprecated(reason: "Interventions do not record a disease, so this filter is ignored.")
   type: String
+  "Month of creation, 1 to 12 with year or YYYY-MM on its own."
   month: String
   createdBy: String
   createdByIds: [String!]
@@ -743,6 +1005,27 @@ input UpdateInterventionInput {
 type InterventionList {
   interventions: [Intervention!]!
   total: Int!
+  pageInfo: PageInfo!
+}
+
+type InterventionSearchResults {
+  results: [InterventionSearchHit!]!
+  total: Int!
+  pageInfo: PageInfo!
+}
+
+type InterventionSearchHit {
+  intervention: Intervention!
+  rank: Float!
+  "The title with matching words wrapped in <mark> tags."
+  titleHighlight: String!
+  "Matching fragments of the description, notes, problems and referral reasons."
+  snippet: String
+}
+
+type PageInfo {
+  endCursor: String
+  hasNextPage: Boolean!
 }
 
 type CreateInterventionsResponse {
@@ -771,6 +1054,21 @@ type BarrierSubtype {
   barrierCount: Int!
 }
 
+type WorkloadResponse {
+  byAssignee: [WorkloadCount!]!
+  byTeam: [WorkloadCount!]!
+}
+
+type WorkloadCount {
+  "The assignee or team, or null for unassigned interventions."
+  name: String
+  "Pending and in-progress interventions."
+  open: Int!
+  "Open interventions due before today (UTC)."
+  overdue: Int!
+  completed: Int!
+}
+
 type MessageResponse {
   message: String!
 }
@@ -782,6 +1080,17 @@ var parsedSchema = gqlparser.MustLoadSchema(sources...)
 
 // region    ***************************** args.gotpl *****************************
 
+func (ec *executionContext) dir_hasRole_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "roles", ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ)
+	if err != nil {
+		return nil, err
+	}
+	args["roles"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_cancelIntervention_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -825,6 +1134,33 @@ func (ec *executionContext) field_Mutation_createInterventions_args(ctx context.
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_reopenIntervention_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "reason", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["reason"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_startIntervention_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_updateIntervention_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -882,6 +1218,52 @@ func (ec *executionContext) field_Query_interventions_args(ctx context.Context,
 		return nil, err
 	}
 	args["filters"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "first", ec.unmarshalOInt2ᚖint)
+	if err != nil {
+		return nil, err
+	}
+	args["first"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "after", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "sortBy", ec.unmarshalOInterventionSortField2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSortField)
+	if err != nil {
+		return nil, err
+	}
+	args["sortBy"] = arg3
+	arg4, err := graphql.ProcessArgField(ctx, rawArgs, "sortDirection", ec.unmarshalOSortDirection2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐSortDirection)
+	if err != nil {
+		return nil, err
+	}
+	args["sortDirection"] = arg4
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_searchInterventions_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "query", ec.unmarshalNString2string)
+	if err != nil {
+		return nil, err
+	}
+	args["query"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "filters", ec.unmarshalOInterventionFilters2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionFilters)
+	if err != nil {
+		return nil, err
+	}
+	args["filters"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "first", ec.unmarshalOInt2ᚖint)
+	if err != nil {
+		return nil, err
+	}
+	args["first"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "after", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg3
 	return args, nil
 }
 
@@ -2003,105 +2385,439 @@ func (ec *executionContext) fieldContext_InterventionList_total(_ context.Contex
 	return fc, nil
 }
 
-func (ec *executionContext) _MessageResponse_message(ctx context.Context, field graphql.CollectedField, obj *model.MessageResponse) (ret graphql.Marshaler) {
+func (ec *executionContext) _InterventionList_pageInfo(ctx context.Context, field graphql.CollectedField, obj *model.InterventionList) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_MessageResponse_message,
+		ec.fieldContext_InterventionList_pageInfo,
 		func(ctx context.Context) (any, error) {
-			return obj.Message, nil
+			return obj.PageInfo, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNPageInfo2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐPageInfo,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_MessageResponse_message(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_InterventionList_pageInfo(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "MessageResponse",
+		Object:     "InterventionList",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "endCursor":
+				return ec.fieldContext_PageInfo_endCursor(ctx, field)
+			case "hasNextPage":
+				return ec.fieldContext_PageInfo_hasNextPage(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_createInterventions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _InterventionSearchHit_intervention(ctx context.Context, field graphql.CollectedField, obj *model.InterventionSearchHit) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Mutation_createInterventions,
+		ec.fieldContext_InterventionSearchHit_intervention,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Mutation().CreateInterventions(ctx, fc.Args["input"].(model.CreateInterventionsInput))
+			return obj.Intervention, nil
 		},
 		nil,
-		ec.marshalNCreateInterventionsResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐCreateInterventionsResponse,
+		ec.marshalNIntervention2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐIntervention,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Mutation_createInterventions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_InterventionSearchHit_intervention(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "InterventionSearchHit",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "interventionIds":
-				return ec.fieldContext_CreateInterventionsResponse_interventionIds(ctx, field)
-			case "createdTasks":
-				return ec.fieldContext_CreateInterventionsResponse_createdTasks(ctx, field)
+			case "id":
+				return ec.fieldContext_Intervention_id(ctx, field)
+			case "tenantId":
+				return ec.fieldContext_Intervention_tenantId(ctx, field)
+			case "patientId":
+				return ec.fieldContext_Intervention_patientId(ctx, field)
+			case "screeningId":
+				return ec.fieldContext_Intervention_screeningId(ctx, field)
+			case "type":
+				return ec.fieldContext_Intervention_type(ctx, field)
+			case "title":
+				return ec.fieldContext_Intervention_title(ctx, field)
+			case "description":
+				return ec.fieldContext_Intervention_description(ctx, field)
+			case "status":
+				return ec.fieldContext_Intervention_status(ctx, field)
+			case "priority":
+				return ec.fieldContext_Intervention_priority(ctx, field)
+			case "createdBy":
+				return ec.fieldContext_Intervention_createdBy(ctx, field)
+			case "assignedTo":
+				return ec.fieldContext_Intervention_assignedTo(ctx, field)
+			case "assignedTeam":
+				return ec.fieldContext_Intervention_assignedTeam(ctx, field)
+			case "dueAt":
+				return ec.fieldContext_Intervention_dueAt(ctx, field)
+			case "completedAt":
+				return ec.fieldContext_Intervention_completedAt(ctx, field)
+			case "linkedTaskId":
+				return ec.fieldContext_Intervention_linkedTaskId(ctx, field)
+			case "referralReasons":
+				return ec.fieldContext_Intervention_referralReasons(ctx, field)
+			case "problems":
+				return ec.fieldContext_Intervention_problems(ctx, field)
+			case "notes":
+				return ec.fieldContext_Intervention_notes(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Intervention_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_Intervention_updatedAt(ctx, field)
+			case "user":
+				return ec.fieldContext_Intervention_user(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type CreateInterventionsResponse", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Intervention", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_createInterventions_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_updateIntervention(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _InterventionSearchHit_rank(ctx context.Context, field graphql.CollectedField, obj *model.InterventionSearchHit) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Mutation_updateIntervention,
+		ec.fieldContext_InterventionSearchHit_rank,
 		func(ctx context.Context) (any, error) {
-			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Mutation().UpdateIntervention(ctx, fc.Args["id"].(string), fc.Args["updates"].(model.UpdateInterventionInput))
+			return obj.Rank, nil
 		},
 		nil,
-		ec.marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse,
+		ec.marshalNFloat2float64,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Mutation_updateIntervention(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_InterventionSearchHit_rank(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "InterventionSearchHit",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _InterventionSearchHit_titleHighlight(ctx context.Context, field graphql.CollectedField, obj *model.InterventionSearchHit) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_InterventionSearchHit_titleHighlight,
+		func(ctx context.Context) (any, error) {
+			return obj.TitleHighlight, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_InterventionSearchHit_titleHighlight(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "InterventionSearchHit",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _InterventionSearchHit_snippet(ctx context.Context, field graphql.CollectedField, obj *model.InterventionSearchHit) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_InterventionSearchHit_snippet,
+		func(ctx context.Context) (any, error) {
+			return obj.Snippet, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_InterventionSearchHit_snippet(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "InterventionSearchHit",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _InterventionSearchResults_results(ctx context.Context, field graphql.CollectedField, obj *model.InterventionSearchResults) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_InterventionSearchResults_results,
+		func(ctx context.Context) (any, error) {
+			return obj.Results, nil
+		},
+		nil,
+		ec.marshalNInterventionSearchHit2ᚕᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSearchHitᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_InterventionSearchResults_results(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "InterventionSearchResults",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "intervention":
+				return ec.fieldContext_InterventionSearchHit_intervention(ctx, field)
+			case "rank":
+				return ec.fieldContext_InterventionSearchHit_rank(ctx, field)
+			case "titleHighlight":
+				return ec.fieldContext_InterventionSearchHit_titleHighlight(ctx, field)
+			case "snippet":
+				return ec.fieldContext_InterventionSearchHit_snippet(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type InterventionSearchHit", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _InterventionSearchResults_total(ctx context.Context, field graphql.CollectedField, obj *model.InterventionSearchResults) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_InterventionSearchResults_total,
+		func(ctx context.Context) (any, error) {
+			return obj.Total, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_InterventionSearchResults_total(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "InterventionSearchResults",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _InterventionSearchResults_pageInfo(ctx context.Context, field graphql.CollectedField, obj *model.InterventionSearchResults) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_InterventionSearchResults_pageInfo,
+		func(ctx context.Context) (any, error) {
+			return obj.PageInfo, nil
+		},
+		nil,
+		ec.marshalNPageInfo2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐPageInfo,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_InterventionSearchResults_pageInfo(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "InterventionSearchResults",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "endCursor":
+				return ec.fieldContext_PageInfo_endCursor(ctx, field)
+			case "hasNextPage":
+				return ec.fieldContext_PageInfo_hasNextPage(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _MessageResponse_message(ctx context.Context, field graphql.CollectedField, obj *model.MessageResponse) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_MessageResponse_message,
+		func(ctx context.Context) (any, error) {
+			return obj.Message, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_MessageResponse_message(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "MessageResponse",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_createInterventions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_createInterventions,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Mutation().CreateInterventions(ctx, fc.Args["input"].(model.CreateInterventionsInput))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.CreateInterventionsResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.CreateInterventionsResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNCreateInterventionsResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐCreateInterventionsResponse,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_createInterventions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "interventionIds":
+				return ec.fieldContext_CreateInterventionsResponse_interventionIds(ctx, field)
+			case "createdTasks":
+				return ec.fieldContext_CreateInterventionsResponse_createdTasks(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type CreateInterventionsResponse", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_createInterventions_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_updateIntervention(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_updateIntervention,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Mutation().UpdateIntervention(ctx, fc.Args["id"].(string), fc.Args["updates"].(model.UpdateInterventionInput))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "social_worker", "registered_dietitian", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_updateIntervention(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "message":
@@ -2134,7 +2850,25 @@ func (ec *executionContext) _Mutation_completeIntervention(ctx context.Context,
 			fc := graphql.GetFieldContext(ctx)
 			return ec.resolvers.Mutation().CompleteIntervention(ctx, fc.Args["id"].(string), fc.Args["notes"].(*string))
 		},
-		nil,
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "social_worker", "registered_dietitian", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
 		ec.marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse,
 		true,
 		true,
@@ -2179,7 +2913,25 @@ func (ec *executionContext) _Mutation_cancelIntervention(ctx context.Context, fi
 			fc := graphql.GetFieldContext(ctx)
 			return ec.resolvers.Mutation().CancelIntervention(ctx, fc.Args["id"].(string), fc.Args["reason"].(*string))
 		},
-		nil,
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
 		ec.marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse,
 		true,
 		true,
@@ -2214,6 +2966,190 @@ func (ec *executionContext) fieldContext_Mutation_cancelIntervention(ctx context
 	return fc, nil
 }
 
+func (ec *executionContext) _Mutation_startIntervention(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_startIntervention,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Mutation().StartIntervention(ctx, fc.Args["id"].(string))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "social_worker", "registered_dietitian", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_startIntervention(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "message":
+				return ec.fieldContext_MessageResponse_message(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type MessageResponse", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_startIntervention_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_reopenIntervention(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_reopenIntervention,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Mutation().ReopenIntervention(ctx, fc.Args["id"].(string), fc.Args["reason"].(*string))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.MessageResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_reopenIntervention(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "message":
+				return ec.fieldContext_MessageResponse_message(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type MessageResponse", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_reopenIntervention_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *model.PageInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PageInfo_endCursor,
+		func(ctx context.Context) (any, error) {
+			return obj.EndCursor, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_endCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *model.PageInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PageInfo_hasNextPage,
+		func(ctx context.Context) (any, error) {
+			return obj.HasNextPage, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_hasNextPage(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query_health(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -2253,7 +3189,25 @@ func (ec *executionContext) _Query_intervention(ctx context.Context, field graph
 			fc := graphql.GetFieldContext(ctx)
 			return ec.resolvers.Query().Intervention(ctx, fc.Args["id"].(string))
 		},
-		nil,
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "social_worker", "registered_dietitian", "navigator_admin", "patient"})
+				if err != nil {
+					var zeroVal *model.Intervention
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.Intervention
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
 		ec.marshalOIntervention2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐIntervention,
 		true,
 		false,
@@ -2311,7 +3265,74 @@ func (ec *executionContext) fieldContext_Query_intervention(ctx context.Context,
 			case "user":
 				return ec.fieldContext_Intervention_user(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Intervention", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Intervention", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_intervention_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_interventions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_interventions,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Interventions(ctx, fc.Args["filters"].(*model.InterventionFilters), fc.Args["first"].(*int), fc.Args["after"].(*string), fc.Args["sortBy"].(*model.InterventionSortField), fc.Args["sortDirection"].(*model.SortDirection))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "social_worker", "registered_dietitian", "navigator_admin", "patient"})
+				if err != nil {
+					var zeroVal *model.InterventionList
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.InterventionList
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNInterventionList2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionList,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_interventions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "interventions":
+				return ec.fieldContext_InterventionList_interventions(ctx, field)
+			case "total":
+				return ec.fieldContext_InterventionList_total(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_InterventionList_pageInfo(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type InterventionList", field.Name)
 		},
 	}
 	defer func() {
@@ -2321,31 +3342,49 @@ func (ec *executionContext) fieldContext_Query_intervention(ctx context.Context,
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_intervention_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_interventions_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_interventions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Query_searchInterventions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_Query_interventions,
+		ec.fieldContext_Query_searchInterventions,
 		func(ctx context.Context) (any, error) {
 			fc := graphql.GetFieldContext(ctx)
-			return ec.resolvers.Query().Interventions(ctx, fc.Args["filters"].(*model.InterventionFilters))
+			return ec.resolvers.Query().SearchInterventions(ctx, fc.Args["query"].(string), fc.Args["filters"].(*model.InterventionFilters), fc.Args["first"].(*int), fc.Args["after"].(*string))
 		},
-		nil,
-		ec.marshalNInterventionList2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionList,
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "social_worker", "registered_dietitian", "navigator_admin", "patient"})
+				if err != nil {
+					var zeroVal *model.InterventionSearchResults
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.InterventionSearchResults
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNInterventionSearchResults2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSearchResults,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_Query_interventions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_searchInterventions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -2353,12 +3392,14 @@ func (ec *executionContext) fieldContext_Query_interventions(ctx context.Context
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "interventions":
-				return ec.fieldContext_InterventionList_interventions(ctx, field)
+			case "results":
+				return ec.fieldContext_InterventionSearchResults_results(ctx, field)
 			case "total":
-				return ec.fieldContext_InterventionList_total(ctx, field)
+				return ec.fieldContext_InterventionSearchResults_total(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_InterventionSearchResults_pageInfo(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type InterventionList", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type InterventionSearchResults", field.Name)
 		},
 	}
 	defer func() {
@@ -2368,7 +3409,7 @@ func (ec *executionContext) fieldContext_Query_interventions(ctx context.Context
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_interventions_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_searchInterventions_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
@@ -2385,7 +3426,25 @@ func (ec *executionContext) _Query_barrierCounts(ctx context.Context, field grap
 			fc := graphql.GetFieldContext(ctx)
 			return ec.resolvers.Query().BarrierCounts(ctx, fc.Args["filters"].(*model.BarrierFilters))
 		},
-		nil,
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.BarrierResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.BarrierResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
 		ec.marshalNBarrierResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐBarrierResponse,
 		true,
 		true,
@@ -2422,6 +3481,59 @@ func (ec *executionContext) fieldContext_Query_barrierCounts(ctx context.Context
 	return fc, nil
 }
 
+func (ec *executionContext) _Query_workload(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_workload,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Query().Workload(ctx)
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				roles, err := ec.unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx, []any{"patient_navigator", "nurse_navigator", "navigator_admin"})
+				if err != nil {
+					var zeroVal *model.WorkloadResponse
+					return zeroVal, err
+				}
+				if ec.directives.HasRole == nil {
+					var zeroVal *model.WorkloadResponse
+					return zeroVal, errors.New("directive hasRole is not implemented")
+				}
+				return ec.directives.HasRole(ctx, nil, directive0, roles)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNWorkloadResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadResponse,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_workload(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "byAssignee":
+				return ec.fieldContext_WorkloadResponse_byAssignee(ctx, field)
+			case "byTeam":
+				return ec.fieldContext_WorkloadResponse_byTeam(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type WorkloadResponse", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -2553,31 +3665,205 @@ func (ec *executionContext) fieldContext_User_id(_ context.Context, field graphq
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _User_tenantId(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_User_tenantId,
+		func(ctx context.Context) (any, error) {
+			return obj.TenantID, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_User_tenantId(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "User",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _User_email(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_User_email,
+		func(ctx context.Context) (any, error) {
+			return obj.Email, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_User_email(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "User",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _User_username(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_User_username,
+		func(ctx context.Context) (any, error) {
+			return obj.Username, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_User_username(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "User",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _User_role(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_User_role,
+		func(ctx context.Context) (any, error) {
+			return obj.Role, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_User_role(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "User",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _User_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_User_createdAt,
+		func(ctx context.Context) (any, error) {
+			return obj.CreatedAt, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_User_createdAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "User",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _User_updatedAt(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_User_updatedAt,
+		func(ctx context.Context) (any, error) {
+			return obj.UpdatedAt, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_User_updatedAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "User",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _User_tenantId(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+func (ec *executionContext) _WorkloadCount_name(ctx context.Context, field graphql.CollectedField, obj *model.WorkloadCount) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_User_tenantId,
+		ec.fieldContext_WorkloadCount_name,
 		func(ctx context.Context) (any, error) {
-			return obj.TenantID, nil
+			return obj.Name, nil
 		},
 		nil,
-		ec.marshalNString2string,
-		true,
+		ec.marshalOString2ᚖstring,
 		true,
+		false,
 	)
 }
 
-func (ec *executionContext) fieldContext_User_tenantId(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WorkloadCount_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "User",
+		Object:     "WorkloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2588,146 +3874,166 @@ func (ec *executionContext) fieldContext_User_tenantId(_ context.Context, field
 	return fc, nil
 }
 
-func (ec *executionContext) _User_email(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+func (ec *executionContext) _WorkloadCount_open(ctx context.Context, field graphql.CollectedField, obj *model.WorkloadCount) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_User_email,
+		ec.fieldContext_WorkloadCount_open,
 		func(ctx context.Context) (any, error) {
-			return obj.Email, nil
+			return obj.Open, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNInt2int,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_User_email(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WorkloadCount_open(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "User",
+		Object:     "WorkloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _User_username(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+func (ec *executionContext) _WorkloadCount_overdue(ctx context.Context, field graphql.CollectedField, obj *model.WorkloadCount) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_User_username,
+		ec.fieldContext_WorkloadCount_overdue,
 		func(ctx context.Context) (any, error) {
-			return obj.Username, nil
+			return obj.Overdue, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNInt2int,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_User_username(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WorkloadCount_overdue(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "User",
+		Object:     "WorkloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _User_role(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+func (ec *executionContext) _WorkloadCount_completed(ctx context.Context, field graphql.CollectedField, obj *model.WorkloadCount) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_User_role,
+		ec.fieldContext_WorkloadCount_completed,
 		func(ctx context.Context) (any, error) {
-			return obj.Role, nil
+			return obj.Completed, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNInt2int,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_User_role(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WorkloadCount_completed(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "User",
+		Object:     "WorkloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _User_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+func (ec *executionContext) _WorkloadResponse_byAssignee(ctx context.Context, field graphql.CollectedField, obj *model.WorkloadResponse) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_User_createdAt,
+		ec.fieldContext_WorkloadResponse_byAssignee,
 		func(ctx context.Context) (any, error) {
-			return obj.CreatedAt, nil
+			return obj.ByAssignee, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNWorkloadCount2ᚕᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadCountᚄ,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_User_createdAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WorkloadResponse_byAssignee(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "User",
+		Object:     "WorkloadResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "name":
+				return ec.fieldContext_WorkloadCount_name(ctx, field)
+			case "open":
+				return ec.fieldContext_WorkloadCount_open(ctx, field)
+			case "overdue":
+				return ec.fieldContext_WorkloadCount_overdue(ctx, field)
+			case "completed":
+				return ec.fieldContext_WorkloadCount_completed(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type WorkloadCount", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _User_updatedAt(ctx context.Context, field graphql.CollectedField, obj *model.User) (ret graphql.Marshaler) {
+func (ec *executionContext) _WorkloadResponse_byTeam(ctx context.Context, field graphql.CollectedField, obj *model.WorkloadResponse) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
 		ec.OperationContext,
 		field,
-		ec.fieldContext_User_updatedAt,
+		ec.fieldContext_WorkloadResponse_byTeam,
 		func(ctx context.Context) (any, error) {
-			return obj.UpdatedAt, nil
+			return obj.ByTeam, nil
 		},
 		nil,
-		ec.marshalNString2string,
+		ec.marshalNWorkloadCount2ᚕᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadCountᚄ,
 		true,
 		true,
 	)
 }
 
-func (ec *executionContext) fieldContext_User_updatedAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_WorkloadResponse_byTeam(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "User",
+		Object:     "WorkloadResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "name":
+				return ec.fieldContext_WorkloadCount_name(ctx, field)
+			case "open":
+				return ec.fieldContext_WorkloadCount_open(ctx, field)
+			case "overdue":
+				return ec.fieldContext_WorkloadCount_overdue(ctx, field)
+			case "completed":
+				return ec.fieldContext_WorkloadCount_completed(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type WorkloadCount", field.Name)
 		},
 	}
 	return fc, nil
@@ -4303,7 +5609,7 @@ func (ec *executionContext) unmarshalInputInterventionFilters(ctx context.Contex
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"status", "type", "assignedTeam", "patientId", "screeningId", "screeningIds", "createdBy", "createdByIds"}
+	fieldsInOrder := [...]string{"status", "statuses", "type", "types", "assignedTeam", "assignedTo", "assignedToMe", "unassigned", "overdue", "patientId", "screeningId", "screeningIds", "createdBy", "createdByIds", "dueAfter", "dueBefore", "createdAfter", "createdBefore", "problemsAny", "problemsAll"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -4317,6 +5623,13 @@ func (ec *executionContext) unmarshalInputInterventionFilters(ctx context.Contex
 				return it, err
 			}
 			it.Status = data
+		case "statuses":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("statuses"))
+			data, err := ec.unmarshalOInterventionStatus2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionStatusᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Statuses = data
 		case "type":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalOInterventionType2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionType(ctx, v)
@@ -4324,6 +5637,13 @@ func (ec *executionContext) unmarshalInputInterventionFilters(ctx context.Contex
 				return it, err
 			}
 			it.Type = data
+		case "types":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("types"))
+			data, err := ec.unmarshalOInterventionType2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionTypeᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Types = data
 		case "assignedTeam":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("assignedTeam"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
@@ -4331,6 +5651,34 @@ func (ec *executionContext) unmarshalInputInterventionFilters(ctx context.Contex
 				return it, err
 			}
 			it.AssignedTeam = data
+		case "assignedTo":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("assignedTo"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.AssignedTo = data
+		case "assignedToMe":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("assignedToMe"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.AssignedToMe = data
+		case "unassigned":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("unassigned"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Unassigned = data
+		case "overdue":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("overdue"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Overdue = data
 		case "patientId":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("patientId"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
@@ -4366,6 +5714,48 @@ func (ec *executionContext) unmarshalInputInterventionFilters(ctx context.Contex
 				return it, err
 			}
 			it.CreatedByIds = data
+		case "dueAfter":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("dueAfter"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.DueAfter = data
+		case "dueBefore":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("dueBefore"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.DueBefore = data
+		case "createdAfter":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("createdAfter"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.CreatedAfter = data
+		case "createdBefore":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("createdBefore"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.CreatedBefore = data
+		case "problemsAny":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("problemsAny"))
+			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ProblemsAny = data
+		case "problemsAll":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("problemsAll"))
+			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ProblemsAll = data
 		}
 	}
 
@@ -4880,6 +6270,111 @@ func (ec *executionContext) _InterventionList(ctx context.Context, sel ast.Selec
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "pageInfo":
+			out.Values[i] = ec._InterventionList_pageInfo(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var interventionSearchHitImplementors = []string{"InterventionSearchHit"}
+
+func (ec *executionContext) _InterventionSearchHit(ctx context.Context, sel ast.SelectionSet, obj *model.InterventionSearchHit) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, interventionSearchHitImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("InterventionSearchHit")
+		case "intervention":
+			out.Values[i] = ec._InterventionSearchHit_intervention(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "rank":
+			out.Values[i] = ec._InterventionSearchHit_rank(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "titleHighlight":
+			out.Values[i] = ec._InterventionSearchHit_titleHighlight(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "snippet":
+			out.Values[i] = ec._InterventionSearchHit_snippet(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var interventionSearchResultsImplementors = []string{"InterventionSearchResults"}
+
+func (ec *executionContext) _InterventionSearchResults(ctx context.Context, sel ast.SelectionSet, obj *model.InterventionSearchResults) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, interventionSearchResultsImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("InterventionSearchResults")
+		case "results":
+			out.Values[i] = ec._InterventionSearchResults_results(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "total":
+			out.Values[i] = ec._InterventionSearchResults_total(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "pageInfo":
+			out.Values[i] = ec._InterventionSearchResults_pageInfo(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -4960,32 +6455,87 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Mutation")
-		case "createInterventions":
-			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
-				return ec._Mutation_createInterventions(ctx, field)
-			})
-			if out.Values[i] == graphql.Null {
-				out.Invalids++
-			}
-		case "updateIntervention":
-			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
-				return ec._Mutation_updateIntervention(ctx, field)
-			})
-			if out.Values[i] == graphql.Null {
-				out.Invalids++
-			}
-		case "completeIntervention":
-			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
-				return ec._Mutation_completeIntervention(ctx, field)
-			})
-			if out.Values[i] == graphql.Null {
-				out.Invalids++
-			}
-		case "cancelIntervention":
-			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
-				return ec._Mutation_cancelIntervention(ctx, field)
-			})
+			out.Values[i] = graphql.MarshalString("Mutation")
+		case "createInterventions":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_createInterventions(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "updateIntervention":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_updateIntervention(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "completeIntervention":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_completeIntervention(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "cancelIntervention":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_cancelIntervention(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "startIntervention":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_startIntervention(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "reopenIntervention":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_reopenIntervention(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var pageInfoImplementors = []string{"PageInfo"}
+
+func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *model.PageInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, pageInfoImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PageInfo")
+		case "endCursor":
+			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
+		case "hasNextPage":
+			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
@@ -5090,6 +6640,28 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "searchInterventions":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_searchInterventions(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "barrierCounts":
 			field := field
@@ -5112,6 +6684,28 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "workload":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_workload(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "__type":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
@@ -5213,6 +6807,101 @@ func (ec *executionContext) _User(ctx context.Context, sel ast.SelectionSet, obj
 	return out
 }
 
+var workloadCountImplementors = []string{"WorkloadCount"}
+
+func (ec *executionContext) _WorkloadCount(ctx context.Context, sel ast.SelectionSet, obj *model.WorkloadCount) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, workloadCountImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("WorkloadCount")
+		case "name":
+			out.Values[i] = ec._WorkloadCount_name(ctx, field, obj)
+		case "open":
+			out.Values[i] = ec._WorkloadCount_open(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "overdue":
+			out.Values[i] = ec._WorkloadCount_overdue(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "completed":
+			out.Values[i] = ec._WorkloadCount_completed(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var workloadResponseImplementors = []string{"WorkloadResponse"}
+
+func (ec *executionContext) _WorkloadResponse(ctx context.Context, sel ast.SelectionSet, obj *model.WorkloadResponse) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, workloadResponseImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("WorkloadResponse")
+		case "byAssignee":
+			out.Values[i] = ec._WorkloadResponse_byAssignee(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "byTeam":
+			out.Values[i] = ec._WorkloadResponse_byTeam(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
@@ -5895,6 +7584,74 @@ func (ec *executionContext) marshalNInterventionList2ᚖgithubᚗcomᚋlambdaᚋ
 	return ec._InterventionList(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNInterventionSearchHit2ᚕᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSearchHitᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.InterventionSearchHit) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNInterventionSearchHit2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSearchHit(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNInterventionSearchHit2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSearchHit(ctx context.Context, sel ast.SelectionSet, v *model.InterventionSearchHit) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._InterventionSearchHit(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNInterventionSearchResults2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSearchResults(ctx context.Context, sel ast.SelectionSet, v model.InterventionSearchResults) graphql.Marshaler {
+	return ec._InterventionSearchResults(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNInterventionSearchResults2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSearchResults(ctx context.Context, sel ast.SelectionSet, v *model.InterventionSearchResults) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._InterventionSearchResults(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNInterventionStatus2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionStatus(ctx context.Context, v any) (model.InterventionStatus, error) {
 	var res model.InterventionStatus
 	err := res.UnmarshalGQL(v)
@@ -5915,18 +7672,97 @@ func (ec *executionContext) marshalNInterventionType2githubᚗcomᚋlambdaᚋapp
 	return v
 }
 
-func (ec *executionContext) marshalNMessageResponse2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse(ctx context.Context, sel ast.SelectionSet, v model.MessageResponse) graphql.Marshaler {
-	return ec._MessageResponse(ctx, sel, &v)
-}
+func (ec *executionContext) marshalNMessageResponse2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse(ctx context.Context, sel ast.SelectionSet, v model.MessageResponse) graphql.Marshaler {
+	return ec._MessageResponse(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse(ctx context.Context, sel ast.SelectionSet, v *model.MessageResponse) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._MessageResponse(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNPageInfo2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v *model.PageInfo) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._PageInfo(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNRole2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRole(ctx context.Context, v any) (model.Role, error) {
+	var res model.Role
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNRole2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRole(ctx context.Context, sel ast.SelectionSet, v model.Role) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx context.Context, v any) ([]model.Role, error) {
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]model.Role, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNRole2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRole(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalNRole2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRoleᚄ(ctx context.Context, sel ast.SelectionSet, v []model.Role) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNRole2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐRole(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
 
-func (ec *executionContext) marshalNMessageResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐMessageResponse(ctx context.Context, sel ast.SelectionSet, v *model.MessageResponse) graphql.Marshaler {
-	if v == nil {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
 		}
-		return graphql.Null
 	}
-	return ec._MessageResponse(ctx, sel, v)
+
+	return ret
 }
 
 func (ec *executionContext) unmarshalNString2string(ctx context.Context, v any) (string, error) {
@@ -5980,6 +7816,74 @@ func (ec *executionContext) unmarshalNUpdateInterventionInput2githubᚗcomᚋlam
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) marshalNWorkloadCount2ᚕᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadCountᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.WorkloadCount) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNWorkloadCount2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadCount(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNWorkloadCount2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadCount(ctx context.Context, sel ast.SelectionSet, v *model.WorkloadCount) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._WorkloadCount(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNWorkloadResponse2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadResponse(ctx context.Context, sel ast.SelectionSet, v model.WorkloadResponse) graphql.Marshaler {
+	return ec._WorkloadResponse(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNWorkloadResponse2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐWorkloadResponse(ctx context.Context, sel ast.SelectionSet, v *model.WorkloadResponse) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._WorkloadResponse(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalN__Directive2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐDirective(ctx context.Context, sel ast.SelectionSet, v introspection.Directive) graphql.Marshaler {
 	return ec.___Directive(ctx, sel, &v)
 }
@@ -6271,6 +8175,24 @@ func (ec *executionContext) marshalOBoolean2ᚖbool(ctx context.Context, sel ast
 	return res
 }
 
+func (ec *executionContext) unmarshalOInt2ᚖint(ctx context.Context, v any) (*int, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := graphql.UnmarshalInt(v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOInt2ᚖint(ctx context.Context, sel ast.SelectionSet, v *int) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	_ = sel
+	_ = ctx
+	res := graphql.MarshalInt(*v)
+	return res
+}
+
 func (ec *executionContext) marshalOIntervention2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐIntervention(ctx context.Context, sel ast.SelectionSet, v *model.Intervention) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -6286,6 +8208,87 @@ func (ec *executionContext) unmarshalOInterventionFilters2ᚖgithubᚗcomᚋlamb
 	return &res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalOInterventionSortField2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSortField(ctx context.Context, v any) (*model.InterventionSortField, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(model.InterventionSortField)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOInterventionSortField2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionSortField(ctx context.Context, sel ast.SelectionSet, v *model.InterventionSortField) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) unmarshalOInterventionStatus2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionStatusᚄ(ctx context.Context, v any) ([]model.InterventionStatus, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]model.InterventionStatus, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNInterventionStatus2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionStatus(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalOInterventionStatus2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionStatusᚄ(ctx context.Context, sel ast.SelectionSet, v []model.InterventionStatus) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNInterventionStatus2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionStatus(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) unmarshalOInterventionStatus2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionStatus(ctx context.Context, v any) (*model.InterventionStatus, error) {
 	if v == nil {
 		return nil, nil
@@ -6302,6 +8305,71 @@ func (ec *executionContext) marshalOInterventionStatus2ᚖgithubᚗcomᚋlambda
 	return v
 }
 
+func (ec *executionContext) unmarshalOInterventionType2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionTypeᚄ(ctx context.Context, v any) ([]model.InterventionType, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]model.InterventionType, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNInterventionType2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionType(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalOInterventionType2ᚕgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []model.InterventionType) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNInterventionType2githubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionType(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) unmarshalOInterventionType2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐInterventionType(ctx context.Context, v any) (*model.InterventionType, error) {
 	if v == nil {
 		return nil, nil
@@ -6318,6 +8386,22 @@ func (ec *executionContext) marshalOInterventionType2ᚖgithubᚗcomᚋlambdaᚋ
 	return v
 }
 
+func (ec *executionContext) unmarshalOSortDirection2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐSortDirection(ctx context.Context, v any) (*model.SortDirection, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(model.SortDirection)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOSortDirection2ᚖgithubᚗcomᚋlambdaᚋappsᚋsubgraphᚑinterventionᚋgraphᚋmodelᚐSortDirection(ctx context.Context, sel ast.SelectionSet, v *model.SortDirection) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) unmarshalOString2ᚕstringᚄ(ctx context.Context, v any) ([]string, error) {
 	if v == nil {
 		return nil, nil
@@ -16,9 +16,11 @@ type BarrierCount struct {
 }
 
 type BarrierFilters struct {
-	Year         *string  `json:"year,omitempty"`
-	Disease      *string  `json:"disease,omitempty"`
-	Type         *string  `json:"type,omitempty"`
+	// Calendar year of creation, in UTC.
+	Year    *string `json:"year,omitempty"`
+	Disease *string `json:"disease,omitempty"`
+	Type    *string `json:"type,omitempty"`
+	// Month of creation, 1 to 12 with year or YYYY-MM on its own.
 	Month        *string  `json:"month,omitempty"`
 	CreatedBy    *string  `json:"createdBy,omitempty"`
 	CreatedByIds []string `json:"createdByIds,omitempty"`
@@ -77,14 +79,32 @@ type Intervention struct {
 }
 
 type InterventionFilters struct {
-	Status       *InterventionStatus `json:"status,omitempty"`
-	Type         *InterventionType   `json:"type,omitempty"`
-	AssignedTeam *string             `json:"assignedTeam,omitempty"`
-	PatientID    *string             `json:"patientId,omitempty"`
-	ScreeningID  *string             `json:"screeningId,omitempty"`
-	ScreeningIds []string            `json:"screeningIds,omitempty"`
-	CreatedBy    *string             `json:"createdBy,omitempty"`
-	CreatedByIds []string            `json:"createdByIds,omitempty"`
+	Status       *InterventionStatus  `json:"status,omitempty"`
+	Statuses     []InterventionStatus `json:"statuses,omitempty"`
+	Type         *InterventionType    `json:"type,omitempty"`
+	Types        []InterventionType   `json:"types,omitempty"`
+	AssignedTeam *string              `json:"assignedTeam,omitempty"`
+	AssignedTo   *string              `json:"assignedTo,omitempty"`
+	// Only interventions assigned to the signed-in user.
+	AssignedToMe *bool `json:"assignedToMe,omitempty"`
+	// Only interventions with neither an assignee nor a team.
+	Unassigned *bool `json:"unassigned,omitempty"`
+	// Only open interventions whose due date has passed.
+	Overdue      *bool    `json:"overdue,omitempty"`
+	PatientID    *string  `json:"patientId,omitempty"`
+	ScreeningID  *string  `json:"screeningId,omitempty"`
+	ScreeningIds []string `json:"screeningIds,omitempty"`
+	CreatedBy    *string  `json:"createdBy,omitempty"`
+	CreatedByIds []string `json:"createdByIds,omitempty"`
+	// RFC 3339 timestamps; After is inclusive, Before exclusive.
+	DueAfter      *string `json:"dueAfter,omitempty"`
+	DueBefore     *string `json:"dueBefore,omitempty"`
+	CreatedAfter  *string `json:"createdAfter,omitempty"`
+	CreatedBefore *string `json:"createdBefore,omitempty"`
+	// Only interventions listing at least one of these problems.
+	ProblemsAny []string `json:"problemsAny,omitempty"`
+	// Only interventions listing every one of these problems.
+	ProblemsAll []string `json:"problemsAll,omitempty"`
 }
 
 type InterventionItemInput struct {
@@ -103,6 +123,22 @@ type InterventionItemInput struct {
 type InterventionList struct {
 	Interventions []*Intervention `json:"interventions"`
 	Total         int             `json:"total"`
+	PageInfo      *PageInfo       `json:"pageInfo"`
+}
+
+type InterventionSearchHit struct {
+	Intervention *Intervention `json:"intervention"`
+	Rank         float64       `json:"rank"`
+	// The title with matching words wrapped in <mark> tags.
+	TitleHighlight string `json:"titleHighlight"`
+	// Matching fragments of the description, notes, problems and referral reasons.
+	Snippet *string `json:"snippet,omitempty"`
+}
+
+type InterventionSearchResults struct {
+	Results  []*InterventionSearchHit `json:"results"`
+	Total    int                      `json:"total"`
+	PageInfo *PageInfo                `json:"pageInfo"`
 }
 
 type MessageResponse struct {
@@ -112,6 +148,11 @@ type MessageResponse struct {
 type Mutation struct {
 }
 
+type PageInfo struct {
+	EndCursor   *string `json:"endCursor,omitempty"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
 type Query struct {
 }
 
@@ -133,6 +174,82 @@ type User struct {
 	UpdatedAt string `json:"updatedAt"`
 }
 
+type WorkloadCount struct {
+	// The assignee or team, or null for unassigned interventions.
+	Name *string `json:"name,omitempty"`
+	// Pending and in-progress interventions.
+	Open int `json:"open"`
+	// Open interventions due before today (UTC).
+	Overdue   int `json:"overdue"`
+	Completed int `json:"completed"`
+}
+
+type WorkloadResponse struct {
+	ByAssignee []*WorkloadCount `json:"byAssignee"`
+	ByTeam     []*WorkloadCount `json:"byTeam"`
+}
+
+type InterventionSortField string
+
+const (
+	InterventionSortFieldCreatedAt InterventionSortField = "created_at"
+	InterventionSortFieldUpdatedAt InterventionSortField = "updated_at"
+	InterventionSortFieldDueAt     InterventionSortField = "due_at"
+	InterventionSortFieldPriority  InterventionSortField = "priority"
+	InterventionSortFieldStatus    InterventionSortField = "status"
+)
+
+var AllInterventionSortField = []InterventionSortField{
+	InterventionSortFieldCreatedAt,
+	InterventionSortFieldUpdatedAt,
+	InterventionSortFieldDueAt,
+	InterventionSortFieldPriority,
+	InterventionSortFieldStatus,
+}
+
+func (e InterventionSortField) IsValid() bool {
+	switch e {
+	case InterventionSortFieldCreatedAt, InterventionSortFieldUpdatedAt, InterventionSortFieldDueAt, InterventionSortFieldPriority, InterventionSortFieldStatus:
+		return true
+	}
+	return false
+}
+
+func (e InterventionSortField) String() string {
+	return string(e)
+}
+
+func (e *InterventionSortField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = InterventionSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid InterventionSortField", str)
+	}
+	return nil
+}
+
+func (e InterventionSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *InterventionSortField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e InterventionSortField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type InterventionStatus string
 
 const (
@@ -272,3 +389,121 @@ func (e InterventionType) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type Role string
+
+const (
+	RolePatient             Role = "patient"
+	RolePatientNavigator    Role = "patient_navigator"
+	RoleSocialWorker        Role = "social_worker"
+	RoleNavigatorAdmin      Role = "navigator_admin"
+	RoleNurseNavigator      Role = "nurse_navigator"
+	RoleRegisteredDietitian Role = "registered_dietitian"
+)
+
+var AllRole = []Role{
+	RolePatient,
+	RolePatientNavigator,
+	RoleSocialWorker,
+	RoleNavigatorAdmin,
+	RoleNurseNavigator,
+	RoleRegisteredDietitian,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RolePatient, RolePatientNavigator, RoleSocialWorker, RoleNavigatorAdmin, RoleNurseNavigator, RoleRegisteredDietitian:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "asc"
+	SortDirectionDesc SortDirection = "desc"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SortDirection) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SortDirection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
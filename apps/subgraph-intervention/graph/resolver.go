@@ -10,4 +10,7 @@ import (
 
 type Resolver struct {
 	InterventionService *service.InterventionService
+	// QueryService reads the read-side projection, which holds the search
+	// index and the dashboard counts.
+	QueryService *service.InterventionQueryService
 }
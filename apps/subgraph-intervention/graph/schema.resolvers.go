@@ -6,19 +6,25 @@ package graph
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lambda/apps/subgraph-intervention/graph/generated"
 	"github.com/lambda/apps/subgraph-intervention/graph/model"
+	"github.com/lambda/internal/auth"
 	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
 	"github.com/lambda/internal/service"
 )
 
 // CreateInterventions is the resolver for the createInterventions field.
 func (r *mutationResolver) CreateInterventions(ctx context.Context, input model.CreateInterventionsInput) (*model.CreateInterventionsResponse, error) {
-	// Get tenantID and userID from context (should be set by middleware)
-	tenantID := "test-tenant" // TODO: get from context
-	userID := "test-user"     // TODO: get from context
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, userID := principal.TenantID, principal.UserID
 
 	// Convert GraphQL input to service request
 	req := &service.CreateInterventionsRequest{
@@ -83,7 +89,11 @@ func (r *mutationResolver) CreateInterventions(ctx context.Context, input model.
 
 // UpdateIntervention is the resolver for the updateIntervention field.
 func (r *mutationResolver) UpdateIntervention(ctx context.Context, id string, updates model.UpdateInterventionInput) (*model.MessageResponse, error) {
-	tenantID := "test-tenant" // TODO: get from context
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
 
 	updatesMap := make(map[string]interface{})
 	if updates.AssignedTo != nil {
@@ -102,7 +112,7 @@ func (r *mutationResolver) UpdateIntervention(ctx context.Context, id string, up
 		updatesMap["problems"] = updates.Problems
 	}
 
-	err := r.InterventionService.UpdateIntervention(ctx, tenantID, id, updatesMap)
+	err = r.InterventionService.UpdateIntervention(ctx, tenantID, id, updatesMap)
 	if err != nil {
 		return nil, err
 	}
@@ -113,14 +123,18 @@ func (r *mutationResolver) UpdateIntervention(ctx context.Context, id string, up
 
 // CompleteIntervention is the resolver for the completeIntervention field.
 func (r *mutationResolver) CompleteIntervention(ctx context.Context, id string, notes *string) (*model.MessageResponse, error) {
-	tenantID := "test-tenant" // TODO: get from context
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
 
 	notesStr := ""
 	if notes != nil {
 		notesStr = *notes
 	}
 
-	err := r.InterventionService.CompleteIntervention(ctx, tenantID, id, notesStr)
+	err = r.InterventionService.CompleteIntervention(ctx, tenantID, id, notesStr)
 	if err != nil {
 		return nil, err
 	}
@@ -131,14 +145,18 @@ func (r *mutationResolver) CompleteIntervention(ctx context.Context, id string,
 
 // CancelIntervention is the resolver for the cancelIntervention field.
 func (r *mutationResolver) CancelIntervention(ctx context.Context, id string, reason *string) (*model.MessageResponse, error) {
-	tenantID := "test-tenant" // TODO: get from context
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
 
 	reasonStr := ""
 	if reason != nil {
 		reasonStr = *reason
 	}
 
-	err := r.InterventionService.CancelIntervention(ctx, tenantID, id, reasonStr)
+	err = r.InterventionService.CancelIntervention(ctx, tenantID, id, reasonStr)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +165,45 @@ func (r *mutationResolver) CancelIntervention(ctx context.Context, id string, re
 	return &model.MessageResponse{Message: msg}, nil
 }
 
+// StartIntervention is the resolver for the startIntervention field.
+func (r *mutationResolver) StartIntervention(ctx context.Context, id string) (*model.MessageResponse, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
+
+	err = r.InterventionService.StartIntervention(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := "Intervention started successfully"
+	return &model.MessageResponse{Message: msg}, nil
+}
+
+// ReopenIntervention is the resolver for the reopenIntervention field.
+func (r *mutationResolver) ReopenIntervention(ctx context.Context, id string, reason *string) (*model.MessageResponse, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
+
+	reasonStr := ""
+	if reason != nil {
+		reasonStr = *reason
+	}
+
+	err = r.InterventionService.ReopenIntervention(ctx, tenantID, id, reasonStr)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := "Intervention reopened successfully"
+	return &model.MessageResponse{Message: msg}, nil
+}
+
 // Health is the resolver for the health field.
 func (r *queryResolver) Health(ctx context.Context) (*string, error) {
 	status := "ok"
@@ -155,7 +212,11 @@ func (r *queryResolver) Health(ctx context.Context) (*string, error) {
 
 // Intervention is the resolver for the intervention field.
 func (r *queryResolver) Intervention(ctx context.Context, id string) (*model.Intervention, error) {
-	tenantID := "test-tenant" // TODO: get from context
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
 
 	intervention, err := r.InterventionService.GetInterventionByID(ctx, tenantID, id)
 	if err != nil {
@@ -166,45 +227,48 @@ func (r *queryResolver) Intervention(ctx context.Context, id string) (*model.Int
 }
 
 // Interventions is the resolver for the interventions field.
-func (r *queryResolver) Interventions(ctx context.Context, filters *model.InterventionFilters) (*model.InterventionList, error) {
-	tenantID := "test-tenant" // TODO: get from context
+func (r *queryResolver) Interventions(ctx context.Context, filters *model.InterventionFilters, first *int, after *string, sortBy *model.InterventionSortField, sortDirection *model.SortDirection) (*model.InterventionList, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
 
-	filtersMap := make(map[string]interface{})
-	if filters != nil {
-		if filters.Status != nil {
-			filtersMap["status"] = *filters.Status
-		}
-		if filters.Type != nil {
-			filtersMap["type"] = *filters.Type
-		}
-		if filters.AssignedTeam != nil {
-			filtersMap["assigned_team"] = *filters.AssignedTeam
-		}
-		if filters.PatientID != nil {
-			filtersMap["patient_id"] = *filters.PatientID
-		}
-		if filters.ScreeningID != nil {
-			filtersMap["screening_id"] = *filters.ScreeningID
-		}
-		if filters.ScreeningIds != nil {
-			filtersMap["screening_ids"] = filters.ScreeningIds
-		}
-		if filters.CreatedBy != nil {
-			filtersMap["created_by"] = *filters.CreatedBy
-		}
-		if filters.CreatedByIds != nil {
-			filtersMap["created_by_ids"] = filters.CreatedByIds
-		}
+	filter, err := convertFiltersToRepository(ctx, filters)
+	if err != nil {
+		return nil, err
 	}
 
-	interventions, err := r.InterventionService.ListInterventions(ctx, tenantID, filtersMap)
+	page := repository.PageRequest{}
+	if first != nil {
+		page.First = *first
+	}
+	if after != nil {
+		page.After = *after
+	}
+	if sortBy != nil {
+		page.SortBy = repository.SortField(*sortBy)
+	}
+	if sortDirection != nil {
+		page.Direction = repository.SortDirection(strings.ToUpper(string(*sortDirection)))
+	}
+
+	interventions, pageInfo, err := r.InterventionService.ListInterventions(ctx, tenantID, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.InterventionService.CountInterventions(ctx, tenantID, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	result := &model.InterventionList{
 		Interventions: make([]*model.Intervention, len(interventions)),
-		Total:         len(interventions),
+		Total:         int(total),
+		PageInfo:      &model.PageInfo{HasNextPage: pageInfo.HasNextPage},
+	}
+	if pageInfo.EndCursor != "" {
+		result.PageInfo.EndCursor = &pageInfo.EndCursor
 	}
 
 	for i, intervention := range interventions {
@@ -214,33 +278,73 @@ func (r *queryResolver) Interventions(ctx context.Context, filters *model.Interv
 	return result, nil
 }
 
-// BarrierCounts is the resolver for the barrierCounts field.
-func (r *queryResolver) BarrierCounts(ctx context.Context, filters *model.BarrierFilters) (*model.BarrierResponse, error) {
-	tenantID := "test-tenant" // TODO: get from context
+// SearchInterventions is the resolver for the searchInterventions field.
+func (r *queryResolver) SearchInterventions(ctx context.Context, query string, filters *model.InterventionFilters, first *int, after *string) (*model.InterventionSearchResults, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
 
-	filtersMap := make(map[string]interface{})
-	if filters != nil {
-		if filters.Year != nil {
-			filtersMap["year"] = *filters.Year
-		}
-		if filters.Disease != nil {
-			filtersMap["disease"] = *filters.Disease
-		}
-		if filters.Type != nil {
-			filtersMap["type"] = *filters.Type
-		}
-		if filters.Month != nil {
-			filtersMap["month"] = *filters.Month
-		}
-		if filters.CreatedBy != nil {
-			filtersMap["created_by"] = *filters.CreatedBy
+	filter, err := convertFiltersToRepository(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	page := repository.PageRequest{}
+	if first != nil {
+		page.First = *first
+	}
+	if after != nil {
+		page.After = *after
+	}
+
+	hits, pageInfo, err := r.QueryService.SearchInterventions(ctx, tenantID, query, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.QueryService.CountSearch(ctx, tenantID, query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.InterventionSearchResults{
+		Results:  make([]*model.InterventionSearchHit, len(hits)),
+		Total:    int(total),
+		PageInfo: &model.PageInfo{HasNextPage: pageInfo.HasNextPage},
+	}
+	if pageInfo.EndCursor != "" {
+		result.PageInfo.EndCursor = &pageInfo.EndCursor
+	}
+
+	for i, hit := range hits {
+		result.Results[i] = &model.InterventionSearchHit{
+			Intervention:   convertProjectionToModel(&hit.InterventionProjection),
+			Rank:           hit.Rank,
+			TitleHighlight: hit.TitleHighlight,
 		}
-		if filters.CreatedByIds != nil {
-			filtersMap["created_by_ids"] = filters.CreatedByIds
+		if hit.Snippet != "" {
+			result.Results[i].Snippet = &hit.Snippet
 		}
 	}
 
-	barriers, err := r.InterventionService.GetBarrierCounts(ctx, tenantID, filtersMap)
+	return result, nil
+}
+
+// BarrierCounts is the resolver for the barrierCounts field.
+func (r *queryResolver) BarrierCounts(ctx context.Context, filters *model.BarrierFilters) (*model.BarrierResponse, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
+
+	filter, err := convertBarrierFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	barriers, err := r.QueryService.GetBarrierCounts(ctx, tenantID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -268,6 +372,25 @@ func (r *queryResolver) BarrierCounts(ctx context.Context, filters *model.Barrie
 	return result, nil
 }
 
+// Workload is the resolver for the workload field.
+func (r *queryResolver) Workload(ctx context.Context) (*model.WorkloadResponse, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := principal.TenantID
+
+	workload, err := r.QueryService.GetWorkload(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WorkloadResponse{
+		ByAssignee: convertWorkloadToModel(workload.ByAssignee),
+		ByTeam:     convertWorkloadToModel(workload.ByTeam),
+	}, nil
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
@@ -278,12 +401,83 @@ type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 
 // Helper function to convert domain.Intervention to model.Intervention
+// convertFiltersToRepository merges the single-value filters into their
+// list counterparts and resolves assignedToMe to the caller.
+func convertFiltersToRepository(ctx context.Context, filters *model.InterventionFilters) (repository.InterventionFilter, error) {
+	filter := repository.InterventionFilter{}
+	if filters == nil {
+		return filter, nil
+	}
+
+	if filters.Status != nil {
+		filter.Statuses = append(filter.Statuses, domain.InterventionStatus(*filters.Status))
+	}
+	for _, status := range filters.Statuses {
+		filter.Statuses = append(filter.Statuses, domain.InterventionStatus(status))
+	}
+	if filters.Type != nil {
+		filter.Types = append(filter.Types, domain.InterventionType(*filters.Type))
+	}
+	for _, interventionType := range filters.Types {
+		filter.Types = append(filter.Types, domain.InterventionType(interventionType))
+	}
+	if filters.ScreeningID != nil {
+		filter.ScreeningIDs = append(filter.ScreeningIDs, *filters.ScreeningID)
+	}
+	filter.ScreeningIDs = append(filter.ScreeningIDs, filters.ScreeningIds...)
+	if filters.CreatedBy != nil {
+		filter.CreatedByIDs = append(filter.CreatedByIDs, *filters.CreatedBy)
+	}
+	filter.CreatedByIDs = append(filter.CreatedByIDs, filters.CreatedByIds...)
+
+	if filters.PatientID != nil {
+		filter.PatientID = *filters.PatientID
+	}
+	if filters.AssignedTeam != nil {
+		filter.AssignedTeam = *filters.AssignedTeam
+	}
+	if filters.AssignedTo != nil {
+		filter.AssignedTo = *filters.AssignedTo
+	}
+	if filters.AssignedToMe != nil && *filters.AssignedToMe {
+		principal, err := auth.PrincipalFrom(ctx)
+		if err != nil {
+			return filter, err
+		}
+		filter.AssignedTo = principal.UserID
+	}
+	filter.Unassigned = filters.Unassigned != nil && *filters.Unassigned
+	filter.Overdue = filters.Overdue != nil && *filters.Overdue
+	filter.ProblemsAny = filters.ProblemsAny
+	filter.ProblemsAll = filters.ProblemsAll
+
+	for _, field := range []struct {
+		name   string
+		value  *string
+		target **time.Time
+	}{
+		{"dueAfter", filters.DueAfter, &filter.DueAfter},
+		{"dueBefore", filters.DueBefore, &filter.DueBefore},
+		{"createdAfter", filters.CreatedAfter, &filter.CreatedAfter},
+		{"createdBefore", filters.CreatedBefore, &filter.CreatedBefore},
+	} {
+		if field.value == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, *field.value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", repository.ErrInvalidFilter, field.name)
+		}
+		*field.target = &t
+	}
+	return filter, nil
+}
 func convertInterventionToModel(i *domain.Intervention) *model.Intervention {
 	var user *model.User
 	if i.User != nil {
 		user = &model.User{
 			ID:        i.User.ID.String(),
-			TenantID:  i.User.TenantID.String(),
+			TenantID:  i.User.TenantID,
 			Email:     i.User.Email,
 			Username:  i.User.Username,
 			Role:      string(i.User.Role),
@@ -343,3 +537,69 @@ func convertInterventionToModel(i *domain.Intervention) *model.Intervention {
 		User:            user,
 	}
 }
+
+// convertProjectionToModel maps a read-side row, whose timestamps are
+// already strings, onto the GraphQL type.
+func convertProjectionToModel(p *repository.InterventionProjection) *model.Intervention {
+	return &model.Intervention{
+		ID:              p.ID,
+		TenantID:        p.TenantID,
+		PatientID:       p.PatientID,
+		ScreeningID:     p.ScreeningID,
+		Type:            model.InterventionType(p.Type),
+		Title:           p.Title,
+		Description:     p.Description,
+		Status:          model.InterventionStatus(p.Status),
+		Priority:        p.Priority,
+		CreatedBy:       p.CreatedBy,
+		AssignedTo:      p.AssignedTo,
+		AssignedTeam:    p.AssignedTeam,
+		DueAt:           p.DueAt,
+		CompletedAt:     p.CompletedAt,
+		LinkedTaskID:    p.LinkedTaskID,
+		ReferralReasons: p.ReferralReasons,
+		Problems:        p.Problems,
+		Notes:           p.Notes,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+func convertBarrierFilters(filters *model.BarrierFilters) (repository.BarrierFilter, error) {
+	var filter repository.BarrierFilter
+	if filters == nil {
+		return filter, nil
+	}
+
+	var year, month string
+	if filters.Year != nil {
+		year = *filters.Year
+	}
+	if filters.Month != nil {
+		month = *filters.Month
+	}
+	if err := filter.SetPeriod(year, month); err != nil {
+		return filter, err
+	}
+	if filters.Type != nil && *filters.Type != "" {
+		filter.Types = []domain.InterventionType{domain.InterventionType(*filters.Type)}
+	}
+	if filters.CreatedBy != nil && *filters.CreatedBy != "" {
+		filter.CreatedByIDs = append(filter.CreatedByIDs, *filters.CreatedBy)
+	}
+	filter.CreatedByIDs = append(filter.CreatedByIDs, filters.CreatedByIds...)
+	return filter, nil
+}
+func convertWorkloadToModel(counts []*domain.WorkloadCount) []*model.WorkloadCount {
+	result := make([]*model.WorkloadCount, len(counts))
+	for i, count := range counts {
+		result[i] = &model.WorkloadCount{
+			Open:      count.Open,
+			Overdue:   count.Overdue,
+			Completed: count.Completed,
+		}
+		if count.Name != "" {
+			result[i].Name = &count.Name
+		}
+	}
+	return result
+}
@@ -14,9 +14,11 @@ import (
 
 	"github.com/lambda/apps/subgraph-intervention/graph"
 	"github.com/lambda/apps/subgraph-intervention/graph/generated"
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
 	"github.com/lambda/internal/db"
-	"github.com/lambda/internal/events"
 	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/requestctx"
 	"github.com/lambda/internal/service"
 )
 
@@ -30,27 +32,37 @@ func main() {
 		port = defaultPort
 	}
 
+	verifier, err := auth.NewVerifierFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure token verification: %v", err)
+	}
+
 	dbConfig, err := db.NewDBConfig(ctx)
 	if err != nil {
 		log.Fatalf("failed to initialize database connections: %v", err)
 	}
 	defer dbConfig.Close()
 
-	streamName := getEnv("KINESIS_STREAM_NAME", "intervention-events")
-	eventPublisher := events.NewKinesisEventPublisher(dbConfig.AWS, streamName)
-
 	interventionRepo := repository.NewInterventionRepository(dbConfig.WriteDB)
+	outboxRepo := repository.NewOutboxRepository(dbConfig.WriteDB)
+	eventStoreRepo := repository.NewEventStoreRepository(dbConfig.WriteDB)
 
-	interventionService := service.NewInterventionService(interventionRepo, eventPublisher)
+	authorizer := authz.NewAuthorizer(authz.DefaultPolicy, repository.NewUserRepository(dbConfig.WriteDB))
+
+	interventionService := service.NewInterventionService(interventionRepo, outboxRepo, eventStoreRepo, authorizer)
 
 	resolver := &graph.Resolver{
 		InterventionService: interventionService,
+		QueryService:        service.NewInterventionQueryService(repository.NewInterventionProjectionRepository(dbConfig.ReadDB), authorizer),
 	}
 
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: graph.Directives(),
+	}))
 
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	http.Handle("/query", requestctx.Middleware(auth.Middleware(verifier)(srv)))
 
 	server := &http.Server{
 		Addr:    ":" + port,
@@ -79,10 +91,3 @@ func main() {
 
 	log.Println("server exited gracefully")
 }
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
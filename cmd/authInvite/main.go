@@ -3,52 +3,163 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	appdb "github.com/lambda/internal/db"
 	"github.com/lambda/internal/domain"
 	"github.com/lambda/internal/repository"
 	"github.com/lambda/internal/service"
 )
 
+var (
+	invitationService *service.InvitationService
+	verifier          *auth.Verifier
+)
+
+func init() {
+	dsn := os.Getenv("DATABASE_URL")
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to database: " + err.Error())
+	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
+	}
+	invitationService = service.NewInvitationService(repository.NewInvitationRepository(db), nil, os.Getenv("JWT_SECRET"))
+
+	verifier, err = auth.NewVerifierFromEnv()
+	if err != nil {
+		panic("failed to configure token verification: " + err.Error())
+	}
+}
+
 type InviteRequest struct {
 	Email string `json:"email"`
 	Role  string `json:"role"`
 }
 
+// InvitationResponse is an invitation as listed to navigator admins,
+// without its token.
+type InvitationResponse struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Status    string `json:"status"`
+	InvitedBy string `json:"invited_by"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+// HandleRequest serves the navigator admins' invitation routes:
+//
+//	POST /auth/invite              send an invitation
+//	GET  /auth/invite              list pending invitations, ?invitedBy= to filter
+//	POST /auth/invite/{id}/revoke  revoke an invitation
+//	POST /auth/invite/{id}/resend  resend an invitation with a new token
 func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	dsn := os.Getenv("DATABASE_URL")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		return events.APIGatewayProxyResponse{Body: "DB connection error", StatusCode: 500}, nil
+	switch request.HTTPMethod + " " + request.Resource {
+	case "POST /auth/invite":
+		return createInvitation(ctx, request)
+	case "GET /auth/invite":
+		return listInvitations(ctx, request)
+	case "POST /auth/invite/{id}/revoke":
+		invitation, err := invitationService.RevokeInvitation(ctx, request.PathParameters["id"])
+		if err != nil {
+			return errorResponse(err), nil
+		}
+		return jsonResponse(200, toResponse(invitation)), nil
+	case "POST /auth/invite/{id}/resend":
+		invitation, err := invitationService.ResendInvitation(ctx, request.PathParameters["id"])
+		if err != nil {
+			return errorResponse(err), nil
+		}
+		return jsonResponse(200, map[string]string{"token": invitation.Token}), nil
 	}
+	return events.APIGatewayProxyResponse{Body: `{"error": "Not found"}`, StatusCode: 404}, nil
+}
 
-	invitationRepo := repository.NewInvitationRepository(db)
-	invitationService := service.NewInvitationService(invitationRepo, os.Getenv("JWT_SECRET"))
-
+func createInvitation(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	var req InviteRequest
 	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
-		return events.APIGatewayProxyResponse{Body: "Invalid request", StatusCode: 400}, nil
+		return events.APIGatewayProxyResponse{Body: `{"error": "Invalid request body"}`, StatusCode: 400}, nil
 	}
 
-	// In a real application, you'd get the inviter's ID from the JWT
-	invitation := &domain.Invitation{
+	token, err := invitationService.CreateInvitation(ctx, &domain.Invitation{
 		Email: req.Email,
 		Role:  domain.Role(req.Role),
+	})
+	if err != nil {
+		return errorResponse(err), nil
 	}
+	return jsonResponse(200, map[string]string{"token": token}), nil
+}
 
-	token, err := invitationService.CreateInvitation(invitation)
+func listInvitations(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	invitations, err := invitationService.ListPendingInvitations(ctx, request.QueryStringParameters["invitedBy"])
 	if err != nil {
-		return events.APIGatewayProxyResponse{Body: "Failed to create invitation", StatusCode: 500}, nil
+		return errorResponse(err), nil
+	}
+
+	response := make([]InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		response = append(response, toResponse(invitation))
+	}
+	return jsonResponse(200, response), nil
+}
+
+func toResponse(invitation *domain.Invitation) InvitationResponse {
+	return InvitationResponse{
+		ID:        invitation.ID.String(),
+		Email:     invitation.Email,
+		Role:      string(invitation.Role),
+		Status:    string(invitation.Status),
+		InvitedBy: invitation.InvitedBy.String(),
+		ExpiresAt: invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func jsonResponse(status int, body interface{}) events.APIGatewayProxyResponse {
+	encoded, _ := json.Marshal(body)
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Body:       string(encoded),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
 	}
+}
 
-	return events.APIGatewayProxyResponse{Body: `{"token":"` + token + `"}`, StatusCode: 200}, nil
+func errorResponse(err error) events.APIGatewayProxyResponse {
+	status := 500
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		status = 400
+	case errors.Is(err, auth.ErrUnauthenticated):
+		status = 401
+	case errors.Is(err, authz.ErrForbidden):
+		status = 403
+	case errors.Is(err, service.ErrInvitationNotFound):
+		status = 404
+	case errors.Is(err, service.ErrInvitationUsed), errors.Is(err, service.ErrInvitationRevoked):
+		status = 409
+	}
+	message := err.Error()
+	if status == 500 {
+		message = "Failed to process invitation"
+	}
+	return jsonResponse(status, map[string]string{"error": message})
 }
 
 func main() {
-	lambda.Start(HandleRequest)
+	lambda.Start(auth.RequireAPIGateway(verifier, HandleRequest))
 }
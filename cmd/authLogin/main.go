@@ -29,7 +29,7 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	authService := service.NewAuthService(cfg, os.Getenv("COGNITO_USER_POOL_ID"), os.Getenv("COGNITO_CLIENT_ID"))
 
 	// This is the same as send OTP, as the custom auth flow handles both login and registration
-	resp, err := authService.StartOTPChallenge(req.Email, req.Password)
+	resp, err := authService.StartOTPChallenge(ctx, req.Email, req.Password)
 	if err != nil {
 		return events.APIGatewayProxyResponse{Body: "Failed to start OTP challenge", StatusCode: 500}, nil
 	}
@@ -3,14 +3,53 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"os"
+
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"os"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	appdb "github.com/lambda/internal/db"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
 	"github.com/lambda/internal/service"
 )
 
+var (
+	userService *service.UserService
+	verifier    *auth.Verifier
+)
+
+func init() {
+	dsn := os.Getenv("DATABASE_URL")
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to database: " + err.Error())
+	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		panic("failed to load AWS config: " + err.Error())
+	}
+	authService := service.NewAuthService(cfg, os.Getenv("COGNITO_USER_POOL_ID"), os.Getenv("COGNITO_CLIENT_ID"))
+	userService = service.NewUserService(repository.NewUserRepository(db), authService)
+
+	verifier, err = auth.NewVerifierFromEnv()
+	if err != nil {
+		panic("failed to configure token verification: " + err.Error())
+	}
+}
+
+// RegisterRequest names the person to register. TenantID and
+// NavigatorAdminID may be left out: the tenant is the caller's, and
+// navigators report to the caller.
 type RegisterRequest struct {
 	Email            string `json:"email"`
 	Password         string `json:"password"`
@@ -19,26 +58,51 @@ type RegisterRequest struct {
 	NavigatorAdminID string `json:"navigator_admin_id"`
 }
 
+// HandleRequest registers someone in the caller's tenant without an
+// invitation. For navigator admins.
 func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	var req RegisterRequest
 	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
-		return events.APIGatewayProxyResponse{Body: "Invalid request", StatusCode: 400}, nil
+		return events.APIGatewayProxyResponse{Body: `{"error": "Invalid request body"}`, StatusCode: 400}, nil
 	}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	user, err := userService.RegisterUser(ctx, req.Email, req.Password, domain.Role(req.Role), req.TenantID, req.NavigatorAdminID)
 	if err != nil {
-		return events.APIGatewayProxyResponse{Body: "AWS config error", StatusCode: 500}, nil
+		return errorResponse(err), nil
 	}
-	authService := service.NewAuthService(cfg, os.Getenv("COGNITO_USER_POOL_ID"), os.Getenv("COGNITO_CLIENT_ID"))
+	return jsonResponse(200, map[string]string{"user_id": user.ID.String()}), nil
+}
 
-	_, err = authService.CreateCognitoUser(req.Email, req.Password, req.Role, req.TenantID, req.NavigatorAdminID)
-	if err != nil {
-		return events.APIGatewayProxyResponse{Body: "Failed to create user", StatusCode: 500}, nil
+func jsonResponse(status int, body interface{}) events.APIGatewayProxyResponse {
+	encoded, _ := json.Marshal(body)
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Body:       string(encoded),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
 	}
+}
 
-	return events.APIGatewayProxyResponse{Body: "User registered successfully", StatusCode: 200}, nil
+func errorResponse(err error) events.APIGatewayProxyResponse {
+	status := 500
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		status = 400
+	case errors.Is(err, auth.ErrUnauthenticated):
+		status = 401
+	case errors.Is(err, authz.ErrForbidden):
+		status = 403
+	case errors.Is(err, service.ErrUserExists):
+		status = 409
+	}
+	message := err.Error()
+	if status == 500 {
+		message = "Failed to register user"
+	}
+	return jsonResponse(status, map[string]string{"error": message})
 }
 
 func main() {
-	lambda.Start(HandleRequest)
+	lambda.Start(auth.RequireAPIGateway(verifier, HandleRequest))
 }
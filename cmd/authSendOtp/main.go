@@ -27,7 +27,7 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	}
 	authService := service.NewAuthService(cfg, os.Getenv("COGNITO_USER_POOL_ID"), os.Getenv("COGNITO_CLIENT_ID"))
 
-	resp, err := authService.StartOTPChallenge(req.Email, req.Password)
+	resp, err := authService.StartOTPChallenge(ctx, req.Email, req.Password)
 	if err != nil {
 		return events.APIGatewayProxyResponse{Body: "Failed to start OTP challenge", StatusCode: 500}, nil
 	}
@@ -2,26 +2,91 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"os"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 
-	    "github.com/lambda/internal/service")
+	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/service"
+)
+
+type AcceptRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
 
+// HandleRequest serves the invited person, who has no account yet:
+//
+//	GET  /auth/validateInvite?token=  check an invitation and show its email and role
+//	POST /auth/acceptInvite           register with {"token", "password"} and accept
 func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	invitationService := service.NewInvitationService(nil, os.Getenv("JWT_SECRET"))
+	dsn := os.Getenv("DATABASE_URL")
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return events.APIGatewayProxyResponse{Body: "DB connection error", StatusCode: 500}, nil
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{Body: "AWS config error", StatusCode: 500}, nil
+	}
+	authService := service.NewAuthService(cfg, os.Getenv("COGNITO_USER_POOL_ID"), os.Getenv("COGNITO_CLIENT_ID"))
+	invitationService := service.NewInvitationService(repository.NewInvitationRepository(db), authService, os.Getenv("JWT_SECRET"))
+
+	if request.HTTPMethod == "POST" {
+		return acceptInvitation(ctx, invitationService, request)
+	}
 
 	tokenStr, ok := request.QueryStringParameters["token"]
 	if !ok {
 		return events.APIGatewayProxyResponse{Body: "Missing token", StatusCode: 400}, nil
 	}
 
-	token, err := invitationService.ValidateInvitationToken(tokenStr)
-	if err != nil || !token.Valid {
-		return events.APIGatewayProxyResponse{Body: "Invalid token", StatusCode: 400}, nil
+	invitation, err := invitationService.ValidateInvitationToken(ctx, tokenStr)
+	if err != nil {
+		return invitationError(err, "Failed to validate invitation"), nil
 	}
 
-	return events.APIGatewayProxyResponse{Body: "Token is valid", StatusCode: 200}, nil
+	body, _ := json.Marshal(map[string]string{"email": invitation.Email, "role": string(invitation.Role)})
+	return events.APIGatewayProxyResponse{Body: string(body), StatusCode: 200}, nil
+}
+
+// acceptInvitation registers the invited person in Cognito with the
+// invitation's email, role and tenant, and marks the invitation accepted
+// by the new user.
+func acceptInvitation(ctx context.Context, invitationService *service.InvitationService, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	var req AcceptRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil || req.Token == "" {
+		return events.APIGatewayProxyResponse{Body: "Invalid request", StatusCode: 400}, nil
+	}
+
+	invitation, err := invitationService.AcceptInvitation(ctx, req.Token, req.Password)
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		return events.APIGatewayProxyResponse{Body: err.Error(), StatusCode: 400}, nil
+	case errors.Is(err, service.ErrUserExists):
+		return events.APIGatewayProxyResponse{Body: err.Error(), StatusCode: 409}, nil
+	case err != nil:
+		return invitationError(err, "Failed to accept invitation"), nil
+	}
+
+	body, _ := json.Marshal(map[string]string{"user_id": invitation.AcceptedBy.String(), "email": invitation.Email, "role": string(invitation.Role)})
+	return events.APIGatewayProxyResponse{Body: string(body), StatusCode: 200}, nil
+}
+
+func invitationError(err error, failure string) events.APIGatewayProxyResponse {
+	switch {
+	case errors.Is(err, service.ErrInvitationExpired), errors.Is(err, service.ErrInvitationUsed), errors.Is(err, service.ErrInvitationRevoked):
+		return events.APIGatewayProxyResponse{Body: err.Error(), StatusCode: 410}
+	case errors.Is(err, service.ErrInvalidInvitation):
+		return events.APIGatewayProxyResponse{Body: "Invalid token", StatusCode: 400}
+	}
+	return events.APIGatewayProxyResponse{Body: failure, StatusCode: 500}
 }
 
 func main() {
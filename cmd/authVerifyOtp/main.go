@@ -29,7 +29,7 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	}
 	authService := service.NewAuthService(cfg, os.Getenv("COGNito_USER_POOL_ID"), os.Getenv("COGNITO_CLIENT_ID"))
 
-	authResult, err := authService.VerifyOTPChallenge(req.Email, req.OTP, req.Session)
+	authResult, err := authService.VerifyOTPChallenge(ctx, req.Email, req.OTP, req.Session)
 	if err != nil {
 		return events.APIGatewayProxyResponse{Body: "Failed to verify OTP", StatusCode: 500}, nil
 	}
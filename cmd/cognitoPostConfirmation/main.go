@@ -31,7 +31,7 @@ func HandleRequest(ctx context.Context, event CognitoEvent) error {
 	}
 
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, nil)
 
 	// In a real application, you would parse the custom attributes and create the user
 	// For now, this is a placeholder
@@ -39,7 +39,7 @@ func HandleRequest(ctx context.Context, event CognitoEvent) error {
 		Email: event.Request.UserAttributes["email"],
 	}
 
-	if err := userService.CreateUser(user); err != nil {
+	if err := userService.CreateUser(ctx, user); err != nil {
 		log.Printf("failed to create user in database: %v", err)
 		return err
 	}
@@ -3,15 +3,17 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
-	internalevents "github.com/lambda/internal/events"
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	appdb "github.com/lambda/internal/db"
 	"github.com/lambda/internal/repository"
 	"github.com/lambda/internal/service"
 )
@@ -19,6 +21,7 @@ import (
 var (
 	db                  *gorm.DB
 	interventionService *service.InterventionService
+	verifier            *auth.Verifier
 )
 
 func init() {
@@ -32,29 +35,32 @@ func init() {
 	if err != nil {
 		panic("failed to connect to database: " + err.Error())
 	}
-
-	cfg, err := config.LoadDefaultConfig(context.Background())
-	if err != nil {
-		panic("failed to load AWS config: " + err.Error())
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
 	}
 
-	streamName := getEnv("KINESIS_STREAM_NAME", "intervention-events")
-	eventPublisher := internalevents.NewKinesisEventPublisher(cfg, streamName)
-
 	interventionRepo := repository.NewInterventionRepository(db)
-	interventionService = service.NewInterventionService(interventionRepo, eventPublisher)
-}
+	outboxRepo := repository.NewOutboxRepository(db)
+	eventStoreRepo := repository.NewEventStoreRepository(db)
+	authorizer := authz.NewAuthorizer(authz.DefaultPolicy, repository.NewUserRepository(db))
+	interventionService = service.NewInterventionService(interventionRepo, outboxRepo, eventStoreRepo, authorizer)
 
-func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	tenantID := request.Headers["X-Tenant-ID"]
-	if tenantID == "" {
-		tenantID = "default-tenant"
+	verifier, err = auth.NewVerifierFromEnv()
+	if err != nil {
+		panic("failed to configure token verification: " + err.Error())
 	}
+}
 
-	userID := request.Headers["X-User-ID"]
-	if userID == "" {
-		userID = "default-user"
+func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 401,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
 	}
+	tenantID := principal.TenantID
+	userID := principal.UserID
 
 	var req service.CreateInterventionsRequest
 	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
@@ -66,6 +72,12 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 
 	resp, err := interventionService.CreateInterventions(ctx, tenantID, userID, &req)
 	if err != nil {
+		if errors.Is(err, authz.ErrForbidden) {
+			return events.APIGatewayProxyResponse{
+				StatusCode: 403,
+				Body:       `{"error": "` + err.Error() + `"}`,
+			}, nil
+		}
 		return events.APIGatewayProxyResponse{
 			StatusCode: 500,
 			Body:       `{"error": "` + err.Error() + `"}`,
@@ -82,13 +94,6 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	}, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
 func main() {
-	lambda.Start(HandleRequest)
+	lambda.Start(auth.RequireAPIGateway(verifier, HandleRequest))
 }
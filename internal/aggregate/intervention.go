@@ -0,0 +1,190 @@
+// Package aggregate rebuilds write-side state from the event store.
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/events"
+	"github.com/lib/pq"
+)
+
+var ErrNoHistory = errors.New("no events for intervention")
+
+// InterventionAggregate is an intervention rebuilt by replaying its events
+// in sequence order. Version is the sequence of the last event applied.
+type InterventionAggregate struct {
+	Intervention domain.Intervention
+	Version      int64
+}
+
+// RehydrateIntervention replays the full history of one intervention.
+func RehydrateIntervention(history []*events.DomainEvent) (*InterventionAggregate, error) {
+	if len(history) == 0 {
+		return nil, ErrNoHistory
+	}
+	aggregate := &InterventionAggregate{}
+	for _, event := range history {
+		if err := aggregate.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return aggregate, nil
+}
+
+// Apply folds one event into the aggregate. Events must arrive in sequence
+// order, starting with intervention.created.
+func (a *InterventionAggregate) Apply(event *events.DomainEvent) error {
+	if event.Sequence != a.Version+1 {
+		return fmt.Errorf("event %s has sequence %d, expected %d", event.EventID, event.Sequence, a.Version+1)
+	}
+
+	payload, err := events.DefaultRegistry.Decode(event)
+	if err != nil {
+		return err
+	}
+
+	state := &a.Intervention
+	if _, created := payload.(*events.InterventionCreatedEvent); !created && state.ID == "" {
+		return fmt.Errorf("event %s applied before intervention.created", event.EventID)
+	}
+
+	switch payload := payload.(type) {
+	case *events.InterventionCreatedEvent:
+		*state = domain.Intervention{
+			ID:              payload.InterventionID,
+			TenantID:        payload.TenantID,
+			PatientID:       payload.PatientID,
+			ScreeningID:     payload.ScreeningID,
+			Type:            payload.Type,
+			Title:           payload.Title,
+			Description:     payload.Description,
+			Status:          payload.Status,
+			Priority:        payload.Priority,
+			CreatedBy:       payload.CreatedBy,
+			AssignedTo:      payload.AssignedTo,
+			AssignedTeam:    payload.AssignedTeam,
+			DueAt:           payload.DueAt,
+			ReferralReasons: pq.StringArray(payload.ReferralReasons),
+			Problems:        pq.StringArray(payload.Problems),
+			CreatedAt:       payload.CreatedAt,
+		}
+	case *events.InterventionUpdatedEvent:
+		changes := payload.UpdatedFields
+		if changes.AssignedTo != nil {
+			state.AssignedTo = changes.AssignedTo
+		}
+		if changes.AssignedTeam != nil {
+			state.AssignedTeam = changes.AssignedTeam
+		}
+		if changes.Priority != nil {
+			state.Priority = *changes.Priority
+		}
+		if changes.Notes != nil {
+			state.Notes = changes.Notes
+		}
+		if changes.Problems != nil {
+			state.Problems = pq.StringArray(*changes.Problems)
+		}
+	case *events.InterventionCompletedEvent:
+		state.Status = domain.StatusCompleted
+		completedAt := payload.CompletedAt
+		state.CompletedAt = &completedAt
+		if payload.Notes != nil {
+			state.Notes = payload.Notes
+		}
+	case *events.InterventionCancelledEvent:
+		state.Status = domain.StatusCancelled
+		if payload.Reason != nil {
+			state.Notes = payload.Reason
+		}
+	case *events.InterventionStartedEvent:
+		state.Status = domain.StatusInProgress
+	case *events.InterventionReopenedEvent:
+		state.Status = domain.StatusPending
+		state.CompletedAt = nil
+		if payload.Reason != nil {
+			state.Notes = payload.Reason
+		}
+	default:
+		return fmt.Errorf("event %s of type %s does not apply to interventions", event.EventID, event.EventType)
+	}
+
+	a.Version = event.Sequence
+	state.Version = event.Sequence
+	state.UpdatedAt = event.Timestamp
+	return nil
+}
+
+// SnapshotEvents describes the current state of an intervention as the
+// shortest run of events that produces it, for replaying interventions that
+// have no history in the event store. The events end at the intervention's
+// version so that live events continue the sequence; rows too old to have
+// room for them are given sequence 0 and deduplicated by event ID instead.
+func SnapshotEvents(intervention *domain.Intervention) ([]*events.DomainEvent, error) {
+	var history []*events.DomainEvent
+
+	created, err := events.NewInterventionCreatedEvent(&events.InterventionCreatedEvent{
+		InterventionID:  intervention.ID,
+		TenantID:        intervention.TenantID,
+		PatientID:       intervention.PatientID,
+		ScreeningID:     intervention.ScreeningID,
+		Type:            intervention.Type,
+		Title:           intervention.Title,
+		Description:     intervention.Description,
+		Status:          intervention.Status,
+		Priority:        intervention.Priority,
+		CreatedBy:       intervention.CreatedBy,
+		AssignedTo:      intervention.AssignedTo,
+		AssignedTeam:    intervention.AssignedTeam,
+		DueAt:           intervention.DueAt,
+		ReferralReasons: intervention.ReferralReasons,
+		Problems:        intervention.Problems,
+		CreatedAt:       intervention.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history = append(history, created)
+
+	var closing *events.DomainEvent
+	switch {
+	case intervention.Status == domain.StatusCompleted && intervention.CompletedAt != nil:
+		closing, err = events.NewInterventionCompletedEvent(&events.InterventionCompletedEvent{
+			InterventionID: intervention.ID,
+			TenantID:       intervention.TenantID,
+			CompletedAt:    *intervention.CompletedAt,
+			Notes:          intervention.Notes,
+		})
+	case intervention.Status == domain.StatusCancelled:
+		closing, err = events.NewInterventionCancelledEvent(&events.InterventionCancelledEvent{
+			InterventionID: intervention.ID,
+			TenantID:       intervention.TenantID,
+			CancelledAt:    intervention.UpdatedAt,
+			Reason:         intervention.Notes,
+		})
+	case intervention.Notes != nil:
+		closing, err = events.NewInterventionUpdatedEvent(&events.InterventionUpdatedEvent{
+			InterventionID: intervention.ID,
+			TenantID:       intervention.TenantID,
+			UpdatedFields:  events.InterventionFieldChanges{Notes: intervention.Notes},
+			UpdatedAt:      intervention.UpdatedAt,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if closing != nil {
+		history = append(history, closing)
+	}
+
+	first := intervention.Version - int64(len(history)) + 1
+	for i, event := range history {
+		event.Metadata["source"] = "snapshot"
+		if first >= 1 {
+			event.Sequence = first + int64(i)
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// KeySet finds the public key a token was signed with by its key ID.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (*rsa.PublicKey, error)
+}
+
+// JWK is one RSA signing key in a JSON Web Key Set.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK describes an RSA public key for a JWKS document.
+func NewJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		KeyType:   "RSA",
+		KeyID:     kid,
+		Algorithm: "RS256",
+		Use:       "sig",
+		Modulus:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// ParseJWKS reads the RSA keys of a JWKS document. Keys of other types are
+// skipped.
+func ParseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+		if err != nil {
+			return nil, fmt.Errorf("key %s has an invalid modulus: %w", jwk.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+		if err != nil {
+			return nil, fmt.Errorf("key %s has an invalid exponent: %w", jwk.KeyID, err)
+		}
+		keys[jwk.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS has no RSA signing keys")
+	}
+	return keys, nil
+}
+
+type staticKeySet map[string]*rsa.PublicKey
+
+// LoadKeySetFile reads a JWKS document from disk once, for local
+// development and tests where no Cognito pool publishes one.
+func LoadKeySetFile(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return staticKeySet(keys), nil
+}
+
+func (s staticKeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+// minRefreshInterval limits how often an unknown key ID makes the key set
+// fetch the JWKS again, so forged key IDs cannot hammer the pool.
+const minRefreshInterval = time.Minute
+
+type remoteKeySet struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewRemoteKeySet fetches the JWKS at url on first use and again when a
+// token names a key it does not have yet, which is how Cognito key
+// rotation shows up.
+func NewRemoteKeySet(url string) KeySet {
+	return &remoteKeySet{url: url, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (s *remoteKeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if s.keys != nil && time.Since(s.fetchedAt) < minRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+	if err := s.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+func (s *remoteKeySet) fetch(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS from %s: %w", s.url, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS from %s: %s", s.url, response.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	lambdaevents "github.com/aws/aws-lambda-go/events"
+
+	"github.com/lambda/internal/requestctx"
+)
+
+// Middleware rejects requests without a valid bearer token and puts the
+// Principal on the context of the rest. It also makes the principal the
+// actor in the request's requestctx.Metadata, which is the only place the
+// actor comes from, so it must run inside requestctx.Middleware.
+// Rejections use the GraphQL error format.
+func Middleware(verifier *Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticate(r.Context(), verifier, r.Header.Get("Authorization"))
+			if err != nil {
+				writeUnauthenticated(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withActor(r.Context(), principal)))
+		})
+	}
+}
+
+// OptionalMiddleware is Middleware for servers that also serve anonymous
+// callers, such as the auth subgraph, where people sign in and accept
+// invitations before they have a token. Requests without an Authorization
+// header pass through without a Principal, so resolvers that need one get
+// ErrUnauthenticated from PrincipalFrom; a token that fails verification is
+// still rejected.
+func OptionalMiddleware(verifier *Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization := r.Header.Get("Authorization")
+			if strings.TrimSpace(authorization) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			principal, err := authenticate(r.Context(), verifier, authorization)
+			if err != nil {
+				writeUnauthenticated(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withActor(r.Context(), principal)))
+		})
+	}
+}
+
+func writeUnauthenticated(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"message":    err.Error(),
+			"extensions": map[string]string{"code": "UNAUTHENTICATED"},
+		}},
+	})
+}
+
+// APIGatewayHandler is the signature of the Lambda proxy handlers.
+type APIGatewayHandler func(ctx context.Context, request lambdaevents.APIGatewayProxyRequest) (lambdaevents.APIGatewayProxyResponse, error)
+
+// RequireAPIGateway wraps a Lambda proxy handler so it only runs for
+// requests with a valid bearer token. The handler's context carries the
+// Principal and the request's requestctx.Metadata with the principal as
+// actor.
+func RequireAPIGateway(verifier *Verifier, handler APIGatewayHandler) APIGatewayHandler {
+	return func(ctx context.Context, request lambdaevents.APIGatewayProxyRequest) (lambdaevents.APIGatewayProxyResponse, error) {
+		ctx = requestctx.With(ctx, requestctx.FromAPIGatewayRequest(request))
+		principal, err := authenticate(ctx, verifier, headerValue(request.Headers, "Authorization"))
+		if err != nil {
+			body, _ := json.Marshal(map[string]string{"error": err.Error()})
+			return lambdaevents.APIGatewayProxyResponse{
+				StatusCode: 401,
+				Body:       string(body),
+				Headers: map[string]string{
+					"Content-Type":     "application/json",
+					"WWW-Authenticate": `Bearer error="invalid_token"`,
+				},
+			}, nil
+		}
+		return handler(withActor(ctx, principal), request)
+	}
+}
+
+// authenticate verifies the token in an Authorization header, with or
+// without the Bearer scheme. Why a token failed, including a JWKS that
+// could not be fetched, is logged rather than told to the caller.
+func authenticate(ctx context.Context, verifier *Verifier, authorization string) (*Principal, error) {
+	token := strings.TrimSpace(authorization)
+	if scheme, rest, ok := strings.Cut(token, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token = strings.TrimSpace(rest)
+	}
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	principal, err := verifier.Verify(ctx, token)
+	if err != nil {
+		log.Printf("Rejected token: %v", err)
+		return nil, ErrInvalidToken
+	}
+	return principal, nil
+}
+
+func withActor(ctx context.Context, principal *Principal) context.Context {
+	metadata, _ := requestctx.From(ctx)
+	metadata.ActorID = principal.UserID
+	metadata.ActorRole = principal.Role
+	return WithPrincipal(requestctx.With(ctx, metadata), principal)
+}
+
+func headerValue(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
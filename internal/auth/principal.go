@@ -0,0 +1,47 @@
+// Package auth verifies Cognito-issued JWTs and carries the caller they
+// identify through context.Context. The GraphQL servers use Middleware and
+// the API Gateway Lambdas use RequireAPIGateway; both reject requests
+// without a valid token. OptionalMiddleware lets anonymous requests through
+// for servers that also serve callers who have not signed in.
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrUnauthenticated means the request carried no token, or the context
+	// has no principal.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrInvalidToken means a token was presented but failed verification.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Principal is the verified caller. TenantID and Role come from the
+// custom:tenant_id and custom:role attributes, UserID from sub.
+type Principal struct {
+	UserID   string
+	TenantID string
+	Role     string
+	Email    string
+	Username string
+	// TokenUse is "id" or "access".
+	TokenUse string
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// PrincipalFrom returns the verified caller, or ErrUnauthenticated when the
+// request was not authenticated.
+func PrincipalFrom(ctx context.Context) (*Principal, error) {
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+	if !ok || principal == nil {
+		return nil, ErrUnauthenticated
+	}
+	return principal, nil
+}
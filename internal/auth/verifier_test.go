@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testIssuer   = "https://cognito-idp.us-east-1.amazonaws.com/us-east-1_test"
+	testClientID = "test-client"
+	testKeyID    = "test-key"
+)
+
+// testKeys is a signing key whose public half is served from a JWKS file,
+// the way scripts/devtoken sets up local verification.
+type testKeys struct {
+	private  *rsa.PrivateKey
+	jwksFile string
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	data, err := json.Marshal(JWKS{Keys: []JWK{NewJWK(testKeyID, &private.PublicKey)}})
+	if err != nil {
+		t.Fatalf("Marshal JWKS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return &testKeys{private: private, jwksFile: path}
+}
+
+func (k *testKeys) verifier(t *testing.T) *Verifier {
+	t.Helper()
+	verifier, err := NewVerifier(Config{Issuer: testIssuer, ClientID: testClientID, JWKSFile: k.jwksFile})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	return verifier
+}
+
+func (k *testKeys) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(k.private)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func idClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":              "7f1c1a9e-4a6b-4f0e-9b7d-2f1f8c0d9a11",
+		"iss":              testIssuer,
+		"aud":              testClientID,
+		"token_use":        "id",
+		"exp":              time.Now().Add(time.Hour).Unix(),
+		"email":            "navigator@example.com",
+		"cognito:username": "navigator",
+		"custom:tenant_id": "tenant-a",
+		"custom:role":      "patient_navigator",
+	}
+}
+
+func accessClaims() jwt.MapClaims {
+	claims := idClaims()
+	delete(claims, "aud")
+	delete(claims, "cognito:username")
+	claims["token_use"] = "access"
+	claims["client_id"] = testClientID
+	claims["username"] = "navigator"
+	return claims
+}
+
+func with(claims jwt.MapClaims, changes map[string]interface{}) jwt.MapClaims {
+	for name, value := range changes {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+func TestVerifyAcceptsIDAndAccessTokens(t *testing.T) {
+	keys := newTestKeys(t)
+	verifier := keys.verifier(t)
+
+	for _, claims := range []jwt.MapClaims{idClaims(), accessClaims()} {
+		principal, err := verifier.Verify(context.Background(), keys.sign(t, claims))
+		if err != nil {
+			t.Fatalf("Verify(%s token): %v", claims["token_use"], err)
+		}
+		want := Principal{
+			UserID:   "7f1c1a9e-4a6b-4f0e-9b7d-2f1f8c0d9a11",
+			TenantID: "tenant-a",
+			Role:     "patient_navigator",
+			Email:    "navigator@example.com",
+			Username: "navigator",
+			TokenUse: claims["token_use"].(string),
+		}
+		if *principal != want {
+			t.Errorf("principal = %+v, want %+v", *principal, want)
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	keys := newTestKeys(t)
+	verifier := keys.verifier(t)
+	other := newTestKeys(t)
+
+	hs256 := jwt.NewWithClaims(jwt.SigningMethodHS256, idClaims())
+	hs256.Header["kid"] = testKeyID
+	hs256Token, err := hs256.SignedString([]byte("shared-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs512 := jwt.NewWithClaims(jwt.SigningMethodRS512, idClaims())
+	rs512.Header["kid"] = testKeyID
+	rs512Token, err := rs512.SignedString(keys.private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noneToken, err := jwt.NewWithClaims(jwt.SigningMethodNone, idClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownKid := jwt.NewWithClaims(jwt.SigningMethodRS256, idClaims())
+	unknownKid.Header["kid"] = "rotated-away"
+	unknownKidToken, err := unknownKid.SignedString(keys.private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", keys.sign(t, with(idClaims(), map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()}))},
+		{"no expiry", keys.sign(t, with(idClaims(), map[string]interface{}{"exp": nil}))},
+		{"not yet valid", keys.sign(t, with(idClaims(), map[string]interface{}{"nbf": time.Now().Add(time.Hour).Unix()}))},
+		{"wrong issuer", keys.sign(t, with(idClaims(), map[string]interface{}{"iss": "https://cognito-idp.us-east-1.amazonaws.com/us-east-1_other"}))},
+		{"no issuer", keys.sign(t, with(idClaims(), map[string]interface{}{"iss": nil}))},
+		{"id token for another audience", keys.sign(t, with(idClaims(), map[string]interface{}{"aud": "other-client"}))},
+		{"id token without audience", keys.sign(t, with(idClaims(), map[string]interface{}{"aud": nil}))},
+		{"access token for another client", keys.sign(t, with(accessClaims(), map[string]interface{}{"client_id": "other-client"}))},
+		{"access token without client_id", keys.sign(t, with(accessClaims(), map[string]interface{}{"client_id": nil}))},
+		{"refresh token_use", keys.sign(t, with(idClaims(), map[string]interface{}{"token_use": "refresh"}))},
+		{"missing token_use", keys.sign(t, with(idClaims(), map[string]interface{}{"token_use": nil}))},
+		{"missing sub", keys.sign(t, with(idClaims(), map[string]interface{}{"sub": nil}))},
+		{"missing tenant", keys.sign(t, with(idClaims(), map[string]interface{}{"custom:tenant_id": nil}))},
+		{"empty tenant", keys.sign(t, with(idClaims(), map[string]interface{}{"custom:tenant_id": ""}))},
+		{"unknown kid", unknownKidToken},
+		{"signed by another key", other.sign(t, idClaims())},
+		{"HS256", hs256Token},
+		{"RS512", rs512Token},
+		{"alg none", noneToken},
+		{"garbage", "not.a.token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token)
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("Verify = %+v, %v; want ErrInvalidToken", principal, err)
+			}
+		})
+	}
+}
+
+func TestVerifyWithoutClientIDAcceptsAnyClient(t *testing.T) {
+	keys := newTestKeys(t)
+	verifier, err := NewVerifier(Config{Issuer: testIssuer + "/", JWKSFile: keys.jwksFile})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	token := keys.sign(t, with(idClaims(), map[string]interface{}{"aud": "any-client"}))
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestRemoteKeySetFetchesJWKS(t *testing.T) {
+	keys := newTestKeys(t)
+	jwks, err := os.ReadFile(keys.jwksFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	verifier, err := NewVerifier(Config{Issuer: testIssuer, ClientID: testClientID, JWKSURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := verifier.Verify(context.Background(), keys.sign(t, idClaims())); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("JWKS fetched %d times, want once", fetches)
+	}
+
+	// An unknown key ID right after a fetch is rejected without fetching
+	// again.
+	unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, idClaims())
+	unknown.Header["kid"] = "forged"
+	token, _ := unknown.SignedString(keys.private)
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify with unknown kid: %v", err)
+	}
+	if fetches != 1 {
+		t.Errorf("JWKS fetched %d times after an unknown kid, want once", fetches)
+	}
+}
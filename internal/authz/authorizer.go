@@ -0,0 +1,160 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
+)
+
+// ErrForbidden means the caller is authenticated but the policy does not
+// let their role do what they asked.
+var ErrForbidden = errors.New("forbidden")
+
+// TeamDirectory finds the navigators a navigator admin oversees.
+// repository.UserRepository implements it.
+type TeamDirectory interface {
+	NavigatorIDs(ctx context.Context, tenantID, adminID string) ([]string, error)
+}
+
+// Authorizer applies a Policy to the Principal on the context.
+type Authorizer struct {
+	policy Policy
+	team   TeamDirectory
+}
+
+func NewAuthorizer(policy Policy, team TeamDirectory) *Authorizer {
+	return &Authorizer{policy: policy, team: team}
+}
+
+// Allow checks that the caller's role may perform action on some
+// interventions, and returns the caller. Use it for actions that do not
+// target an existing intervention, such as creating one.
+func (a *Authorizer) Allow(ctx context.Context, action Action) (*auth.Principal, error) {
+	principal, _, err := a.scope(ctx, action)
+	return principal, err
+}
+
+// Resource is what the policy looks at on an intervention.
+type Resource struct {
+	ID         string
+	PatientID  string
+	CreatedBy  string
+	AssignedTo string
+}
+
+// ResourceOf describes a write-model intervention.
+func ResourceOf(intervention *domain.Intervention) Resource {
+	resource := Resource{ID: intervention.ID, PatientID: intervention.PatientID, CreatedBy: intervention.CreatedBy}
+	if intervention.AssignedTo != nil {
+		resource.AssignedTo = *intervention.AssignedTo
+	}
+	return resource
+}
+
+// ProjectionResource describes a read-model intervention.
+func ProjectionResource(projection *repository.InterventionProjection) Resource {
+	resource := Resource{ID: projection.ID, PatientID: projection.PatientID, CreatedBy: projection.CreatedBy}
+	if projection.AssignedTo != nil {
+		resource.AssignedTo = *projection.AssignedTo
+	}
+	return resource
+}
+
+// Authorize checks that the caller may perform action on the intervention.
+func (a *Authorizer) Authorize(ctx context.Context, action Action, intervention Resource) error {
+	principal, scope, err := a.scope(ctx, action)
+	if err != nil {
+		return err
+	}
+
+	switch scope {
+	case AnyInTenant:
+		return nil
+	case OwnRecord:
+		if intervention.PatientID == principal.UserID {
+			return nil
+		}
+	case AssignedWork:
+		if intervention.AssignedTo == principal.UserID {
+			return nil
+		}
+	default:
+		userIDs, err := a.userIDs(ctx, principal, scope)
+		if err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			if intervention.CreatedBy == userID || intervention.AssignedTo == userID {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s may not %s intervention %s", ErrForbidden, roleName(principal.Role), action, intervention.ID)
+}
+
+// ScopeFilter narrows filter to the interventions the caller may perform
+// action on, so lists and counts only include those.
+func (a *Authorizer) ScopeFilter(ctx context.Context, action Action, filter *repository.InterventionFilter) error {
+	principal, scope, err := a.scope(ctx, action)
+	if err != nil {
+		return err
+	}
+
+	switch scope {
+	case AnyInTenant:
+		return nil
+	case OwnRecord:
+		if filter.PatientID != "" && filter.PatientID != principal.UserID {
+			return fmt.Errorf("%w: %s may only see their own interventions", ErrForbidden, roleName(principal.Role))
+		}
+		filter.PatientID = principal.UserID
+		return nil
+	case AssignedWork:
+		filter.VisibleAssignedTo = []string{principal.UserID}
+		return nil
+	default:
+		userIDs, err := a.userIDs(ctx, principal, scope)
+		if err != nil {
+			return err
+		}
+		filter.VisibleTo = userIDs
+		return nil
+	}
+}
+
+func (a *Authorizer) scope(ctx context.Context, action Action) (*auth.Principal, Scope, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	scope, ok := a.policy.scopeFor(action, domain.Role(principal.Role))
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s may not %s interventions", ErrForbidden, roleName(principal.Role), action)
+	}
+	return principal, scope, nil
+}
+
+// userIDs is the caller alone for OwnWork, and the caller with the
+// navigators they administer for TeamWork.
+func (a *Authorizer) userIDs(ctx context.Context, principal *auth.Principal, scope Scope) ([]string, error) {
+	userIDs := []string{principal.UserID}
+	if scope != TeamWork || a.team == nil {
+		return userIDs, nil
+	}
+	navigatorIDs, err := a.team.NavigatorIDs(ctx, principal.TenantID, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load navigators of %s: %w", principal.UserID, err)
+	}
+	return append(userIDs, navigatorIDs...), nil
+}
+
+func roleName(role string) string {
+	if role == "" {
+		return "a user without a role"
+	}
+	return role
+}
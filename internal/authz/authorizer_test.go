@@ -0,0 +1,199 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
+)
+
+const (
+	caller    = "caller"
+	teammate  = "teammate"
+	colleague = "colleague"
+)
+
+// team puts teammate on every navigator admin's team.
+type team struct{ err error }
+
+func (d team) NavigatorIDs(ctx context.Context, tenantID, adminID string) ([]string, error) {
+	return []string{teammate}, d.err
+}
+
+func principalContext(role domain.Role) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: caller, TenantID: "tenant-a", Role: string(role)})
+}
+
+// resources are interventions of the caller's tenant, named by how they
+// relate to the caller.
+var resources = map[string]Resource{
+	"created by the caller":    {ID: "created", PatientID: "patient-1", CreatedBy: caller, AssignedTo: colleague},
+	"assigned to the caller":   {ID: "assigned", PatientID: "patient-1", CreatedBy: colleague, AssignedTo: caller},
+	"created by a teammate":    {ID: "team-created", PatientID: "patient-1", CreatedBy: teammate, AssignedTo: colleague},
+	"assigned to a teammate":   {ID: "team-assigned", PatientID: "patient-1", CreatedBy: colleague, AssignedTo: teammate},
+	"of someone else":          {ID: "other", PatientID: "patient-1", CreatedBy: colleague, AssignedTo: colleague},
+	"unassigned":               {ID: "unassigned", PatientID: "patient-1", CreatedBy: colleague},
+	"of the caller as patient": {ID: "record", PatientID: caller, CreatedBy: colleague, AssignedTo: colleague},
+}
+
+// reach lists, per scope, the resources it covers.
+var reach = map[Scope][]string{
+	AnyInTenant:  {"created by the caller", "assigned to the caller", "created by a teammate", "assigned to a teammate", "of someone else", "unassigned", "of the caller as patient"},
+	TeamWork:     {"created by the caller", "assigned to the caller", "created by a teammate", "assigned to a teammate"},
+	OwnWork:      {"created by the caller", "assigned to the caller"},
+	AssignedWork: {"assigned to the caller"},
+	OwnRecord:    {"of the caller as patient"},
+}
+
+func TestAuthorize(t *testing.T) {
+	authorizer := NewAuthorizer(DefaultPolicy, team{})
+	for _, action := range actions {
+		for _, role := range roles {
+			scope, granted := wantScopes[action][role]
+			allowed := map[string]bool{}
+			if granted {
+				for _, name := range reach[scope] {
+					allowed[name] = true
+				}
+			}
+			for name, resource := range resources {
+				t.Run(string(role)+" "+string(action)+" "+name, func(t *testing.T) {
+					err := authorizer.Authorize(principalContext(role), action, resource)
+					if allowed[name] {
+						if err != nil {
+							t.Fatalf("Authorize: %v", err)
+						}
+						return
+					}
+					if !errors.Is(err, ErrForbidden) {
+						t.Fatalf("Authorize = %v, want ErrForbidden", err)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	authorizer := NewAuthorizer(DefaultPolicy, team{})
+	for _, action := range actions {
+		for _, role := range roles {
+			_, granted := wantScopes[action][role]
+			principal, err := authorizer.Allow(principalContext(role), action)
+			switch {
+			case granted && (err != nil || principal.UserID != caller):
+				t.Errorf("Allow(%s, %s) = %v, %v; want the caller", role, action, principal, err)
+			case !granted && !errors.Is(err, ErrForbidden):
+				t.Errorf("Allow(%s, %s) = %v, want ErrForbidden", role, action, err)
+			}
+		}
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	authorizer := NewAuthorizer(DefaultPolicy, team{})
+	ctx := context.Background()
+	if _, err := authorizer.Allow(ctx, ReadIntervention); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Allow = %v, want ErrUnauthenticated", err)
+	}
+	if err := authorizer.Authorize(ctx, ReadIntervention, resources["of someone else"]); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Authorize = %v, want ErrUnauthenticated", err)
+	}
+	if err := authorizer.ScopeFilter(ctx, ReadIntervention, &repository.InterventionFilter{}); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("ScopeFilter = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestScopeFilter(t *testing.T) {
+	tests := []struct {
+		role   domain.Role
+		action Action
+		want   repository.InterventionFilter
+	}{
+		{domain.RolePatientNavigator, ReadIntervention, repository.InterventionFilter{}},
+		{domain.RoleNurseNavigator, ReadIntervention, repository.InterventionFilter{}},
+		{domain.RoleSocialWorker, ReadIntervention, repository.InterventionFilter{VisibleTo: []string{caller}}},
+		{domain.RoleRegisteredDietitian, ReadIntervention, repository.InterventionFilter{VisibleTo: []string{caller}}},
+		{domain.RoleNavigatorAdmin, ReadIntervention, repository.InterventionFilter{VisibleTo: []string{caller, teammate}}},
+		{domain.RolePatient, ReadIntervention, repository.InterventionFilter{PatientID: caller}},
+		{domain.RolePatientNavigator, CompleteIntervention, repository.InterventionFilter{VisibleAssignedTo: []string{caller}}},
+		{domain.RoleSocialWorker, CompleteIntervention, repository.InterventionFilter{VisibleAssignedTo: []string{caller}}},
+		{domain.RoleNavigatorAdmin, CompleteIntervention, repository.InterventionFilter{VisibleTo: []string{caller, teammate}}},
+		{domain.RoleSocialWorker, StartIntervention, repository.InterventionFilter{VisibleTo: []string{caller}}},
+	}
+	authorizer := NewAuthorizer(DefaultPolicy, team{})
+	for _, tt := range tests {
+		t.Run(string(tt.role)+" "+string(tt.action), func(t *testing.T) {
+			filter := repository.InterventionFilter{}
+			if err := authorizer.ScopeFilter(principalContext(tt.role), tt.action, &filter); err != nil {
+				t.Fatalf("ScopeFilter: %v", err)
+			}
+			if !reflect.DeepEqual(filter, tt.want) {
+				t.Fatalf("filter = %+v, want %+v", filter, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeFilterKeepsCallerFilters(t *testing.T) {
+	authorizer := NewAuthorizer(DefaultPolicy, team{})
+	filter := repository.InterventionFilter{AssignedTo: colleague, Statuses: []domain.InterventionStatus{domain.StatusPending}}
+	if err := authorizer.ScopeFilter(principalContext(domain.RoleSocialWorker), ReadIntervention, &filter); err != nil {
+		t.Fatalf("ScopeFilter: %v", err)
+	}
+	want := repository.InterventionFilter{
+		AssignedTo: colleague,
+		Statuses:   []domain.InterventionStatus{domain.StatusPending},
+		VisibleTo:  []string{caller},
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Fatalf("filter = %+v, want %+v", filter, want)
+	}
+}
+
+func TestScopeFilterRejects(t *testing.T) {
+	authorizer := NewAuthorizer(DefaultPolicy, team{})
+
+	// A patient asking for another patient's interventions.
+	filter := repository.InterventionFilter{PatientID: "patient-1"}
+	if err := authorizer.ScopeFilter(principalContext(domain.RolePatient), ReadIntervention, &filter); !errors.Is(err, ErrForbidden) {
+		t.Errorf("patient filtering on another patient: %v, want ErrForbidden", err)
+	}
+
+	for _, role := range roles {
+		if _, granted := wantScopes[CreateIntervention][role]; granted {
+			continue
+		}
+		if err := authorizer.ScopeFilter(principalContext(role), CreateIntervention, &repository.InterventionFilter{}); !errors.Is(err, ErrForbidden) {
+			t.Errorf("%s: ScopeFilter = %v, want ErrForbidden", role, err)
+		}
+	}
+}
+
+func TestTeamDirectoryFailure(t *testing.T) {
+	lookup := errors.New("connection refused")
+	authorizer := NewAuthorizer(DefaultPolicy, team{err: lookup})
+	ctx := principalContext(domain.RoleNavigatorAdmin)
+
+	if err := authorizer.Authorize(ctx, ReadIntervention, resources["assigned to a teammate"]); !errors.Is(err, lookup) {
+		t.Errorf("Authorize = %v, want the directory's error", err)
+	}
+	if err := authorizer.ScopeFilter(ctx, ReadIntervention, &repository.InterventionFilter{}); !errors.Is(err, lookup) {
+		t.Errorf("ScopeFilter = %v, want the directory's error", err)
+	}
+}
+
+func TestWithoutTeamDirectoryAdminsSeeOwnWork(t *testing.T) {
+	authorizer := NewAuthorizer(DefaultPolicy, nil)
+	ctx := principalContext(domain.RoleNavigatorAdmin)
+	if err := authorizer.Authorize(ctx, ReadIntervention, resources["assigned to the caller"]); err != nil {
+		t.Errorf("Authorize own work: %v", err)
+	}
+	if err := authorizer.Authorize(ctx, ReadIntervention, resources["assigned to a teammate"]); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Authorize team work = %v, want ErrForbidden", err)
+	}
+}
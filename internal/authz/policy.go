@@ -0,0 +1,127 @@
+// Package authz decides which roles may do what to which interventions.
+// The rules are data, in DefaultPolicy; Authorizer applies them to the
+// principal on a request's context.
+package authz
+
+import (
+	"github.com/lambda/internal/domain"
+)
+
+type Action string
+
+const (
+	CreateIntervention   Action = "create"
+	ReadIntervention     Action = "read"
+	UpdateIntervention   Action = "update"
+	StartIntervention    Action = "start"
+	CompleteIntervention Action = "complete"
+	CancelIntervention   Action = "cancel"
+	ReopenIntervention   Action = "reopen"
+	// ViewAnalytics covers the tenant-wide barrier and workload dashboards.
+	ViewAnalytics Action = "view analytics on"
+)
+
+// Scope is which interventions a rule lets a role act on.
+type Scope int
+
+const (
+	// AnyInTenant covers every intervention of the caller's tenant.
+	AnyInTenant Scope = iota
+	// OwnWork covers interventions assigned to or created by the caller.
+	OwnWork
+	// TeamWork is OwnWork widened to the navigators the caller
+	// administers, as recorded by their NavigatorAdminID.
+	TeamWork
+	// OwnRecord covers the interventions of the patient who is the caller.
+	// There is no separate patient-to-account mapping: an intervention's
+	// PatientID is compared with the caller's Cognito sub, so a patient
+	// only sees interventions filed under their account's user ID.
+	OwnRecord
+	// AssignedWork covers interventions assigned to the caller, but not
+	// those the caller merely created.
+	AssignedWork
+)
+
+// Rule grants an action to roles, limited to a scope.
+type Rule struct {
+	Roles []domain.Role
+	Scope Scope
+}
+
+// Policy lists, per action, the rules that grant it. A principal may act
+// under the widest scope any rule for its role grants; an action without
+// rules is denied to everyone.
+type Policy map[Action][]Rule
+
+var (
+	navigators  = []domain.Role{domain.RolePatientNavigator, domain.RoleNurseNavigator}
+	specialists = []domain.Role{domain.RoleSocialWorker, domain.RoleRegisteredDietitian}
+	admins      = []domain.Role{domain.RoleNavigatorAdmin}
+	patients    = []domain.Role{domain.RolePatient}
+	staff       = append(append([]domain.Role{}, navigators...), specialists...)
+)
+
+// DefaultPolicy: navigators run the tenant's interventions; specialists
+// work the interventions assigned to them; admins oversee their navigators'
+// work; patients can only read their own. Only the assignee completes an
+// intervention, whoever created it.
+var DefaultPolicy = Policy{
+	CreateIntervention: {
+		{Roles: navigators, Scope: AnyInTenant},
+		{Roles: admins, Scope: AnyInTenant},
+	},
+	ReadIntervention: {
+		{Roles: navigators, Scope: AnyInTenant},
+		{Roles: specialists, Scope: OwnWork},
+		{Roles: admins, Scope: TeamWork},
+		{Roles: patients, Scope: OwnRecord},
+	},
+	UpdateIntervention: {
+		{Roles: navigators, Scope: AnyInTenant},
+		{Roles: specialists, Scope: OwnWork},
+		{Roles: admins, Scope: TeamWork},
+	},
+	StartIntervention: {
+		{Roles: staff, Scope: OwnWork},
+		{Roles: admins, Scope: TeamWork},
+	},
+	CompleteIntervention: {
+		{Roles: staff, Scope: AssignedWork},
+		{Roles: admins, Scope: TeamWork},
+	},
+	CancelIntervention: {
+		{Roles: navigators, Scope: AnyInTenant},
+		{Roles: admins, Scope: TeamWork},
+	},
+	ReopenIntervention: {
+		{Roles: navigators, Scope: AnyInTenant},
+		{Roles: admins, Scope: TeamWork},
+	},
+	ViewAnalytics: {
+		{Roles: navigators, Scope: AnyInTenant},
+		{Roles: admins, Scope: AnyInTenant},
+	},
+}
+
+// scopeFor returns the widest scope the policy grants role for action.
+func (p Policy) scopeFor(action Action, role domain.Role) (Scope, bool) {
+	best, found := Scope(0), false
+	for _, rule := range p[action] {
+		for _, granted := range rule.Roles {
+			if granted != role {
+				continue
+			}
+			if !found || wider(rule.Scope, best) {
+				best, found = rule.Scope, true
+			}
+		}
+	}
+	return best, found
+}
+
+// wider orders scopes from the whole tenant down to a single patient's
+// record.
+func wider(a, b Scope) bool {
+	rank := map[Scope]int{AnyInTenant: 4, TeamWork: 3, OwnWork: 2, AssignedWork: 1, OwnRecord: 0}
+	return rank[a] > rank[b]
+}
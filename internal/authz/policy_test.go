@@ -0,0 +1,132 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/lambda/internal/domain"
+)
+
+var (
+	roles = []domain.Role{
+		domain.RolePatient,
+		domain.RolePatientNavigator,
+		domain.RoleNurseNavigator,
+		domain.RoleSocialWorker,
+		domain.RoleRegisteredDietitian,
+		domain.RoleNavigatorAdmin,
+	}
+	actions = []Action{
+		CreateIntervention,
+		ReadIntervention,
+		UpdateIntervention,
+		StartIntervention,
+		CompleteIntervention,
+		CancelIntervention,
+		ReopenIntervention,
+		ViewAnalytics,
+	}
+)
+
+// wantScopes is DefaultPolicy written out per role and action; a missing
+// entry means the role may not perform the action at all. It is kept apart
+// from DefaultPolicy so a change to the policy has to be made here too.
+var wantScopes = map[Action]map[domain.Role]Scope{
+	CreateIntervention: {
+		domain.RolePatientNavigator: AnyInTenant,
+		domain.RoleNurseNavigator:   AnyInTenant,
+		domain.RoleNavigatorAdmin:   AnyInTenant,
+	},
+	ReadIntervention: {
+		domain.RolePatient:             OwnRecord,
+		domain.RolePatientNavigator:    AnyInTenant,
+		domain.RoleNurseNavigator:      AnyInTenant,
+		domain.RoleSocialWorker:        OwnWork,
+		domain.RoleRegisteredDietitian: OwnWork,
+		domain.RoleNavigatorAdmin:      TeamWork,
+	},
+	UpdateIntervention: {
+		domain.RolePatientNavigator:    AnyInTenant,
+		domain.RoleNurseNavigator:      AnyInTenant,
+		domain.RoleSocialWorker:        OwnWork,
+		domain.RoleRegisteredDietitian: OwnWork,
+		domain.RoleNavigatorAdmin:      TeamWork,
+	},
+	StartIntervention: {
+		domain.RolePatientNavigator:    OwnWork,
+		domain.RoleNurseNavigator:      OwnWork,
+		domain.RoleSocialWorker:        OwnWork,
+		domain.RoleRegisteredDietitian: OwnWork,
+		domain.RoleNavigatorAdmin:      TeamWork,
+	},
+	CompleteIntervention: {
+		domain.RolePatientNavigator:    AssignedWork,
+		domain.RoleNurseNavigator:      AssignedWork,
+		domain.RoleSocialWorker:        AssignedWork,
+		domain.RoleRegisteredDietitian: AssignedWork,
+		domain.RoleNavigatorAdmin:      TeamWork,
+	},
+	CancelIntervention: {
+		domain.RolePatientNavigator: AnyInTenant,
+		domain.RoleNurseNavigator:   AnyInTenant,
+		domain.RoleNavigatorAdmin:   TeamWork,
+	},
+	ReopenIntervention: {
+		domain.RolePatientNavigator: AnyInTenant,
+		domain.RoleNurseNavigator:   AnyInTenant,
+		domain.RoleNavigatorAdmin:   TeamWork,
+	},
+	ViewAnalytics: {
+		domain.RolePatientNavigator: AnyInTenant,
+		domain.RoleNurseNavigator:   AnyInTenant,
+		domain.RoleNavigatorAdmin:   AnyInTenant,
+	},
+}
+
+func TestDefaultPolicyScopes(t *testing.T) {
+	for _, action := range actions {
+		for _, role := range roles {
+			t.Run(string(role)+" "+string(action), func(t *testing.T) {
+				want, wantOK := wantScopes[action][role]
+				got, ok := DefaultPolicy.scopeFor(action, role)
+				if ok != wantOK || got != want {
+					t.Fatalf("scopeFor(%q, %q) = %v, %v; want %v, %v", action, role, got, ok, want, wantOK)
+				}
+			})
+		}
+	}
+}
+
+func TestDefaultPolicyCoversEveryAction(t *testing.T) {
+	for _, action := range actions {
+		if len(DefaultPolicy[action]) == 0 {
+			t.Errorf("DefaultPolicy has no rules for %q", action)
+		}
+	}
+	if len(DefaultPolicy) != len(actions) {
+		t.Errorf("DefaultPolicy has %d actions, the test knows %d", len(DefaultPolicy), len(actions))
+	}
+}
+
+func TestScopeForPicksTheWidestRule(t *testing.T) {
+	scopes := []Scope{OwnRecord, AssignedWork, OwnWork, TeamWork, AnyInTenant}
+	for i, narrow := range scopes {
+		for _, wide := range scopes[i:] {
+			policy := Policy{ReadIntervention: {
+				{Roles: []domain.Role{domain.RoleSocialWorker}, Scope: wide},
+				{Roles: []domain.Role{domain.RoleSocialWorker}, Scope: narrow},
+			}}
+			if got, _ := policy.scopeFor(ReadIntervention, domain.RoleSocialWorker); got != wide {
+				t.Errorf("rules %v and %v: scope = %v, want %v", wide, narrow, got, wide)
+			}
+		}
+	}
+}
+
+func TestScopeForUnknownRoleOrAction(t *testing.T) {
+	if _, ok := DefaultPolicy.scopeFor(ReadIntervention, domain.Role("")); ok {
+		t.Error("a principal without a role may read interventions")
+	}
+	if _, ok := DefaultPolicy.scopeFor(Action("delete"), domain.RolePatientNavigator); ok {
+		t.Error("an action without rules is granted")
+	}
+}
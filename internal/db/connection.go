@@ -70,6 +70,9 @@ func connectWriteDB() (*gorm.DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := db.Use(TenantIsolation{}); err != nil {
+		return nil, err
+	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -103,6 +106,9 @@ func connectReadDB() (*gorm.DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := db.Use(TenantIsolation{}); err != nil {
+		return nil, err
+	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/auth"
+)
+
+// TenantRole is the database role tenant-scoped statements run as. The
+// row-level security policies of migration 013 apply to it and compare
+// each row's tenant_id with the app.tenant_id setting.
+const TenantRole = "tenant_app"
+
+// ErrTenantRowsOutsideTx is returned for Row, Rows and Scan queries made
+// for an authenticated request outside a transaction; GORM's Scan reads
+// through Rows. Their results are read after the query returns, so there is
+// no point at which a transaction opened just for them could be committed.
+// Run them in db.Transaction instead.
+var ErrTenantRowsOutsideTx = errors.New("tenant-scoped row queries must run in a transaction")
+
+const startedTx = "tenant:started_transaction"
+
+// TenantIsolation is a GORM plugin that makes every statement issued with
+// an authenticated principal on its context run as TenantRole with
+// app.tenant_id set to the principal's tenant, so Postgres row-level
+// security hides other tenants' rows even from a query that forgets its
+// tenant_id filter. Both settings are transaction-local: statements already
+// in a transaction, including GORM's default write transactions, set them
+// on it; other queries are wrapped in a transaction of their own. Without
+// a principal, as in workers and scripts, statements run unchanged as the
+// connecting role, which owns the tables and bypasses the policies.
+type TenantIsolation struct{}
+
+func (TenantIsolation) Name() string {
+	return "tenant_isolation"
+}
+
+func (TenantIsolation) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Create().After("gorm:begin_transaction").Before("gorm:before_create").Register("tenant:scope", scopeTenant(false)); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:begin_transaction").Before("gorm:setup_reflect_value").Register("tenant:scope", scopeTenant(false)); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:begin_transaction").Before("gorm:before_delete").Register("tenant:scope", scopeTenant(false)); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:scope", scopeTenant(false)); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("tenant:scope", scopeTenant(false)); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:scope", scopeTenant(true)); err != nil {
+		return err
+	}
+
+	if err := callbacks.Create().After("*").Register("tenant:commit_or_rollback", commitOrRollback); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("*").Register("tenant:commit_or_rollback", commitOrRollback); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("*").Register("tenant:commit_or_rollback", commitOrRollback); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("*").Register("tenant:commit_or_rollback", commitOrRollback); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("*").Register("tenant:commit_or_rollback", commitOrRollback)
+}
+
+func scopeTenant(rows bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.DryRun {
+			return
+		}
+		principal, err := auth.PrincipalFrom(db.Statement.Context)
+		if err != nil {
+			return
+		}
+
+		if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); !inTx {
+			if rows {
+				db.AddError(ErrTenantRowsOutsideTx)
+				return
+			}
+			tx := db.Begin()
+			if tx.Error != nil {
+				db.AddError(tx.Error)
+				return
+			}
+			db.Statement.ConnPool = tx.Statement.ConnPool
+			db.InstanceSet(startedTx, true)
+		}
+
+		_, err = db.Statement.ConnPool.ExecContext(db.Statement.Context,
+			"SELECT set_config('role', $1, true), set_config('app.tenant_id', $2, true)",
+			TenantRole, principal.TenantID)
+		if err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+func commitOrRollback(db *gorm.DB) {
+	if _, ok := db.InstanceGet(startedTx); !ok {
+		return
+	}
+	if db.Error != nil {
+		db.Rollback()
+	} else {
+		db.Commit()
+	}
+	db.Statement.ConnPool = db.ConnPool
+}
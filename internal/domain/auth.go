@@ -18,25 +18,63 @@ const (
 )
 
 type User struct {
-	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key;"`
-	TenantID         uuid.UUID `json:"tenant_id" gorm:"type:uuid"`
-	Email            string    `json:"email" gorm:"uniqueIndex"`
-	Username         string    `json:"username" gorm:"uniqueIndex"`
-	PhoneNumber      string    `json:"phone_number"`
-	Role             Role      `json:"role"`
-	NavigatorAdminID uuid.UUID `json:"navigator_admin_id" gorm:"type:uuid"`
-	IsDeleted        bool      `json:"is_deleted"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;"`
+	TenantID         string     `json:"tenant_id"`
+	Email            string     `json:"email" gorm:"uniqueIndex"`
+	Username         string     `json:"username" gorm:"uniqueIndex"`
+	PhoneNumber      string     `json:"phone_number"`
+	Role             Role       `json:"role"`
+	NavigatorAdminID *uuid.UUID `json:"navigator_admin_id,omitempty" gorm:"type:uuid"`
+	IsDeleted        bool       `json:"is_deleted"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
+// InvitationStatus is where an invitation is in its lifecycle. Only a
+// pending invitation can be accepted. Pending and expired ones can be
+// revoked, or resent, which makes them pending again with a new token;
+// accepted and revoked are final.
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+	InvitationExpired  InvitationStatus = "expired"
+)
+
 type Invitation struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;"`
-	Email     string    `json:"email"`
-	Token     string    `json:"token"`
-	Role      Role      `json:"role"`
-	InvitedBy uuid.UUID `json:"invited_by" gorm:"type:uuid"`
-	ExpiresAt time.Time `json:"expires_at"`
-	IsUsed    bool      `json:"is_used"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;"`
+	TenantID   string           `json:"tenant_id"`
+	Email      string           `json:"email"`
+	Token      string           `json:"token"`
+	Role       Role             `json:"role"`
+	InvitedBy  uuid.UUID        `json:"invited_by" gorm:"type:uuid"`
+	Status     InvitationStatus `json:"status"`
+	ExpiresAt  time.Time        `json:"expires_at"`
+	IsUsed     bool             `json:"is_used"`
+	AcceptedBy *uuid.UUID       `json:"accepted_by,omitempty" gorm:"type:uuid"`
+	AcceptedAt *time.Time       `json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// Valid reports whether r is one of the roles above.
+func (r Role) Valid() bool {
+	switch r {
+	case RolePatient, RolePatientNavigator, RoleSocialWorker, RoleNavigatorAdmin, RoleNurseNavigator, RoleRegisteredDietitian:
+		return true
+	}
+	return false
+}
+
+// NavigatorAdminID is the navigator admin the invited user reports to: the
+// inviter when a navigator is invited, and nobody otherwise.
+func (i *Invitation) NavigatorAdminID() string {
+	switch i.Role {
+	case RolePatientNavigator, RoleNurseNavigator:
+		return i.InvitedBy.String()
+	}
+	return ""
 }
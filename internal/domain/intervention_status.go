@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidStatusTransition = errors.New("invalid intervention status transition")
+
+// InvalidTransitionError is returned when an intervention is asked to move
+// to a status that the transition table does not allow from its current one.
+type InvalidTransitionError struct {
+	From InterventionStatus
+	To   InterventionStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot move intervention from %q to %q", e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidStatusTransition
+}
+
+// interventionTransitions lists the statuses each status may move to.
+// Completed and cancelled interventions have to be reopened (back to
+// pending) before they can be worked on again.
+var interventionTransitions = map[InterventionStatus][]InterventionStatus{
+	StatusPending:    {StatusInProgress, StatusCompleted, StatusCancelled},
+	StatusInProgress: {StatusCompleted, StatusCancelled},
+	StatusCompleted:  {StatusPending},
+	StatusCancelled:  {StatusPending},
+}
+
+func (s InterventionStatus) CanTransitionTo(to InterventionStatus) bool {
+	for _, allowed := range interventionTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s InterventionStatus) IsClosed() bool {
+	return s == StatusCompleted || s == StatusCancelled
+}
+
+// TransitionTo moves the intervention to the given status, or returns an
+// *InvalidTransitionError if the move is not allowed.
+func (i *Intervention) TransitionTo(to InterventionStatus) error {
+	if !i.Status.CanTransitionTo(to) {
+		return &InvalidTransitionError{From: i.Status, To: to}
+	}
+	i.Status = to
+	return nil
+}
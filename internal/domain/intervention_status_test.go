@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransitionTo(t *testing.T) {
+	statuses := []InterventionStatus{StatusPending, StatusInProgress, StatusCompleted, StatusCancelled}
+
+	// allowed is written out rather than read from interventionTransitions,
+	// so a change to the table has to be made here too.
+	allowed := map[InterventionStatus]map[InterventionStatus]bool{
+		StatusPending:    {StatusInProgress: true, StatusCompleted: true, StatusCancelled: true},
+		StatusInProgress: {StatusCompleted: true, StatusCancelled: true},
+		StatusCompleted:  {StatusPending: true},
+		StatusCancelled:  {StatusPending: true},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				intervention := &Intervention{Status: from}
+				err := intervention.TransitionTo(to)
+
+				if allowed[from][to] {
+					if err != nil {
+						t.Fatalf("TransitionTo(%q) from %q: unexpected error %v", to, from, err)
+					}
+					if intervention.Status != to {
+						t.Fatalf("status = %q, want %q", intervention.Status, to)
+					}
+					return
+				}
+
+				if !errors.Is(err, ErrInvalidStatusTransition) {
+					t.Fatalf("TransitionTo(%q) from %q: error = %v, want ErrInvalidStatusTransition", to, from, err)
+				}
+				var transitionErr *InvalidTransitionError
+				if !errors.As(err, &transitionErr) || transitionErr.From != from || transitionErr.To != to {
+					t.Fatalf("error = %#v, want *InvalidTransitionError{%q, %q}", err, from, to)
+				}
+				if intervention.Status != from {
+					t.Fatalf("status changed to %q on a rejected transition", intervention.Status)
+				}
+			})
+		}
+	}
+}
+
+func TestTransitionFromUnknownStatus(t *testing.T) {
+	intervention := &Intervention{Status: InterventionStatus("archived")}
+	if err := intervention.TransitionTo(StatusPending); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Fatalf("error = %v, want ErrInvalidStatusTransition", err)
+	}
+}
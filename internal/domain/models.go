@@ -72,6 +72,7 @@ type Intervention struct {
 	ReferralReasons pq.StringArray     `gorm:"type:text[]" json:"referral_reasons"`
 	Problems        pq.StringArray     `gorm:"type:text[]" json:"problems"`
 	Notes           *string            `gorm:"type:text" json:"notes,omitempty"`
+	Version         int64              `gorm:"not null;default:0" json:"version"`
 	CreatedAt       time.Time          `gorm:"type:timestamptz;autoCreateTime" json:"created_at"`
 	UpdatedAt       time.Time          `gorm:"type:timestamptz;autoUpdateTime" json:"updated_at"`
 	User            *User              `gorm:"foreignKey:AssignedTo;references:ID" json:"user,omitempty"`
@@ -93,6 +94,21 @@ type BarrierResponse struct {
 	SubtypeData []*BarrierSubtype `json:"subtypeData"`
 }
 
+// WorkloadCount is one assignee's or team's caseload. Name is empty for
+// unassigned interventions. Open counts pending and in-progress
+// interventions, of which Overdue were due before today (UTC).
+type WorkloadCount struct {
+	Name      string `json:"name"`
+	Open      int    `json:"open"`
+	Overdue   int    `json:"overdue"`
+	Completed int    `json:"completed"`
+}
+
+type WorkloadResponse struct {
+	ByAssignee []*WorkloadCount `json:"byAssignee"`
+	ByTeam     []*WorkloadCount `json:"byTeam"`
+}
+
 func (i *Intervention) BeforeCreate(tx *gorm.DB) (err error) {
 	if i.ID == "" {
 		i.ID = "int_" + uuid.New().String()
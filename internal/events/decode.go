@@ -0,0 +1,64 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidEvent = errors.New("invalid event")
+
+// InvalidEventError marks an event that can never be applied, however often
+// it is redelivered. Consumers move such events to a dead-letter queue with
+// Reason attached instead of retrying them.
+type InvalidEventError struct {
+	EventID   string
+	EventType string
+	Reason    string
+}
+
+func (e *InvalidEventError) Error() string {
+	return fmt.Sprintf("invalid %s event %s: %s", e.EventType, e.EventID, e.Reason)
+}
+
+func (e *InvalidEventError) Is(target error) bool {
+	return target == ErrInvalidEvent
+}
+
+// validateIntervention checks the fields every intervention payload shares.
+func validateIntervention(event *DomainEvent, interventionID string, at time.Time, atField string) error {
+	if interventionID == "" {
+		return errors.New("missing intervention_id")
+	}
+	if interventionID != event.AggregateID && event.AggregateID != "" {
+		return errors.New("intervention_id does not match aggregate_id")
+	}
+	if at.IsZero() {
+		return fmt.Errorf("missing %s", atField)
+	}
+	return nil
+}
+
+func (e *InterventionCreatedEvent) Validate(event *DomainEvent) error {
+	return validateIntervention(event, e.InterventionID, e.CreatedAt, "created_at")
+}
+
+func (e *InterventionUpdatedEvent) Validate(event *DomainEvent) error {
+	return validateIntervention(event, e.InterventionID, e.UpdatedAt, "updated_at")
+}
+
+func (e *InterventionCompletedEvent) Validate(event *DomainEvent) error {
+	return validateIntervention(event, e.InterventionID, e.CompletedAt, "completed_at")
+}
+
+func (e *InterventionCancelledEvent) Validate(event *DomainEvent) error {
+	return validateIntervention(event, e.InterventionID, e.CancelledAt, "cancelled_at")
+}
+
+func (e *InterventionStartedEvent) Validate(event *DomainEvent) error {
+	return validateIntervention(event, e.InterventionID, e.StartedAt, "started_at")
+}
+
+func (e *InterventionReopenedEvent) Validate(event *DomainEvent) error {
+	return validateIntervention(event, e.InterventionID, e.ReopenedAt, "reopened_at")
+}
@@ -0,0 +1,103 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// updatedEvent is an intervention.updated envelope for intervention-1
+// carrying payload as written.
+func updatedEvent(payload string) *DomainEvent {
+	return &DomainEvent{
+		EventID:     "event-1",
+		EventType:   InterventionUpdated,
+		AggregateID: "intervention-1",
+		TenantID:    "tenant-1",
+		Payload:     json.RawMessage(payload),
+	}
+}
+
+func decodeUpdated(event *DomainEvent) (*InterventionUpdatedEvent, error) {
+	return DecodePayload[InterventionUpdatedEvent](DefaultRegistry, event)
+}
+
+func TestDecodeUpdatedEvent(t *testing.T) {
+	updated, err := decodeUpdated(updatedEvent(`{
+		"intervention_id": "intervention-1",
+		"tenant_id": "tenant-1",
+		"updated_fields": {"priority": "high", "notes": "", "problems": ["housing"]},
+		"updated_at": "2024-05-01T12:00:00Z"
+	}`))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	changes := updated.UpdatedFields
+	if changes.Priority == nil || *changes.Priority != "high" {
+		t.Errorf("priority = %v, want high", changes.Priority)
+	}
+	if changes.Notes == nil || *changes.Notes != "" {
+		t.Errorf("notes = %v, want set to empty", changes.Notes)
+	}
+	if changes.Problems == nil || !reflect.DeepEqual(*changes.Problems, []string{"housing"}) {
+		t.Errorf("problems = %v, want [housing]", changes.Problems)
+	}
+	if changes.AssignedTo != nil || changes.AssignedTeam != nil {
+		t.Errorf("fields absent from the payload were set: %+v", changes)
+	}
+}
+
+func TestDecodeUpdatedEventRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{
+			name:    "unknown column",
+			payload: `{"intervention_id": "intervention-1", "updated_fields": {"status": "completed"}, "updated_at": "2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name:    "column name carrying SQL",
+			payload: `{"intervention_id": "intervention-1", "updated_fields": {"priority = 'high', tenant_id": "x"}, "updated_at": "2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name:    "unknown top-level field",
+			payload: `{"intervention_id": "intervention-1", "updated_fields": {}, "updated_at": "2024-05-01T12:00:00Z", "version": 3}`,
+		},
+		{
+			name:    "wrong value type",
+			payload: `{"intervention_id": "intervention-1", "updated_fields": {"problems": "housing"}, "updated_at": "2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name:    "missing intervention_id",
+			payload: `{"updated_fields": {"priority": "high"}, "updated_at": "2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name:    "intervention_id of another aggregate",
+			payload: `{"intervention_id": "intervention-2", "updated_fields": {"priority": "high"}, "updated_at": "2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name:    "missing updated_at",
+			payload: `{"intervention_id": "intervention-1", "updated_fields": {"priority": "high"}}`,
+		},
+		{
+			name:    "not an object",
+			payload: `["intervention-1"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeUpdated(updatedEvent(tt.payload))
+			if !errors.Is(err, ErrInvalidEvent) {
+				t.Fatalf("err = %v, want ErrInvalidEvent", err)
+			}
+			var invalid *InvalidEventError
+			if !errors.As(err, &invalid) || invalid.EventID != "event-1" || invalid.EventType != string(InterventionUpdated) || invalid.Reason == "" {
+				t.Fatalf("err = %#v, want an InvalidEventError naming the event and why", err)
+			}
+		})
+	}
+}
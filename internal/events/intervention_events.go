@@ -1,6 +1,7 @@
 package events
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/lambda/internal/domain"
@@ -13,16 +14,23 @@ const (
 	InterventionUpdated   EventType = "intervention.updated"
 	InterventionCompleted EventType = "intervention.completed"
 	InterventionCancelled EventType = "intervention.cancelled"
+	InterventionStarted   EventType = "intervention.started"
+	InterventionReopened  EventType = "intervention.reopened"
 )
 
+// DomainEvent is the envelope every event is published in. Payload holds
+// the JSON of the struct registered for EventType at SchemaVersion; use a
+// Registry to build and decode it.
 type DomainEvent struct {
-	EventID     string                 `json:"event_id"`
-	EventType   EventType              `json:"event_type"`
-	AggregateID string                 `json:"aggregate_id"`
-	TenantID    string                 `json:"tenant_id"`
-	Timestamp   time.Time              `json:"timestamp"`
-	Payload     map[string]interface{} `json:"payload"`
-	Metadata    map[string]string      `json:"metadata"`
+	EventID       string            `json:"event_id"`
+	EventType     EventType         `json:"event_type"`
+	SchemaVersion int               `json:"schema_version"`
+	AggregateID   string            `json:"aggregate_id"`
+	Sequence      int64             `json:"sequence"`
+	TenantID      string            `json:"tenant_id"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Payload       json.RawMessage   `json:"payload"`
+	Metadata      map[string]string `json:"metadata"`
 }
 
 type InterventionCreatedEvent struct {
@@ -45,10 +53,20 @@ type InterventionCreatedEvent struct {
 }
 
 type InterventionUpdatedEvent struct {
-	InterventionID string                 `json:"intervention_id"`
-	TenantID       string                 `json:"tenant_id"`
-	UpdatedFields  map[string]interface{} `json:"updated_fields"`
-	UpdatedAt      time.Time              `json:"updated_at"`
+	InterventionID string                   `json:"intervention_id"`
+	TenantID       string                   `json:"tenant_id"`
+	UpdatedFields  InterventionFieldChanges `json:"updated_fields"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+// InterventionFieldChanges is the allowlist of fields an intervention.updated
+// event may change. A nil field was not changed.
+type InterventionFieldChanges struct {
+	AssignedTo   *string   `json:"assigned_to,omitempty"`
+	AssignedTeam *string   `json:"assigned_team,omitempty"`
+	Priority     *string   `json:"priority,omitempty"`
+	Notes        *string   `json:"notes,omitempty"`
+	Problems     *[]string `json:"problems,omitempty"`
 }
 
 type InterventionCompletedEvent struct {
@@ -64,3 +82,17 @@ type InterventionCancelledEvent struct {
 	CancelledAt    time.Time  `json:"cancelled_at"`
 	Reason         *string    `json:"reason,omitempty"`
 }
+
+type InterventionStartedEvent struct {
+	InterventionID string    `json:"intervention_id"`
+	TenantID       string    `json:"tenant_id"`
+	StartedAt      time.Time `json:"started_at"`
+}
+
+type InterventionReopenedEvent struct {
+	InterventionID string                    `json:"intervention_id"`
+	TenantID       string                    `json:"tenant_id"`
+	PreviousStatus domain.InterventionStatus `json:"previous_status"`
+	ReopenedAt     time.Time                 `json:"reopened_at"`
+	Reason         *string                   `json:"reason,omitempty"`
+}
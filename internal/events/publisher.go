@@ -28,6 +28,10 @@ func NewKinesisEventPublisher(cfg aws.Config, streamName string) *KinesisEventPu
 	}
 }
 
+// Publish puts the event on the stream with its message group as the
+// partition key, so every event for one aggregate lands on the same shard in
+// publish order. The event ID is assigned before the first attempt and kept
+// on retries, which lets FIFO consumers downstream drop redeliveries.
 func (p *KinesisEventPublisher) Publish(ctx context.Context, event *DomainEvent) error {
 	if event.EventID == "" {
 		event.EventID = uuid.New().String()
@@ -44,7 +48,7 @@ func (p *KinesisEventPublisher) Publish(ctx context.Context, event *DomainEvent)
 	_, err = p.client.PutRecord(ctx, &kinesis.PutRecordInput{
 		StreamName:   aws.String(p.streamName),
 		Data:         data,
-		PartitionKey: aws.String(event.AggregateID),
+		PartitionKey: aws.String(event.MessageGroupID()),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to publish event to Kinesis: %w", err)
@@ -54,98 +58,41 @@ func (p *KinesisEventPublisher) Publish(ctx context.Context, event *DomainEvent)
 	return nil
 }
 
-func NewInterventionCreatedEvent(intervention *InterventionCreatedEvent) *DomainEvent {
-	payload := map[string]interface{}{
-		"intervention_id":  intervention.InterventionID,
-		"tenant_id":        intervention.TenantID,
-		"patient_id":       intervention.PatientID,
-		"screening_id":     intervention.ScreeningID,
-		"type":             intervention.Type,
-		"title":            intervention.Title,
-		"description":      intervention.Description,
-		"status":           intervention.Status,
-		"priority":         intervention.Priority,
-		"created_by":       intervention.CreatedBy,
-		"assigned_to":      intervention.AssignedTo,
-		"assigned_team":    intervention.AssignedTeam,
-		"due_at":           intervention.DueAt,
-		"referral_reasons": intervention.ReferralReasons,
-		"problems":         intervention.Problems,
-		"created_at":       intervention.CreatedAt,
+// MessageGroupID is the ordering key of the event: the Kinesis partition key
+// and the FIFO MessageGroupId. Events for one aggregate share it.
+func (e *DomainEvent) MessageGroupID() string {
+	if e.AggregateID != "" {
+		return e.AggregateID
 	}
+	return e.EventID
+}
 
-	return &DomainEvent{
-		EventID:     uuid.New().String(),
-		EventType:   InterventionCreated,
-		AggregateID: intervention.InterventionID,
-		TenantID:    intervention.TenantID,
-		Timestamp:   time.Now().UTC(),
-		Payload:     payload,
-		Metadata: map[string]string{
-			"source": "intervention-service",
-		},
-	}
+// DeduplicationID identifies every delivery of the same event, for use as
+// the FIFO MessageDeduplicationId.
+func (e *DomainEvent) DeduplicationID() string {
+	return e.EventID
 }
 
-func NewInterventionUpdatedEvent(updated *InterventionUpdatedEvent) *DomainEvent {
-	payload := map[string]interface{}{
-		"intervention_id": updated.InterventionID,
-		"tenant_id":       updated.TenantID,
-		"updated_fields":  updated.UpdatedFields,
-		"updated_at":      updated.UpdatedAt,
-	}
+func NewInterventionCreatedEvent(created *InterventionCreatedEvent) (*DomainEvent, error) {
+	return DefaultRegistry.NewEvent(InterventionCreated, created.InterventionID, created.TenantID, created)
+}
 
-	return &DomainEvent{
-		EventID:     uuid.New().String(),
-		EventType:   InterventionUpdated,
-		AggregateID: updated.InterventionID,
-		TenantID:    updated.TenantID,
-		Timestamp:   time.Now().UTC(),
-		Payload:     payload,
-		Metadata: map[string]string{
-			"source": "intervention-service",
-		},
-	}
+func NewInterventionUpdatedEvent(updated *InterventionUpdatedEvent) (*DomainEvent, error) {
+	return DefaultRegistry.NewEvent(InterventionUpdated, updated.InterventionID, updated.TenantID, updated)
 }
 
-func NewInterventionCompletedEvent(completed *InterventionCompletedEvent) *DomainEvent {
-	payload := map[string]interface{}{
-		"intervention_id": completed.InterventionID,
-		"tenant_id":       completed.TenantID,
-		"completed_at":    completed.CompletedAt,
-		"notes":           completed.Notes,
-	}
+func NewInterventionCompletedEvent(completed *InterventionCompletedEvent) (*DomainEvent, error) {
+	return DefaultRegistry.NewEvent(InterventionCompleted, completed.InterventionID, completed.TenantID, completed)
+}
 
-	return &DomainEvent{
-		EventID:     uuid.New().String(),
-		EventType:   InterventionCompleted,
-		AggregateID: completed.InterventionID,
-		TenantID:    completed.TenantID,
-		Timestamp:   time.Now().UTC(),
-		Payload:     payload,
-		Metadata: map[string]string{
-			"source": "intervention-service",
-		},
-	}
+func NewInterventionCancelledEvent(cancelled *InterventionCancelledEvent) (*DomainEvent, error) {
+	return DefaultRegistry.NewEvent(InterventionCancelled, cancelled.InterventionID, cancelled.TenantID, cancelled)
 }
 
-func NewInterventionCancelledEvent(cancelled *InterventionCancelledEvent) *DomainEvent {
-	payload := map[string]interface{}{
-		"intervention_id": cancelled.InterventionID,
-		"tenant_id":       cancelled.TenantID,
-		"cancelled_at":    cancelled.CancelledAt,
-		"reason":          cancelled.Reason,
-	}
+func NewInterventionStartedEvent(started *InterventionStartedEvent) (*DomainEvent, error) {
+	return DefaultRegistry.NewEvent(InterventionStarted, started.InterventionID, started.TenantID, started)
+}
 
-	return &DomainEvent{
-		EventID:     uuid.New().String(),
-		EventType:   InterventionCancelled,
-		AggregateID: cancelled.InterventionID,
-		TenantID:    cancelled.TenantID,
-		Timestamp:   time.Now().UTC(),
-		Payload:     payload,
-		Metadata: map[string]string{
-			"source": "intervention-service",
-		},
-	}
+func NewInterventionReopenedEvent(reopened *InterventionReopenedEvent) (*DomainEvent, error) {
+	return DefaultRegistry.NewEvent(InterventionReopened, reopened.InterventionID, reopened.TenantID, reopened)
 }
@@ -0,0 +1,198 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnregisteredEventType = errors.New("unregistered event type")
+
+// Upcaster rewrites a payload from one schema version to the next.
+type Upcaster func(payload map[string]interface{}) (map[string]interface{}, error)
+
+// Validator is implemented by payloads that check their own fields once
+// decoded. A returned error becomes the Reason of an *InvalidEventError.
+type Validator interface {
+	Validate(event *DomainEvent) error
+}
+
+type registration struct {
+	version     int
+	payloadType reflect.Type
+	upcasters   map[int]Upcaster
+}
+
+// Registry maps each EventType to its typed payload and current schema
+// version. It builds envelopes from payload structs and decodes envelopes
+// back into them, upcasting payloads written with older schema versions.
+type Registry struct {
+	types map[EventType]*registration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: map[EventType]*registration{}}
+}
+
+// DefaultRegistry holds every event type the service publishes.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	Register[InterventionCreatedEvent](DefaultRegistry, InterventionCreated, 1)
+	Register[InterventionUpdatedEvent](DefaultRegistry, InterventionUpdated, 1)
+	Register[InterventionCompletedEvent](DefaultRegistry, InterventionCompleted, 1)
+	Register[InterventionCancelledEvent](DefaultRegistry, InterventionCancelled, 1)
+	Register[InterventionStartedEvent](DefaultRegistry, InterventionStarted, 1)
+	Register[InterventionReopenedEvent](DefaultRegistry, InterventionReopened, 1)
+}
+
+// Register binds eventType to payload type T at the given schema version.
+// Registering a type twice replaces the earlier registration.
+func Register[T any](r *Registry, eventType EventType, version int) {
+	r.types[eventType] = &registration{
+		version:     version,
+		payloadType: reflect.TypeOf((*T)(nil)).Elem(),
+		upcasters:   map[int]Upcaster{},
+	}
+}
+
+// RegisterUpcaster adds the step that turns a fromVersion payload into a
+// fromVersion+1 payload. Decode chains steps up to the current version.
+func (r *Registry) RegisterUpcaster(eventType EventType, fromVersion int, upcaster Upcaster) error {
+	reg, ok := r.types[eventType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnregisteredEventType, eventType)
+	}
+	if fromVersion < 1 || fromVersion >= reg.version {
+		return fmt.Errorf("upcaster for %s from version %d is outside 1..%d", eventType, fromVersion, reg.version-1)
+	}
+	reg.upcasters[fromVersion] = upcaster
+	return nil
+}
+
+// SchemaVersion returns the version new events of the type are written with.
+func (r *Registry) SchemaVersion(eventType EventType) (int, bool) {
+	reg, ok := r.types[eventType]
+	if !ok {
+		return 0, false
+	}
+	return reg.version, true
+}
+
+// EventTypes returns the registered event types.
+func (r *Registry) EventTypes() []EventType {
+	eventTypes := make([]EventType, 0, len(r.types))
+	for eventType := range r.types {
+		eventTypes = append(eventTypes, eventType)
+	}
+	return eventTypes
+}
+
+// NewEvent wraps payload in an envelope stamped with the current schema
+// version of eventType. The payload must be the registered type or a
+// pointer to it.
+func (r *Registry) NewEvent(eventType EventType, aggregateID, tenantID string, payload interface{}) (*DomainEvent, error) {
+	reg, ok := r.types[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredEventType, eventType)
+	}
+	if t := reflect.Indirect(reflect.ValueOf(payload)).Type(); t != reg.payloadType {
+		return nil, fmt.Errorf("%s payload must be %s, got %s", eventType, reg.payloadType, t)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", eventType, err)
+	}
+
+	return &DomainEvent{
+		EventID:       uuid.New().String(),
+		EventType:     eventType,
+		SchemaVersion: reg.version,
+		AggregateID:   aggregateID,
+		TenantID:      tenantID,
+		Timestamp:     time.Now().UTC(),
+		Payload:       data,
+		Metadata: map[string]string{
+			"source": "intervention-service",
+		},
+	}, nil
+}
+
+// Decode returns the event's payload as a pointer to its registered type,
+// upcast to the current schema version. Events written before envelopes
+// were versioned have no schema_version and are read as version 1. Unknown
+// fields, values of the wrong type and failed validation are reported as
+// *InvalidEventError; unregistered types as ErrUnregisteredEventType.
+func (r *Registry) Decode(event *DomainEvent) (interface{}, error) {
+	reg, ok := r.types[event.EventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredEventType, event.EventType)
+	}
+	invalid := func(reason string) error {
+		return &InvalidEventError{EventID: event.EventID, EventType: string(event.EventType), Reason: reason}
+	}
+
+	version := event.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	if version > reg.version {
+		return nil, invalid(fmt.Sprintf("schema version %d is newer than supported version %d", version, reg.version))
+	}
+
+	data := []byte(event.Payload)
+	if version < reg.version {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, invalid(err.Error())
+		}
+		for ; version < reg.version; version++ {
+			upcaster, ok := reg.upcasters[version]
+			if !ok {
+				return nil, invalid(fmt.Sprintf("no upcaster from schema version %d", version))
+			}
+			var err error
+			if payload, err = upcaster(payload); err != nil {
+				return nil, invalid(fmt.Sprintf("upcasting from schema version %d: %v", version, err))
+			}
+		}
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return nil, invalid(err.Error())
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	value := reflect.New(reg.payloadType)
+	if err := decoder.Decode(value.Interface()); err != nil {
+		return nil, invalid(err.Error())
+	}
+	if validator, ok := value.Interface().(Validator); ok {
+		if err := validator.Validate(event); err != nil {
+			return nil, invalid(err.Error())
+		}
+	}
+	return value.Interface(), nil
+}
+
+// DecodePayload decodes the event's payload into T, failing if T is not the
+// type registered for the event.
+func DecodePayload[T any](r *Registry, event *DomainEvent) (*T, error) {
+	payload, err := r.Decode(event)
+	if err != nil {
+		return nil, err
+	}
+	typed, ok := payload.(*T)
+	if !ok {
+		return nil, fmt.Errorf("%s payload is %T, not %T", event.EventType, payload, typed)
+	}
+	return typed, nil
+}
@@ -0,0 +1,197 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const referralMade EventType = "referral.made"
+
+// referral is at schema version 3. Version 1 called priority "urgency";
+// version 2 had a single "problem" where version 3 has a list.
+type referral struct {
+	ReferralID string   `json:"referral_id"`
+	Priority   string   `json:"priority"`
+	Problems   []string `json:"problems"`
+}
+
+func referralRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	Register[referral](r, referralMade, 3)
+	err := r.RegisterUpcaster(referralMade, 1, func(payload map[string]interface{}) (map[string]interface{}, error) {
+		payload["priority"] = payload["urgency"]
+		delete(payload, "urgency")
+		return payload, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterUpcaster 1: %v", err)
+	}
+	err = r.RegisterUpcaster(referralMade, 2, func(payload map[string]interface{}) (map[string]interface{}, error) {
+		problem, ok := payload["problem"].(string)
+		if !ok {
+			return nil, errors.New("problem is not a string")
+		}
+		payload["problems"] = []string{problem}
+		delete(payload, "problem")
+		return payload, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterUpcaster 2: %v", err)
+	}
+	return r
+}
+
+func referralEvent(version int, payload string) *DomainEvent {
+	return &DomainEvent{EventID: "event-1", EventType: referralMade, SchemaVersion: version, Payload: json.RawMessage(payload)}
+}
+
+func TestDecodeUpcastsToCurrentVersion(t *testing.T) {
+	r := referralRegistry(t)
+	want := &referral{ReferralID: "r-1", Priority: "high", Problems: []string{"housing"}}
+
+	tests := []struct {
+		name  string
+		event *DomainEvent
+	}{
+		{"version 1", referralEvent(1, `{"referral_id": "r-1", "urgency": "high", "problem": "housing"}`)},
+		{"unversioned reads as version 1", referralEvent(0, `{"referral_id": "r-1", "urgency": "high", "problem": "housing"}`)},
+		{"version 2", referralEvent(2, `{"referral_id": "r-1", "priority": "high", "problem": "housing"}`)},
+		{"current version", referralEvent(3, `{"referral_id": "r-1", "priority": "high", "problems": ["housing"]}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePayload[referral](r, tt.event)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("decoded %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsUndecodableVersions(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) *Registry
+		event *DomainEvent
+	}{
+		{
+			name:  "newer than supported",
+			setup: referralRegistry,
+			event: referralEvent(4, `{"referral_id": "r-1", "priority": "high", "problems": ["housing"]}`),
+		},
+		{
+			name: "no upcaster",
+			setup: func(t *testing.T) *Registry {
+				r := NewRegistry()
+				Register[referral](r, referralMade, 3)
+				return r
+			},
+			event: referralEvent(2, `{"referral_id": "r-1", "priority": "high", "problem": "housing"}`),
+		},
+		{
+			name:  "upcaster fails",
+			setup: referralRegistry,
+			event: referralEvent(2, `{"referral_id": "r-1", "priority": "high", "problem": ["housing"]}`),
+		},
+		{
+			name:  "field left behind by an upcaster",
+			setup: referralRegistry,
+			event: referralEvent(2, `{"referral_id": "r-1", "priority": "high", "problem": "housing", "urgency": "low"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.setup(t).Decode(tt.event); !errors.Is(err, ErrInvalidEvent) {
+				t.Fatalf("err = %v, want ErrInvalidEvent", err)
+			}
+		})
+	}
+}
+
+func TestUnregisteredEventType(t *testing.T) {
+	r := NewRegistry()
+	if _, err := r.Decode(referralEvent(1, `{}`)); !errors.Is(err, ErrUnregisteredEventType) {
+		t.Errorf("Decode err = %v, want ErrUnregisteredEventType", err)
+	}
+	if _, err := r.NewEvent(referralMade, "r-1", "tenant-1", &referral{}); !errors.Is(err, ErrUnregisteredEventType) {
+		t.Errorf("NewEvent err = %v, want ErrUnregisteredEventType", err)
+	}
+	if err := r.RegisterUpcaster(referralMade, 1, nil); !errors.Is(err, ErrUnregisteredEventType) {
+		t.Errorf("RegisterUpcaster err = %v, want ErrUnregisteredEventType", err)
+	}
+}
+
+func TestRegisterUpcasterRange(t *testing.T) {
+	r := referralRegistry(t)
+	for _, fromVersion := range []int{0, 3} {
+		if err := r.RegisterUpcaster(referralMade, fromVersion, nil); err == nil {
+			t.Errorf("RegisterUpcaster from version %d succeeded", fromVersion)
+		}
+	}
+}
+
+func TestNewEventRoundTrips(t *testing.T) {
+	created := &InterventionCreatedEvent{
+		InterventionID:  "intervention-1",
+		TenantID:        "tenant-1",
+		Title:           "Food bank referral",
+		Priority:        "medium",
+		ReferralReasons: []string{},
+		Problems:        []string{"food insecurity"},
+		CreatedAt:       time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	event, err := NewInterventionCreatedEvent(created)
+	if err != nil {
+		t.Fatalf("NewInterventionCreatedEvent: %v", err)
+	}
+	if event.SchemaVersion != 1 || event.AggregateID != "intervention-1" || event.TenantID != "tenant-1" || event.EventID == "" {
+		t.Fatalf("envelope = %+v", event)
+	}
+
+	decoded, err := DecodePayload[InterventionCreatedEvent](DefaultRegistry, event)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, created) {
+		t.Fatalf("decoded %+v, want %+v", decoded, created)
+	}
+
+	if _, err := DecodePayload[InterventionUpdatedEvent](DefaultRegistry, event); err == nil {
+		t.Error("DecodePayload into another event's payload succeeded")
+	}
+	if _, err := DefaultRegistry.NewEvent(InterventionCreated, "intervention-1", "tenant-1", &InterventionUpdatedEvent{}); err == nil {
+		t.Error("NewEvent with another event's payload succeeded")
+	}
+}
+
+func TestJSONSchemaDescribesRegisteredPayload(t *testing.T) {
+	schema, err := referralRegistry(t).JSONSchema(referralMade)
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+	if schema["$id"] != "referral.made.v3.schema.json" {
+		t.Errorf("$id = %v", schema["$id"])
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	if got := properties["schema_version"]; !reflect.DeepEqual(got, map[string]interface{}{"const": 3}) {
+		t.Errorf("schema_version = %v, want const 3", got)
+	}
+	payload := properties["payload"].(map[string]interface{})
+	if payload["additionalProperties"] != false {
+		t.Errorf("payload allows additional properties")
+	}
+	fields := payload["properties"].(map[string]interface{})
+	for _, field := range []string{"referral_id", "priority", "problems"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("payload schema lacks %s", field)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema document for the full envelope of
+// eventType at its current schema version, with the payload described by
+// its registered struct. Consumers outside the service can validate what
+// we publish against it.
+func (r *Registry) JSONSchema(eventType EventType) (map[string]interface{}, error) {
+	reg, ok := r.types[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredEventType, eventType)
+	}
+
+	envelope := schemaFor(reflect.TypeOf(DomainEvent{}))
+	properties := envelope["properties"].(map[string]interface{})
+	properties["event_type"] = map[string]interface{}{"const": string(eventType)}
+	properties["schema_version"] = map[string]interface{}{"const": reg.version}
+	properties["payload"] = schemaFor(reg.payloadType)
+
+	envelope["$schema"] = jsonSchemaDialect
+	envelope["$id"] = fmt.Sprintf("%s.v%d.schema.json", eventType, reg.version)
+	envelope["title"] = fmt.Sprintf("%s (schema version %d)", eventType, reg.version)
+	return envelope, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor describes a Go type the way encoding/json marshals it. Pointers,
+// slices and maps may be null, and fields that are pointers or omitempty are
+// not required.
+func schemaFor(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		return nullable(schemaFor(t.Elem()))
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// json.RawMessage and []byte hold arbitrary JSON here.
+			return map[string]interface{}{}
+		}
+		return nullable(map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())})
+	case reflect.Map:
+		return nullable(map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem())})
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, omitEmpty := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+			properties[name] = schemaFor(field.Type)
+			if !omitEmpty && field.Type.Kind() != reflect.Ptr {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// EMFRecorder writes each measurement as a CloudWatch embedded metric
+// format log line. Lambda forwards stdout to CloudWatch Logs, which
+// extracts the metrics from it.
+type EMFRecorder struct {
+	namespace string
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewEMFRecorder(namespace string, out io.Writer) *EMFRecorder {
+	return &EMFRecorder{namespace: namespace, out: out}
+}
+
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+func (r *EMFRecorder) ProjectionLag(eventType string, lag time.Duration) {
+	r.write(map[string]string{"EventType": eventType}, true, []emfMetric{{"ProjectionLag", "Milliseconds"}},
+		float64(lag.Milliseconds()))
+}
+
+func (r *EMFRecorder) IteratorAge(stream, shardID string, age time.Duration) {
+	r.write(map[string]string{"Stream": stream, "ShardId": shardID}, false, []emfMetric{{"IteratorAge", "Milliseconds"}},
+		float64(age.Milliseconds()))
+}
+
+func (r *EMFRecorder) QueueDepth(queue string, visible, inFlight int64) {
+	r.write(map[string]string{"Queue": queue}, false, []emfMetric{{"QueueDepth", "Count"}, {"QueueInFlight", "Count"}},
+		float64(visible), float64(inFlight))
+}
+
+func (r *EMFRecorder) DeadLetters(queue string, count int64) {
+	r.write(map[string]string{"Queue": queue}, false, []emfMetric{{"DeadLetterMessages", "Count"}},
+		float64(count))
+}
+
+// write logs one EMF document with values in the order of metrics, all
+// sharing the given dimensions. With rollup the metrics are also published
+// without dimensions, so one alarm can cover every dimension value.
+func (r *EMFRecorder) write(dimensions map[string]string, rollup bool, metrics []emfMetric, values ...float64) {
+	names := make([]string, 0, len(dimensions))
+	document := map[string]interface{}{}
+	for name, value := range dimensions {
+		names = append(names, name)
+		document[name] = value
+	}
+	dimensionSets := [][]string{names}
+	if rollup {
+		dimensionSets = append(dimensionSets, []string{})
+	}
+	for i, metric := range metrics {
+		document[metric.Name] = values[i]
+	}
+	document["_aws"] = map[string]interface{}{
+		"Timestamp": time.Now().UnixMilli(),
+		"CloudWatchMetrics": []map[string]interface{}{{
+			"Namespace":  r.namespace,
+			"Dimensions": dimensionSets,
+			"Metrics":    metrics,
+		}},
+	}
+
+	line, err := json.Marshal(document)
+	if err != nil {
+		log.Printf("Failed to encode metrics: %v", err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.out.Write(append(line, '\n'))
+}
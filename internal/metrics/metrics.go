@@ -0,0 +1,44 @@
+// Package metrics reports how healthy the event pipeline is: how far the
+// read model trails the write model, how far the forwarder trails Kinesis,
+// and how many messages are waiting in SQS. Standalone workers expose the
+// figures to Prometheus; Lambdas log them in CloudWatch embedded metric
+// format.
+package metrics
+
+import (
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/lambda/internal/events"
+)
+
+type Recorder interface {
+	// ProjectionLag is the time from an event being produced to it being
+	// applied to the read model.
+	ProjectionLag(eventType string, lag time.Duration)
+	// IteratorAge is how far a shard reader is behind the tip of the stream.
+	IteratorAge(stream, shardID string, age time.Duration)
+	// QueueDepth is the number of messages waiting in, and being processed
+	// from, a queue.
+	QueueDepth(queue string, visible, inFlight int64)
+	// DeadLetters is the number of messages in a dead letter queue.
+	DeadLetters(queue string, count int64)
+}
+
+// Applied records the end-to-end latency of an event that has just been
+// applied to the read model.
+func Applied(recorder Recorder, event *events.DomainEvent) {
+	if event.Timestamp.IsZero() {
+		return
+	}
+	recorder.ProjectionLag(string(event.EventType), time.Since(event.Timestamp))
+}
+
+// QueueName turns a queue URL into the short name used as a label.
+func QueueName(queueURL string) string {
+	if parsed, err := url.Parse(queueURL); err == nil && parsed.Path != "" {
+		return path.Base(parsed.Path)
+	}
+	return queueURL
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type PrometheusRecorder struct {
+	projectionLag *prometheus.HistogramVec
+	iteratorAge   *prometheus.GaugeVec
+	queueDepth    *prometheus.GaugeVec
+	deadLetters   *prometheus.GaugeVec
+}
+
+// NewPrometheusRecorder registers the pipeline metrics with registerer.
+func NewPrometheusRecorder(registerer prometheus.Registerer) *PrometheusRecorder {
+	r := &PrometheusRecorder{
+		projectionLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "intervention_projection_lag_seconds",
+			Help:    "Time from an event being produced to it being applied to the read model.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900},
+		}, []string{"event_type"}),
+		iteratorAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kinesis_iterator_age_seconds",
+			Help: "How far the forwarder is behind the tip of each Kinesis shard.",
+		}, []string{"stream", "shard"}),
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "sqs_queue_messages",
+			Help: "Approximate number of messages in an SQS queue, by state (visible or in_flight).",
+		}, []string{"queue", "state"}),
+		deadLetters: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "sqs_dead_letter_messages",
+			Help: "Approximate number of messages in an SQS dead letter queue.",
+		}, []string{"queue"}),
+	}
+	registerer.MustRegister(r.projectionLag, r.iteratorAge, r.queueDepth, r.deadLetters)
+	return r
+}
+
+func (r *PrometheusRecorder) ProjectionLag(eventType string, lag time.Duration) {
+	r.projectionLag.WithLabelValues(eventType).Observe(lag.Seconds())
+}
+
+func (r *PrometheusRecorder) IteratorAge(stream, shardID string, age time.Duration) {
+	r.iteratorAge.WithLabelValues(stream, shardID).Set(age.Seconds())
+}
+
+func (r *PrometheusRecorder) QueueDepth(queue string, visible, inFlight int64) {
+	r.queueDepth.WithLabelValues(queue, "visible").Set(float64(visible))
+	r.queueDepth.WithLabelValues(queue, "in_flight").Set(float64(inFlight))
+}
+
+func (r *PrometheusRecorder) DeadLetters(queue string, count int64) {
+	r.deadLetters.WithLabelValues(queue).Set(float64(count))
+}
+
+// Serve exposes the default registry on addr at /metrics until ctx is
+// cancelled.
+func Serve(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Serving metrics on %s/metrics", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Metrics server stopped: %v", err)
+	}
+}
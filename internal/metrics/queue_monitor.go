@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// QueueMonitor samples the depth of work queues and dead letter queues.
+type QueueMonitor struct {
+	client           *sqs.Client
+	recorder         Recorder
+	queues           []string
+	deadLetterQueues []string
+
+	mu          sync.Mutex
+	lastSampled time.Time
+}
+
+func NewQueueMonitor(client *sqs.Client, recorder Recorder, queues, deadLetterQueues []string) *QueueMonitor {
+	return &QueueMonitor{
+		client:           client,
+		recorder:         recorder,
+		queues:           queues,
+		deadLetterQueues: deadLetterQueues,
+	}
+}
+
+// Run samples every interval until ctx is cancelled.
+func (m *QueueMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Sample(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SampleIfDue samples unless the last sample is younger than interval, for
+// callers such as Lambda handlers that have no loop of their own.
+func (m *QueueMonitor) SampleIfDue(ctx context.Context, interval time.Duration) {
+	m.mu.Lock()
+	due := time.Since(m.lastSampled) >= interval
+	if due {
+		m.lastSampled = time.Now()
+	}
+	m.mu.Unlock()
+
+	if due {
+		m.Sample(ctx)
+	}
+}
+
+// Sample records the current depth of every queue. Queues that cannot be
+// read are logged and skipped.
+func (m *QueueMonitor) Sample(ctx context.Context) {
+	for _, queueURL := range m.queues {
+		attributes, err := m.attributes(ctx, queueURL)
+		if err != nil {
+			log.Printf("Failed to read attributes of %s: %v", queueURL, err)
+			continue
+		}
+		m.recorder.QueueDepth(QueueName(queueURL),
+			attributes[types.QueueAttributeNameApproximateNumberOfMessages],
+			attributes[types.QueueAttributeNameApproximateNumberOfMessagesNotVisible])
+	}
+	for _, queueURL := range m.deadLetterQueues {
+		attributes, err := m.attributes(ctx, queueURL)
+		if err != nil {
+			log.Printf("Failed to read attributes of %s: %v", queueURL, err)
+			continue
+		}
+		m.recorder.DeadLetters(QueueName(queueURL), attributes[types.QueueAttributeNameApproximateNumberOfMessages])
+	}
+}
+
+func (m *QueueMonitor) attributes(ctx context.Context, queueURL string) (map[types.QueueAttributeName]int64, error) {
+	resp, err := m.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeNameApproximateNumberOfMessages,
+			types.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[types.QueueAttributeName]int64{}
+	for name, value := range resp.Attributes {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		counts[types.QueueAttributeName(name)] = count
+	}
+	return counts, nil
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lambda/internal/events"
+	"github.com/lambda/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultBatchSize   = 50
+	DefaultMaxAttempts = 10
+	// DefaultPublishTimeout bounds each publish. Publishing happens while
+	// the batch's rows are locked, so a stalled publisher must not hold the
+	// locks, and the messages queued behind them, indefinitely.
+	DefaultPublishTimeout = 10 * time.Second
+)
+
+// Relay drains the outbox table to an event publisher. A message is marked
+// published only after the publisher accepts it, so delivery is at least
+// once: a crash between the two steps republishes the message on the next
+// run and consumers are expected to deduplicate on event_id.
+type Relay struct {
+	repo           *repository.OutboxRepository
+	publisher      events.EventPublisher
+	batchSize      int
+	maxAttempts    int
+	publishTimeout time.Duration
+}
+
+func NewRelay(repo *repository.OutboxRepository, publisher events.EventPublisher, batchSize, maxAttempts int, publishTimeout time.Duration) *Relay {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if publishTimeout <= 0 {
+		publishTimeout = DefaultPublishTimeout
+	}
+	return &Relay{
+		repo:           repo,
+		publisher:      publisher,
+		batchSize:      batchSize,
+		maxAttempts:    maxAttempts,
+		publishTimeout: publishTimeout,
+	}
+}
+
+// DrainBatch publishes one batch of messages and returns how many were
+// published and how many failed. A batch holds at most the next message of
+// each aggregate (see LockPending), so messages of one aggregate are
+// published in order across batches and relays. Messages that fail to
+// publish are marked failed and retried on later runs; the attempt that
+// reaches the limit makes them dead. Either way the later messages of the
+// aggregate wait behind them.
+func (r *Relay) DrainBatch(ctx context.Context) (published, failed int, err error) {
+	err = r.repo.Transaction(ctx, func(tx *gorm.DB) error {
+		repo := r.repo.WithTx(tx)
+
+		messages, err := repo.LockPending(ctx, r.batchSize, r.maxAttempts)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if err := r.publish(ctx, message); err != nil {
+				log.Printf("Failed to publish outbox message %s: %v", message.ID, err)
+				failed++
+				if err := repo.MarkFailed(ctx, message.ID, err, r.maxAttempts); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := repo.MarkPublished(ctx, message.ID, time.Now().UTC()); err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	return published, failed, err
+}
+
+// Drain publishes batches until one publishes nothing, because the outbox
+// is empty or everything left is blocked, or until one has failures, which
+// should wait for the next run rather than be retried straight away.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		published, failed, err := r.DrainBatch(ctx)
+		total += published
+		if err != nil {
+			return total, err
+		}
+		if published == 0 || failed > 0 {
+			return total, nil
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, message *repository.OutboxMessage) error {
+	event, err := message.Event()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.publishTimeout)
+	defer cancel()
+	return r.publisher.Publish(ctx, event)
+}
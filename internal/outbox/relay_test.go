@@ -0,0 +1,234 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/events"
+	"github.com/lambda/internal/repository"
+)
+
+// These tests need a migrated write model at TEST_DATABASE_URL. Each runs
+// in a transaction that is rolled back, on an outbox emptied inside it.
+func outboxDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	if err := tx.Exec("DELETE FROM outbox").Error; err != nil {
+		t.Fatalf("empty outbox: %v", err)
+	}
+	return tx
+}
+
+// enqueue stores pending messages for aggregates, one second apart in the
+// order given, each named by its event ID.
+func enqueue(t *testing.T, db *gorm.DB, messages ...[2]string) {
+	t.Helper()
+	start := time.Now().Add(-time.Hour).UTC()
+	for i, message := range messages {
+		id, aggregateID := message[0], message[1]
+		payload, err := json.Marshal(&events.DomainEvent{
+			EventID:     id,
+			EventType:   events.InterventionUpdated,
+			AggregateID: aggregateID,
+			TenantID:    "tenant-a",
+		})
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		err = db.Create(&repository.OutboxMessage{
+			ID:          id,
+			AggregateID: aggregateID,
+			TenantID:    "tenant-a",
+			EventType:   string(events.InterventionUpdated),
+			Payload:     string(payload),
+			Status:      repository.OutboxStatusPending,
+			CreatedAt:   start.Add(time.Duration(i) * time.Second),
+		}).Error
+		if err != nil {
+			t.Fatalf("enqueue %s: %v", id, err)
+		}
+	}
+}
+
+// recorder publishes every event except those it is told to fail.
+type recorder struct {
+	published []string
+	fail      map[string]bool
+}
+
+func (r *recorder) Publish(ctx context.Context, event *events.DomainEvent) error {
+	if r.fail[event.EventID] {
+		return errors.New("stream unavailable")
+	}
+	r.published = append(r.published, event.EventID)
+	return nil
+}
+
+func status(t *testing.T, db *gorm.DB, id string) string {
+	t.Helper()
+	var message repository.OutboxMessage
+	if err := db.First(&message, "id = ?", id).Error; err != nil {
+		t.Fatalf("find %s: %v", id, err)
+	}
+	return message.Status
+}
+
+func drainBatch(t *testing.T, relay *Relay) (int, int) {
+	t.Helper()
+	published, failed, err := relay.DrainBatch(context.Background())
+	if err != nil {
+		t.Fatalf("DrainBatch: %v", err)
+	}
+	return published, failed
+}
+
+func TestRelayPublishesEachAggregateInOrder(t *testing.T) {
+	db := outboxDB(t)
+	enqueue(t, db, [2]string{"a1", "a"}, [2]string{"b1", "b"}, [2]string{"a2", "a"}, [2]string{"a3", "a"})
+	publisher := &recorder{}
+	relay := NewRelay(repository.NewOutboxRepository(db), publisher, 10, 3, time.Second)
+
+	// A batch takes only the next message of each aggregate.
+	if published, _ := drainBatch(t, relay); published != 2 {
+		t.Fatalf("first batch published %d, want 2", published)
+	}
+	if want := []string{"a1", "b1"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+
+	if _, err := relay.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if want := []string{"a1", "b1", "a2", "a3"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+}
+
+func TestFailedMessageBlocksItsAggregate(t *testing.T) {
+	db := outboxDB(t)
+	enqueue(t, db, [2]string{"a1", "a"}, [2]string{"a2", "a"}, [2]string{"b1", "b"})
+	publisher := &recorder{fail: map[string]bool{"a1": true}}
+	relay := NewRelay(repository.NewOutboxRepository(db), publisher, 10, 5, time.Second)
+
+	for run := 0; run < 3; run++ {
+		drainBatch(t, relay)
+	}
+	if want := []string{"b1"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+	if got := status(t, db, "a1"); got != repository.OutboxStatusFailed {
+		t.Errorf("a1 is %s, want failed", got)
+	}
+	if got := status(t, db, "a2"); got != repository.OutboxStatusPending {
+		t.Errorf("a2 is %s, want pending behind a1", got)
+	}
+
+	// Once a1 goes through, a2 follows it.
+	publisher.fail = nil
+	if _, err := relay.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if want := []string{"b1", "a1", "a2"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+}
+
+func TestDeadMessageBlocksItsAggregateUntilDiscarded(t *testing.T) {
+	db := outboxDB(t)
+	repo := repository.NewOutboxRepository(db)
+	enqueue(t, db, [2]string{"a1", "a"}, [2]string{"a2", "a"})
+	publisher := &recorder{fail: map[string]bool{"a1": true}}
+	relay := NewRelay(repo, publisher, 10, 2, time.Second)
+
+	drainBatch(t, relay)
+	drainBatch(t, relay)
+	if got := status(t, db, "a1"); got != repository.OutboxStatusDead {
+		t.Fatalf("a1 is %s after its last attempt, want dead", got)
+	}
+
+	// A dead message is no longer tried, and still holds back a2.
+	if published, failed := drainBatch(t, relay); published != 0 || failed != 0 {
+		t.Fatalf("batch after a1 died published %d and failed %d, want nothing", published, failed)
+	}
+	dead, err := repo.ListDead(context.Background())
+	if err != nil {
+		t.Fatalf("ListDead: %v", err)
+	}
+	if len(dead) != 1 || dead[0].ID != "a1" {
+		t.Fatalf("dead messages = %v, want a1", dead)
+	}
+
+	if err := repo.Discard(context.Background(), "a1"); err != nil {
+		t.Fatalf("Discard: %v", err)
+	}
+	if _, err := relay.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if want := []string{"a2"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+	if got := status(t, db, "a1"); got != repository.OutboxStatusDiscarded {
+		t.Errorf("a1 is %s, want discarded", got)
+	}
+}
+
+func TestRequeuedDeadMessageIsPublishedFirst(t *testing.T) {
+	db := outboxDB(t)
+	repo := repository.NewOutboxRepository(db)
+	enqueue(t, db, [2]string{"a1", "a"}, [2]string{"a2", "a"})
+	publisher := &recorder{fail: map[string]bool{"a1": true}}
+	relay := NewRelay(repo, publisher, 10, 1, time.Second)
+
+	drainBatch(t, relay)
+	if got := status(t, db, "a1"); got != repository.OutboxStatusDead {
+		t.Fatalf("a1 is %s, want dead", got)
+	}
+
+	publisher.fail = nil
+	if err := repo.Requeue(context.Background(), "a1"); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	if _, err := relay.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if want := []string{"a1", "a2"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+}
+
+func TestOnlyDeadMessagesCanBeRequeuedOrDiscarded(t *testing.T) {
+	db := outboxDB(t)
+	repo := repository.NewOutboxRepository(db)
+	enqueue(t, db, [2]string{"a1", "a"})
+
+	if err := repo.Discard(context.Background(), "a1"); err == nil {
+		t.Error("Discard of a pending message succeeded")
+	}
+	if err := repo.Requeue(context.Background(), "a1"); err == nil {
+		t.Error("Requeue of a pending message succeeded")
+	}
+	if got := status(t, db, "a1"); got != repository.OutboxStatusPending {
+		t.Errorf("a1 is %s, want pending", got)
+	}
+}
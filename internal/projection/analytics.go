@@ -0,0 +1,254 @@
+package projection
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Pre-aggregated dashboards kept alongside interventions_projection.
+// Barrier and referral counts are per tenant, month of creation (UTC) and
+// intervention type; workload counts are per tenant, assignee, team, status
+// and due date, so overdue counts follow the calendar without events.
+const (
+	BarrierCountsTable  = "intervention_barrier_counts"
+	ReferralCountsTable = "intervention_referral_counts"
+	WorkloadCountsTable = "intervention_workload_counts"
+)
+
+// analyticsRow holds the columns of an intervention the analytics depend
+// on.
+type analyticsRow struct {
+	TenantID        string
+	Type            string
+	Status          string
+	AssignedTo      *string
+	AssignedTeam    *string
+	DueAt           *time.Time
+	CreatedAt       time.Time
+	Problems        pq.StringArray
+	ReferralReasons pq.StringArray
+}
+
+type monthlyKey struct {
+	tenantID string
+	month    string
+	kind     string
+	label    string
+}
+
+type workloadKey struct {
+	tenantID     string
+	assignedTo   string
+	assignedTeam string
+	status       string
+	dueOn        string
+}
+
+// analyticsDelta is the net change to the aggregates from one row change.
+// Buckets an update leaves alone cancel out and are not written.
+type analyticsDelta struct {
+	barriers  map[monthlyKey]int
+	referrals map[monthlyKey]int
+	workload  map[workloadKey]int
+}
+
+func newAnalyticsDelta() *analyticsDelta {
+	return &analyticsDelta{
+		barriers:  map[monthlyKey]int{},
+		referrals: map[monthlyKey]int{},
+		workload:  map[workloadKey]int{},
+	}
+}
+
+// add counts row towards its buckets with sign +1, or takes it off with -1.
+// A problem or reason listed twice counts once.
+func (d *analyticsDelta) add(row *analyticsRow, sign int) {
+	if row == nil {
+		return
+	}
+	month := row.CreatedAt.UTC().Format("2006-01") + "-01"
+	for _, problem := range distinct(row.Problems) {
+		d.barriers[monthlyKey{row.TenantID, month, row.Type, problem}] += sign
+	}
+	for _, reason := range distinct(row.ReferralReasons) {
+		d.referrals[monthlyKey{row.TenantID, month, row.Type, reason}] += sign
+	}
+
+	dueOn := "infinity"
+	if row.DueAt != nil {
+		dueOn = row.DueAt.UTC().Format("2006-01-02")
+	}
+	d.workload[workloadKey{row.TenantID, orEmpty(row.AssignedTo), orEmpty(row.AssignedTeam), row.Status, dueOn}] += sign
+}
+
+// analyticsSnapshot reads and locks the row before an event changes it. It
+// returns nil when the projector does not maintain analytics or the row
+// does not exist yet.
+func (p *InterventionProjector) analyticsSnapshot(tx *gorm.DB, interventionID, tenantID string) (*analyticsRow, error) {
+	if !p.analytics {
+		return nil, nil
+	}
+	var rows []*analyticsRow
+	err := tx.Raw("SELECT tenant_id, type, status, assigned_to, assigned_team, due_at, created_at, problems, referral_reasons FROM "+p.table+
+		" WHERE id = ? AND tenant_id = ? FOR UPDATE", interventionID, tenantID).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return rows[0], nil
+}
+
+// updateAnalytics moves the row's contribution to the aggregates from its
+// state before the event to its state now. It runs in the transaction that
+// changed the row, only once the change has been applied, so skipped
+// duplicate events never count twice.
+func (p *InterventionProjector) updateAnalytics(tx *gorm.DB, before *analyticsRow, interventionID, tenantID string) error {
+	if !p.analytics {
+		return nil
+	}
+	after, err := p.analyticsSnapshot(tx, interventionID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	delta := newAnalyticsDelta()
+	delta.add(before, -1)
+	delta.add(after, 1)
+	return delta.apply(tx)
+}
+
+func (d *analyticsDelta) apply(tx *gorm.DB) error {
+	for _, key := range sortedKeys(d.barriers) {
+		if err := applyMonthly(tx, BarrierCountsTable, "problem", key, d.barriers[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(d.referrals) {
+		if err := applyMonthly(tx, ReferralCountsTable, "referral_reason", key, d.referrals[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(d.workload) {
+		change := d.workload[key]
+		if change == 0 {
+			continue
+		}
+		err := tx.Exec(`INSERT INTO `+WorkloadCountsTable+` AS c (tenant_id, assigned_to, assigned_team, status, due_on, intervention_count)
+			VALUES (?, ?, ?, ?, ?::date, ?)
+			ON CONFLICT (tenant_id, assigned_to, assigned_team, status, due_on)
+			DO UPDATE SET intervention_count = c.intervention_count + EXCLUDED.intervention_count`,
+			key.tenantID, key.assignedTo, key.assignedTeam, key.status, key.dueOn, change).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`DELETE FROM `+WorkloadCountsTable+`
+			WHERE tenant_id = ? AND assigned_to = ? AND assigned_team = ? AND status = ? AND due_on = ?::date AND intervention_count <= 0`,
+			key.tenantID, key.assignedTo, key.assignedTeam, key.status, key.dueOn).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyMonthly(tx *gorm.DB, table, column string, key monthlyKey, change int) error {
+	if change == 0 {
+		return nil
+	}
+	err := tx.Exec(`INSERT INTO `+table+` AS c (tenant_id, month, type, `+column+`, intervention_count)
+		VALUES (?, ?::date, ?, ?, ?)
+		ON CONFLICT (tenant_id, month, type, `+column+`)
+		DO UPDATE SET intervention_count = c.intervention_count + EXCLUDED.intervention_count`,
+		key.tenantID, key.month, key.kind, key.label, change).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec(`DELETE FROM `+table+`
+		WHERE tenant_id = ? AND month = ?::date AND type = ? AND `+column+` = ? AND intervention_count <= 0`,
+		key.tenantID, key.month, key.kind, key.label).Error
+}
+
+// RebuildAnalytics recomputes the aggregates from interventions_projection,
+// for one tenant or, with an empty tenantID, for all of them. The aggregate
+// tables are locked while they are rebuilt, so the worker's updates wait
+// and then apply on top of the rebuilt counts.
+func RebuildAnalytics(ctx context.Context, db *gorm.DB, tenantID string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE " + BarrierCountsTable + ", " + ReferralCountsTable + ", " + WorkloadCountsTable + " IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		where, args := "", []interface{}{}
+		if tenantID != "" {
+			where, args = " WHERE tenant_id = ?", []interface{}{tenantID}
+		}
+		for _, statement := range []string{
+			"DELETE FROM " + BarrierCountsTable + where,
+			"DELETE FROM " + ReferralCountsTable + where,
+			"DELETE FROM " + WorkloadCountsTable + where,
+			`INSERT INTO ` + BarrierCountsTable + ` (tenant_id, month, type, problem, intervention_count)
+				SELECT tenant_id, date_trunc('month', created_at AT TIME ZONE 'UTC')::date, type, problem, count(DISTINCT id)
+				FROM ` + ProjectionTable + `, unnest(problems) AS problem` + where + `
+				GROUP BY 1, 2, 3, 4`,
+			`INSERT INTO ` + ReferralCountsTable + ` (tenant_id, month, type, referral_reason, intervention_count)
+				SELECT tenant_id, date_trunc('month', created_at AT TIME ZONE 'UTC')::date, type, reason, count(DISTINCT id)
+				FROM ` + ProjectionTable + `, unnest(referral_reasons) AS reason` + where + `
+				GROUP BY 1, 2, 3, 4`,
+			`INSERT INTO ` + WorkloadCountsTable + ` (tenant_id, assigned_to, assigned_team, status, due_on, intervention_count)
+				SELECT tenant_id, COALESCE(assigned_to, ''), COALESCE(assigned_team, ''), status,
+					COALESCE((due_at AT TIME ZONE 'UTC')::date, 'infinity'::date), count(*)
+				FROM ` + ProjectionTable + where + `
+				GROUP BY 1, 2, 3, 4, 5`,
+		} {
+			if err := tx.Exec(statement, args...).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func distinct(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func orEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func (k monthlyKey) sortKey() string {
+	return k.tenantID + "\x00" + k.month + "\x00" + k.kind + "\x00" + k.label
+}
+
+func (k workloadKey) sortKey() string {
+	return k.tenantID + "\x00" + k.assignedTo + "\x00" + k.assignedTeam + "\x00" + k.status + "\x00" + k.dueOn
+}
+
+// sortedKeys gives the upserts a fixed order, so two workers updating the
+// same buckets lock them in the same order and cannot deadlock.
+func sortedKeys[K interface {
+	comparable
+	sortKey() string
+}](deltas map[K]int) []K {
+	keys := make([]K, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].sortKey() < keys[j].sortKey() })
+	return keys
+}
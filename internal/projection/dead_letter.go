@@ -0,0 +1,58 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/lambda/internal/events"
+)
+
+// DeadLetterQueue receives events that can never be applied, with the
+// rejection reason attached as message attributes, so they stop being
+// redelivered ahead of the events behind them.
+type DeadLetterQueue struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+func NewDeadLetterQueue(client *sqs.Client, queueURL string) *DeadLetterQueue {
+	return &DeadLetterQueue{client: client, queueURL: queueURL}
+}
+
+func (q *DeadLetterQueue) Send(ctx context.Context, messageID, body string, reason error) error {
+	attributes := map[string]types.MessageAttributeValue{
+		"failure_reason": {DataType: aws.String("String"), StringValue: aws.String(reason.Error())},
+	}
+	var invalid *events.InvalidEventError
+	if errors.As(reason, &invalid) {
+		// failure_detail leaves out the event ID so rejections can be
+		// grouped by cause.
+		attributes["failure_detail"] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(invalid.Reason)}
+		if invalid.EventType != "" {
+			attributes["event_type"] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(invalid.EventType)}
+		}
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(q.queueURL),
+		MessageBody:       aws.String(body),
+		MessageAttributes: attributes,
+	}
+	// A FIFO DLQ needs a group; rejected messages are unrelated to each
+	// other, so each one gets its own.
+	if strings.HasSuffix(q.queueURL, ".fifo") {
+		input.MessageGroupId = aws.String(messageID)
+		input.MessageDeduplicationId = aws.String(messageID)
+	}
+
+	_, err := q.client.SendMessage(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter message %s: %w", messageID, err)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package projection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lambda/internal/events"
+	"github.com/lambda/internal/requestctx"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// InterventionProjector applies intervention domain events to the
+// interventions_projection table in the read model. It is shared by the
+// Lambda and standalone builds of the intervention event worker.
+type InterventionProjector struct {
+	db    *gorm.DB
+	table string
+	// analytics is set when the projector also maintains the aggregate
+	// tables in analytics.go.
+	analytics bool
+}
+
+// ProjectionTable is the table the projector writes to by default.
+const ProjectionTable = "interventions_projection"
+
+func NewInterventionProjector(db *gorm.DB) *InterventionProjector {
+	return &InterventionProjector{db: db, table: ProjectionTable, analytics: true}
+}
+
+// WithTable returns a projector that writes to a table with the same
+// columns as interventions_projection, such as one being rebuilt
+// side by side. It leaves the aggregate tables alone; rebuild them with
+// RebuildAnalytics once the table is live.
+func (p *InterventionProjector) WithTable(table string) *InterventionProjector {
+	return &InterventionProjector{db: p.db, table: table}
+}
+
+// DecodeEvent parses a queue message body into a domain event. Bodies that
+// are not valid events are reported as *events.InvalidEventError.
+func DecodeEvent(messageID string, body []byte) (*events.DomainEvent, error) {
+	var event events.DomainEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, &events.InvalidEventError{EventID: messageID, EventType: "unknown", Reason: err.Error()}
+	}
+	if len(event.Payload) == 0 {
+		return nil, &events.InvalidEventError{EventID: event.EventID, EventType: string(event.EventType), Reason: "missing payload"}
+	}
+	return &event, nil
+}
+
+// Apply projects one event. The context it works in carries the event's
+// trace, so anything the work leads to keeps its correlation ID and names
+// the event as the cause.
+func (p *InterventionProjector) Apply(ctx context.Context, event *events.DomainEvent) error {
+	ctx = requestctx.With(ctx, requestctx.FromEvent(event))
+
+	payload, err := events.DefaultRegistry.Decode(event)
+	if errors.Is(err, events.ErrUnregisteredEventType) {
+		log.Printf("Unknown event type: %s", event.EventType)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch payload := payload.(type) {
+	case *events.InterventionCreatedEvent:
+		return p.handleCreated(ctx, event, payload)
+	case *events.InterventionUpdatedEvent:
+		return p.handleUpdated(ctx, event, payload)
+	case *events.InterventionCompletedEvent:
+		return p.handleCompleted(ctx, event, payload)
+	case *events.InterventionCancelledEvent:
+		return p.handleCancelled(ctx, event, payload)
+	case *events.InterventionStartedEvent:
+		return p.handleStarted(ctx, event, payload)
+	case *events.InterventionReopenedEvent:
+		return p.handleReopened(ctx, event, payload)
+	default:
+		log.Printf("No projection for event type: %s", event.EventType)
+		return nil
+	}
+}
+
+func (p *InterventionProjector) handleCreated(ctx context.Context, event *events.DomainEvent, created *events.InterventionCreatedEvent) error {
+	// A redelivered created event hits the primary key and is skipped.
+	query := `INSERT INTO ` + p.table + `
+		(id, tenant_id, patient_id, screening_id, type, title, description, status, priority,
+		 created_by, assigned_to, assigned_team, due_at, referral_reasons, problems,
+		 created_at, updated_at, last_event_id, last_sequence)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`
+
+	var result *gorm.DB
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result = tx.Exec(query,
+			created.InterventionID,
+			created.TenantID,
+			created.PatientID,
+			created.ScreeningID,
+			created.Type,
+			created.Title,
+			created.Description,
+			created.Status,
+			created.Priority,
+			created.CreatedBy,
+			created.AssignedTo,
+			created.AssignedTeam,
+			created.DueAt,
+			pq.Array(nonNil(created.ReferralReasons)),
+			pq.Array(nonNil(created.Problems)),
+			created.CreatedAt,
+			created.CreatedAt,
+			event.EventID,
+			event.Sequence,
+		)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := p.refreshSearchVector(tx, created.InterventionID, created.TenantID); err != nil {
+			return err
+		}
+		return p.updateAnalytics(tx, nil, created.InterventionID, created.TenantID)
+	})
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		log.Printf("Skipping duplicate created event %s for intervention %s", event.EventID, created.InterventionID)
+		return nil
+	}
+
+	log.Printf("Created intervention projection: %s", created.InterventionID)
+	return nil
+}
+
+func (p *InterventionProjector) handleUpdated(ctx context.Context, event *events.DomainEvent, updated *events.InterventionUpdatedEvent) error {
+	// Column names are fixed here; nothing from the payload ends up in SQL.
+	changes := updated.UpdatedFields
+	setParts := []string{"updated_at = ?"}
+	values := []interface{}{updated.UpdatedAt}
+	if changes.AssignedTo != nil {
+		setParts = append(setParts, "assigned_to = ?")
+		values = append(values, *changes.AssignedTo)
+	}
+	if changes.AssignedTeam != nil {
+		setParts = append(setParts, "assigned_team = ?")
+		values = append(values, *changes.AssignedTeam)
+	}
+	if changes.Priority != nil {
+		setParts = append(setParts, "priority = ?")
+		values = append(values, *changes.Priority)
+	}
+	if changes.Notes != nil {
+		setParts = append(setParts, "notes = ?")
+		values = append(values, *changes.Notes)
+	}
+	if changes.Problems != nil {
+		setParts = append(setParts, "problems = ?")
+		values = append(values, pq.Array(*changes.Problems))
+	}
+
+	if err := p.applyUpdate(ctx, event, updated.InterventionID, setParts, values); err != nil {
+		return err
+	}
+
+	log.Printf("Updated intervention projection: %s", updated.InterventionID)
+	return nil
+}
+
+func (p *InterventionProjector) handleCompleted(ctx context.Context, event *events.DomainEvent, completed *events.InterventionCompletedEvent) error {
+	setParts := []string{"status = ?", "completed_at = ?", "updated_at = ?"}
+	values := []interface{}{"completed", completed.CompletedAt, completed.CompletedAt}
+	if completed.Notes != nil {
+		setParts = append(setParts, "notes = ?")
+		values = append(values, *completed.Notes)
+	}
+
+	if err := p.applyUpdate(ctx, event, completed.InterventionID, setParts, values); err != nil {
+		return err
+	}
+
+	log.Printf("Completed intervention projection: %s", completed.InterventionID)
+	return nil
+}
+
+func (p *InterventionProjector) handleCancelled(ctx context.Context, event *events.DomainEvent, cancelled *events.InterventionCancelledEvent) error {
+	setParts := []string{"status = ?", "updated_at = ?"}
+	values := []interface{}{"cancelled", cancelled.CancelledAt}
+	if cancelled.Reason != nil {
+		setParts = append(setParts, "notes = ?")
+		values = append(values, *cancelled.Reason)
+	}
+
+	if err := p.applyUpdate(ctx, event, cancelled.InterventionID, setParts, values); err != nil {
+		return err
+	}
+
+	log.Printf("Cancelled intervention projection: %s", cancelled.InterventionID)
+	return nil
+}
+
+func (p *InterventionProjector) handleStarted(ctx context.Context, event *events.DomainEvent, started *events.InterventionStartedEvent) error {
+	setParts := []string{"status = ?", "updated_at = ?"}
+	values := []interface{}{"in_progress", started.StartedAt}
+
+	if err := p.applyUpdate(ctx, event, started.InterventionID, setParts, values); err != nil {
+		return err
+	}
+
+	log.Printf("Started intervention projection: %s", started.InterventionID)
+	return nil
+}
+
+func (p *InterventionProjector) handleReopened(ctx context.Context, event *events.DomainEvent, reopened *events.InterventionReopenedEvent) error {
+	setParts := []string{"status = ?", "completed_at = NULL", "updated_at = ?"}
+	values := []interface{}{"pending", reopened.ReopenedAt}
+	if reopened.Reason != nil {
+		setParts = append(setParts, "notes = ?")
+		values = append(values, *reopened.Reason)
+	}
+
+	if err := p.applyUpdate(ctx, event, reopened.InterventionID, setParts, values); err != nil {
+		return err
+	}
+
+	log.Printf("Reopened intervention projection: %s", reopened.InterventionID)
+	return nil
+}
+
+// applyUpdate applies an event's SET clauses only if the event is the next
+// one in the intervention's sequence. Duplicate and stale events are
+// skipped. An event that arrives ahead of a gap, or before the
+// intervention's created event, returns an error so the queue redelivers it
+// after the missing event has been applied. Events published before
+// sequence numbers existed carry sequence 0 and are deduplicated on
+// event_id only.
+func (p *InterventionProjector) applyUpdate(ctx context.Context, event *events.DomainEvent, interventionID string, setParts []string, values []interface{}) error {
+	setParts = append(setParts, "last_event_id = ?", "last_sequence = ?")
+	values = append(values, event.EventID, event.Sequence)
+
+	query := "UPDATE " + p.table + " SET " + strings.Join(setParts, ", ") +
+		" WHERE id = ? AND tenant_id = ?"
+	values = append(values, interventionID, event.TenantID)
+	if event.Sequence > 0 {
+		query += " AND last_sequence = ?"
+		values = append(values, event.Sequence-1)
+	} else {
+		query += " AND last_event_id IS DISTINCT FROM ?"
+		values = append(values, event.EventID)
+	}
+
+	var result *gorm.DB
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := p.analyticsSnapshot(tx, interventionID, event.TenantID)
+		if err != nil {
+			return err
+		}
+		result = tx.Exec(query, values...)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := p.refreshSearchVector(tx, interventionID, event.TenantID); err != nil {
+			return err
+		}
+		return p.updateAnalytics(tx, before, interventionID, event.TenantID)
+	})
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var current struct {
+		LastEventID  *string
+		LastSequence int64
+	}
+	lookup := p.db.WithContext(ctx).
+		Raw("SELECT last_event_id, last_sequence FROM "+p.table+" WHERE id = ? AND tenant_id = ?", interventionID, event.TenantID).
+		Scan(&current)
+	if lookup.Error != nil {
+		return lookup.Error
+	}
+	if lookup.RowsAffected == 0 {
+		return fmt.Errorf("intervention projection %s not found for event %s", interventionID, event.EventID)
+	}
+	if event.Sequence == 0 || current.LastSequence >= event.Sequence {
+		log.Printf("Skipping already applied event %s (sequence %d) for intervention %s at sequence %d",
+			event.EventID, event.Sequence, interventionID, current.LastSequence)
+		return nil
+	}
+	return fmt.Errorf("event %s for intervention %s has sequence %d but projection is at %d",
+		event.EventID, interventionID, event.Sequence, current.LastSequence)
+}
+
+// nonNil keeps NOT NULL array columns from receiving NULL when a payload
+// omitted the list.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
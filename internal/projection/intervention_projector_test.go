@@ -0,0 +1,229 @@
+package projection
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/events"
+)
+
+// These tests need a migrated database at TEST_DATABASE_URL. Each runs in a
+// transaction that is rolled back.
+func projectionDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+type projectedRow struct {
+	Title        string
+	Priority     string
+	Status       string
+	LastEventID  *string
+	LastSequence int64
+}
+
+func projected(t *testing.T, db *gorm.DB, id string) projectedRow {
+	t.Helper()
+	var row projectedRow
+	if err := db.Table(ProjectionTable).Where("id = ?", id).Take(&row).Error; err != nil {
+		t.Fatalf("read projection of %s: %v", id, err)
+	}
+	return row
+}
+
+// history builds the events of one intervention, numbering them in the
+// order they are made.
+type history struct {
+	t        *testing.T
+	id       string
+	tenantID string
+	sequence int64
+}
+
+func newHistory(t *testing.T) *history {
+	return &history{t: t, id: uuid.NewString(), tenantID: "tenant-" + uuid.NewString()}
+}
+
+func (h *history) next(event *events.DomainEvent, err error) *events.DomainEvent {
+	h.t.Helper()
+	if err != nil {
+		h.t.Fatalf("build event: %v", err)
+	}
+	h.sequence++
+	event.Sequence = h.sequence
+	return event
+}
+
+func (h *history) created(title string) *events.DomainEvent {
+	return h.next(events.NewInterventionCreatedEvent(&events.InterventionCreatedEvent{
+		InterventionID:  h.id,
+		TenantID:        h.tenantID,
+		PatientID:       "patient-1",
+		ScreeningID:     "screening-1",
+		Type:            domain.TypeSocialWork,
+		Title:           title,
+		Status:          domain.StatusPending,
+		Priority:        "medium",
+		CreatedBy:       "navigator-1",
+		ReferralReasons: []string{},
+		Problems:        []string{"food insecurity"},
+		CreatedAt:       time.Now().UTC(),
+	}))
+}
+
+func (h *history) prioritized(priority string) *events.DomainEvent {
+	return h.next(events.NewInterventionUpdatedEvent(&events.InterventionUpdatedEvent{
+		InterventionID: h.id,
+		TenantID:       h.tenantID,
+		UpdatedFields:  events.InterventionFieldChanges{Priority: &priority},
+		UpdatedAt:      time.Now().UTC(),
+	}))
+}
+
+func apply(t *testing.T, projector *InterventionProjector, event *events.DomainEvent) {
+	t.Helper()
+	if err := projector.Apply(context.Background(), event); err != nil {
+		t.Fatalf("Apply %s (sequence %d): %v", event.EventType, event.Sequence, err)
+	}
+}
+
+func TestDuplicateCreatedEventIsNoOp(t *testing.T) {
+	db := projectionDB(t)
+	projector := NewInterventionProjector(db)
+	h := newHistory(t)
+	created := h.created("Food bank referral")
+	apply(t, projector, created)
+
+	// A redelivery, and a created event for the same intervention that
+	// says something else, both leave the row alone.
+	apply(t, projector, created)
+	h.sequence = 0
+	apply(t, projector, h.created("Something else"))
+
+	row := projected(t, db, h.id)
+	if row.Title != "Food bank referral" || row.LastSequence != 1 || row.LastEventID == nil || *row.LastEventID != created.EventID {
+		t.Fatalf("projection = %+v, want the first created event's", row)
+	}
+}
+
+func TestRedeliveredEventIsNoOp(t *testing.T) {
+	db := projectionDB(t)
+	projector := NewInterventionProjector(db)
+	h := newHistory(t)
+	apply(t, projector, h.created("Food bank referral"))
+	high := h.prioritized("high")
+	low := h.prioritized("low")
+	apply(t, projector, high)
+	apply(t, projector, low)
+
+	apply(t, projector, high)
+	apply(t, projector, low)
+
+	row := projected(t, db, h.id)
+	if row.Priority != "low" || row.LastSequence != 3 || *row.LastEventID != low.EventID {
+		t.Fatalf("projection = %+v, want priority low at sequence 3", row)
+	}
+}
+
+func TestEventAheadOfAGapIsRetried(t *testing.T) {
+	db := projectionDB(t)
+	projector := NewInterventionProjector(db)
+	h := newHistory(t)
+	apply(t, projector, h.created("Food bank referral"))
+	high := h.prioritized("high")
+	low := h.prioritized("low")
+
+	// Sequence 3 arrives before 2: it fails, so the queue redelivers it,
+	// and the projection stays at 1.
+	if err := projector.Apply(context.Background(), low); err == nil {
+		t.Fatal("Apply of an event ahead of a gap succeeded")
+	}
+	if row := projected(t, db, h.id); row.Priority != "medium" || row.LastSequence != 1 {
+		t.Fatalf("projection = %+v, want it untouched at sequence 1", row)
+	}
+
+	apply(t, projector, high)
+	apply(t, projector, low)
+	if row := projected(t, db, h.id); row.Priority != "low" || row.LastSequence != 3 {
+		t.Fatalf("projection = %+v, want priority low at sequence 3", row)
+	}
+}
+
+func TestStaleEventIsSkipped(t *testing.T) {
+	db := projectionDB(t)
+	projector := NewInterventionProjector(db)
+	h := newHistory(t)
+	apply(t, projector, h.created("Food bank referral"))
+	high := h.prioritized("high")
+	low := h.prioritized("low")
+	apply(t, projector, high)
+	apply(t, projector, low)
+
+	// An older event with a new ID, as a replay from another source would
+	// carry, does not roll the projection back.
+	stale := *high
+	stale.EventID = uuid.NewString()
+	apply(t, projector, &stale)
+
+	if row := projected(t, db, h.id); row.Priority != "low" || row.LastSequence != 3 {
+		t.Fatalf("projection = %+v, want priority low at sequence 3", row)
+	}
+}
+
+func TestEventBeforeCreatedIsRetried(t *testing.T) {
+	db := projectionDB(t)
+	projector := NewInterventionProjector(db)
+	h := newHistory(t)
+	created := h.created("Food bank referral")
+	high := h.prioritized("high")
+
+	if err := projector.Apply(context.Background(), high); err == nil {
+		t.Fatal("Apply of an update before its intervention was created succeeded")
+	}
+	apply(t, projector, created)
+	apply(t, projector, high)
+	if row := projected(t, db, h.id); row.Priority != "high" || row.LastSequence != 2 {
+		t.Fatalf("projection = %+v, want priority high at sequence 2", row)
+	}
+}
+
+func TestUnsequencedEventIsDeduplicatedByID(t *testing.T) {
+	db := projectionDB(t)
+	projector := NewInterventionProjector(db)
+	h := newHistory(t)
+	apply(t, projector, h.created("Food bank referral"))
+
+	// Events published before sequence numbers carry sequence 0.
+	high := h.prioritized("high")
+	high.Sequence = 0
+	apply(t, projector, high)
+	if err := db.Table(ProjectionTable).Where("id = ?", h.id).Update("priority", "medium").Error; err != nil {
+		t.Fatalf("reset priority: %v", err)
+	}
+	apply(t, projector, high)
+
+	if row := projected(t, db, h.id); row.Priority != "medium" || *row.LastEventID != high.EventID {
+		t.Fatalf("projection = %+v, want the redelivery skipped", row)
+	}
+}
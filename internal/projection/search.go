@@ -0,0 +1,18 @@
+package projection
+
+import "gorm.io/gorm"
+
+// SearchVector is the SQL that computes search_vector from an
+// intervention's own columns. Titles rank highest, then problems and
+// referral reasons, then free text. Migration 010 backfills existing rows
+// with the same expression.
+const SearchVector = `setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(array_to_string(problems, ' '), '') || ' ' || coalesce(array_to_string(referral_reasons, ' '), '')), 'B') ||
+	setweight(to_tsvector('english', coalesce(description, '') || ' ' || coalesce(notes, '')), 'C')`
+
+// refreshSearchVector recomputes search_vector after a row has changed. It
+// runs in the transaction that changed the row, so the two never disagree.
+func (p *InterventionProjector) refreshSearchVector(tx *gorm.DB, interventionID, tenantID string) error {
+	return tx.Exec("UPDATE "+p.table+" SET search_vector = "+SearchVector+" WHERE id = ? AND tenant_id = ?",
+		interventionID, tenantID).Error
+}
@@ -0,0 +1,292 @@
+// Package reconcile compares the write model with the read model and works
+// out which events would bring the read model back in line.
+package reconcile
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/aggregate"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/events"
+	"github.com/lambda/internal/repository"
+)
+
+const (
+	KindMissing   = "missing"   // in interventions, not in the projection
+	KindExtra     = "extra"     // in the projection, not in interventions
+	KindDivergent = "divergent" // in both, with different field values
+)
+
+const (
+	RepairRepublish = "republish" // events exist that the projection has not applied
+	RepairRebuild   = "rebuild"   // no event would change the row; rebuild the projection
+	RepairNone      = "none"      // nothing on the write side to repair from
+)
+
+type FieldDiff struct {
+	Field string      `json:"field"`
+	Write interface{} `json:"write"`
+	Read  interface{} `json:"read"`
+}
+
+type Divergence struct {
+	InterventionID string      `json:"intervention_id"`
+	Kind           string      `json:"kind"`
+	WriteVersion   int64       `json:"write_version"`
+	ReadSequence   int64       `json:"read_sequence"`
+	Fields         []FieldDiff `json:"fields,omitempty"`
+	Repair         string      `json:"repair"`
+	Republished    int         `json:"republished,omitempty"`
+
+	events []*events.DomainEvent
+}
+
+type TenantReport struct {
+	TenantID    string        `json:"tenant_id"`
+	WriteCount  int           `json:"write_count"`
+	ReadCount   int           `json:"read_count"`
+	Missing     int           `json:"missing"`
+	Extra       int           `json:"extra"`
+	Divergent   int           `json:"divergent"`
+	Divergences []*Divergence `json:"divergences"`
+}
+
+// projectionRow reads interventions_projection with the column types the
+// comparison needs.
+type projectionRow struct {
+	ID              string
+	TenantID        string
+	PatientID       string
+	ScreeningID     string
+	Type            string
+	Title           string
+	Description     *string
+	Status          string
+	Priority        *string
+	CreatedBy       string
+	AssignedTo      *string
+	AssignedTeam    *string
+	DueAt           *time.Time
+	CompletedAt     *time.Time
+	ReferralReasons pq.StringArray `gorm:"type:text[]"`
+	Problems        pq.StringArray `gorm:"type:text[]"`
+	Notes           *string
+	LastSequence    int64
+}
+
+func (projectionRow) TableName() string {
+	return "interventions_projection"
+}
+
+type Checker struct {
+	writeDB    *gorm.DB
+	readDB     *gorm.DB
+	eventStore *repository.EventStoreRepository
+}
+
+func NewChecker(writeDB, readDB *gorm.DB) *Checker {
+	return &Checker{
+		writeDB:    writeDB,
+		readDB:     readDB,
+		eventStore: repository.NewEventStoreRepository(writeDB),
+	}
+}
+
+// Tenants returns every tenant that has interventions in either model.
+func (c *Checker) Tenants(ctx context.Context) ([]string, error) {
+	seen := map[string]bool{}
+	for _, query := range []*gorm.DB{
+		c.writeDB.WithContext(ctx).Model(&domain.Intervention{}),
+		c.readDB.WithContext(ctx).Model(&projectionRow{}),
+	} {
+		var tenants []string
+		if err := query.Distinct("tenant_id").Pluck("tenant_id", &tenants).Error; err != nil {
+			return nil, err
+		}
+		for _, tenant := range tenants {
+			seen[tenant] = true
+		}
+	}
+
+	tenants := make([]string, 0, len(seen))
+	for tenant := range seen {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// CheckTenant compares one tenant's interventions across both models and
+// plans a repair for each divergence.
+func (c *Checker) CheckTenant(ctx context.Context, tenantID string) (*TenantReport, error) {
+	var written []*domain.Intervention
+	if err := c.writeDB.WithContext(ctx).Where("tenant_id = ?", tenantID).Find(&written).Error; err != nil {
+		return nil, err
+	}
+	var projected []*projectionRow
+	if err := c.readDB.WithContext(ctx).Where("tenant_id = ?", tenantID).Find(&projected).Error; err != nil {
+		return nil, err
+	}
+
+	report := &TenantReport{
+		TenantID:    tenantID,
+		WriteCount:  len(written),
+		ReadCount:   len(projected),
+		Divergences: []*Divergence{},
+	}
+
+	reads := make(map[string]*projectionRow, len(projected))
+	for _, row := range projected {
+		reads[row.ID] = row
+	}
+
+	for _, intervention := range written {
+		read, ok := reads[intervention.ID]
+		delete(reads, intervention.ID)
+
+		divergence := &Divergence{InterventionID: intervention.ID, WriteVersion: intervention.Version}
+		if !ok {
+			divergence.Kind = KindMissing
+			report.Missing++
+		} else {
+			divergence.ReadSequence = read.LastSequence
+			divergence.Fields = compare(intervention, read)
+			if len(divergence.Fields) == 0 {
+				continue
+			}
+			divergence.Kind = KindDivergent
+			report.Divergent++
+		}
+
+		if err := c.planRepair(ctx, intervention, divergence); err != nil {
+			return nil, err
+		}
+		report.Divergences = append(report.Divergences, divergence)
+	}
+
+	for _, read := range reads {
+		report.Extra++
+		report.Divergences = append(report.Divergences, &Divergence{
+			InterventionID: read.ID,
+			Kind:           KindExtra,
+			ReadSequence:   read.LastSequence,
+			Repair:         RepairNone,
+		})
+	}
+
+	sort.Slice(report.Divergences, func(i, j int) bool {
+		return report.Divergences[i].InterventionID < report.Divergences[j].InterventionID
+	})
+	return report, nil
+}
+
+// planRepair picks the events that would bring the projection up to date:
+// the stored events after the projection's sequence when the event store
+// has them, and otherwise, for a missing row, a snapshot of the current
+// state. A row that is divergent at the write version cannot be fixed by
+// events and needs a rebuild.
+func (c *Checker) planRepair(ctx context.Context, intervention *domain.Intervention, divergence *Divergence) error {
+	history, err := c.eventStore.Load(ctx, intervention.TenantID, intervention.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range history {
+		if divergence.Kind == KindMissing || event.Sequence > divergence.ReadSequence {
+			divergence.events = append(divergence.events, event)
+		}
+	}
+	if divergence.Kind == KindMissing && (len(history) == 0 || history[0].EventType != events.InterventionCreated) {
+		divergence.events, err = aggregate.SnapshotEvents(intervention)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(divergence.events) > 0 {
+		divergence.Repair = RepairRepublish
+	} else {
+		divergence.Repair = RepairRebuild
+	}
+	return nil
+}
+
+// Republish sends the planned repair events of every divergence in the
+// report, in sequence order per intervention.
+func (c *Checker) Republish(ctx context.Context, report *TenantReport, publisher events.EventPublisher) error {
+	for _, divergence := range report.Divergences {
+		for _, event := range divergence.events {
+			if err := publisher.Publish(ctx, event); err != nil {
+				return err
+			}
+			divergence.Republished++
+		}
+	}
+	return nil
+}
+
+// compare returns the fields whose values differ between the write row and
+// its projection.
+func compare(write *domain.Intervention, read *projectionRow) []FieldDiff {
+	priority := ""
+	if read.Priority != nil {
+		priority = *read.Priority
+	}
+
+	pairs := []struct {
+		field       string
+		write, read interface{}
+	}{
+		{"patient_id", write.PatientID, read.PatientID},
+		{"screening_id", write.ScreeningID, read.ScreeningID},
+		{"type", string(write.Type), read.Type},
+		{"title", write.Title, read.Title},
+		{"description", deref(write.Description), deref(read.Description)},
+		{"status", string(write.Status), read.Status},
+		{"priority", write.Priority, priority},
+		{"created_by", write.CreatedBy, read.CreatedBy},
+		{"assigned_to", deref(write.AssignedTo), deref(read.AssignedTo)},
+		{"assigned_team", deref(write.AssignedTeam), deref(read.AssignedTeam)},
+		{"due_at", timestamp(write.DueAt), timestamp(read.DueAt)},
+		{"completed_at", timestamp(write.CompletedAt), timestamp(read.CompletedAt)},
+		{"referral_reasons", orEmpty(write.ReferralReasons), orEmpty(read.ReferralReasons)},
+		{"problems", orEmpty(write.Problems), orEmpty(read.Problems)},
+		{"notes", deref(write.Notes), deref(read.Notes)},
+	}
+
+	var diffs []FieldDiff
+	for _, pair := range pairs {
+		if !reflect.DeepEqual(pair.write, pair.read) {
+			diffs = append(diffs, FieldDiff{Field: pair.field, Write: pair.write, Read: pair.read})
+		}
+	}
+	return diffs
+}
+
+func deref(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// timestamp normalises to the microsecond precision Postgres stores.
+func timestamp(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+}
+
+func orEmpty(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/domain"
+)
+
+// BarrierFilter narrows barrier counts. Year and Month select a calendar
+// period in UTC by creation date; Month needs Year. Zero fields do not
+// filter.
+type BarrierFilter struct {
+	Year         int
+	Month        int
+	Types        []domain.InterventionType
+	CreatedByIDs []string
+}
+
+// SetPeriod reads the period from request parameters: year as a number,
+// and month as a number from 1 to 12 or as YYYY-MM, which also sets the
+// year. Empty parameters leave the period open.
+func (f *BarrierFilter) SetPeriod(year, month string) error {
+	if year != "" {
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			return fmt.Errorf("%w: year must be a number", ErrInvalidFilter)
+		}
+		f.Year = y
+	}
+	if month == "" {
+		return nil
+	}
+	if t, err := time.Parse("2006-01", month); err == nil {
+		f.Year, f.Month = t.Year(), int(t.Month())
+		return nil
+	}
+	m, err := strconv.Atoi(month)
+	if err != nil {
+		return fmt.Errorf("%w: month must be a number or YYYY-MM", ErrInvalidFilter)
+	}
+	f.Month = m
+	return nil
+}
+
+func (f BarrierFilter) Validate() error {
+	if f.Month != 0 && f.Year == 0 {
+		return fmt.Errorf("%w: month needs a year", ErrInvalidFilter)
+	}
+	if f.Month < 0 || f.Month > 12 {
+		return fmt.Errorf("%w: month %d is not between 1 and 12", ErrInvalidFilter, f.Month)
+	}
+	return nil
+}
+
+func (f BarrierFilter) interventionFilter() InterventionFilter {
+	filter := InterventionFilter{Types: f.Types, CreatedByIDs: f.CreatedByIDs}
+	if f.Year == 0 {
+		return filter
+	}
+
+	start := time.Date(f.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	if f.Month != 0 {
+		start = time.Date(f.Year, time.Month(f.Month), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0)
+	}
+	filter.CreatedAfter = &start
+	filter.CreatedBefore = &end
+	return filter
+}
+
+// GetBarrierCounts counts a tenant's interventions by the problems they
+// list, per month of creation, and by referral reason. An intervention
+// listing several problems counts once towards each. The counts come from
+// the pre-aggregated tables, except when filtering by creator, which they
+// do not record.
+func (r *InterventionProjectionRepository) GetBarrierCounts(ctx context.Context, tenantID string, filter BarrierFilter) (*domain.BarrierResponse, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	response := &domain.BarrierResponse{
+		ChartData:   []*domain.BarrierCount{},
+		SubtypeData: []*domain.BarrierSubtype{},
+	}
+	// Scan reads through GORM's Row callback, which tenant isolation only
+	// scopes inside a transaction.
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(filter.CreatedByIDs) > 0 {
+			return countBarriersLive(tx, tenantID, filter, response)
+		}
+		return countBarriers(tx, tenantID, filter, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// countBarriers sums the monthly count tables.
+func countBarriers(tx *gorm.DB, tenantID string, filter BarrierFilter, response *domain.BarrierResponse) error {
+	chart := tx.
+		Table("intervention_barrier_counts").
+		Select("to_char(month, 'YYYY-MM') AS month, problem AS problem_name, sum(intervention_count) AS barrier_count")
+	err := filter.applyToCounts(chart, tenantID).
+		Group("month, problem").
+		Order("month, barrier_count DESC, problem_name").
+		Scan(&response.ChartData).Error
+	if err != nil {
+		return err
+	}
+
+	subtypes := tx.
+		Table("intervention_referral_counts").
+		Select("referral_reason AS sub_type, sum(intervention_count) AS barrier_count")
+	return filter.applyToCounts(subtypes, tenantID).
+		Group("referral_reason").
+		Order("barrier_count DESC, sub_type").
+		Scan(&response.SubtypeData).Error
+}
+
+// applyToCounts narrows a query on the monthly count tables.
+func (f BarrierFilter) applyToCounts(query *gorm.DB, tenantID string) *gorm.DB {
+	query = query.Where("tenant_id = ?", tenantID)
+	period := f.interventionFilter()
+	if period.CreatedAfter != nil {
+		query = query.Where("month >= ? AND month < ?", period.CreatedAfter.Format("2006-01-02"), period.CreatedBefore.Format("2006-01-02"))
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, len(f.Types))
+		for i, interventionType := range f.Types {
+			types[i] = string(interventionType)
+		}
+		query = query.Where("type IN ?", types)
+	}
+	return query
+}
+
+// countBarriersLive aggregates interventions_projection directly.
+func countBarriersLive(tx *gorm.DB, tenantID string, filter BarrierFilter, response *domain.BarrierResponse) error {
+	interventionFilter := filter.interventionFilter()
+	table := InterventionProjection{}.TableName()
+
+	chart := tx.
+		Table(table+", unnest(problems) AS problem").
+		Select("to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM') AS month, problem AS problem_name, count(DISTINCT id) AS barrier_count").
+		Where("tenant_id = ?", tenantID)
+	err := interventionFilter.apply(chart).
+		Group("month, problem").
+		Order("month, barrier_count DESC, problem_name").
+		Scan(&response.ChartData).Error
+	if err != nil {
+		return err
+	}
+
+	subtypes := tx.
+		Table(table+", unnest(referral_reasons) AS reason").
+		Select("reason AS sub_type, count(DISTINCT id) AS barrier_count").
+		Where("tenant_id = ?", tenantID)
+	return interventionFilter.apply(subtypes).
+		Group("reason").
+		Order("barrier_count DESC, sub_type").
+		Scan(&response.SubtypeData).Error
+}
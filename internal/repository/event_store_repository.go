@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lambda/internal/events"
+	"gorm.io/gorm"
+)
+
+// StoredEvent is one row of the append-only intervention_events table.
+// Position orders events across all interventions; Sequence orders the
+// events of one intervention.
+type StoredEvent struct {
+	Position       int64     `gorm:"->" json:"position"`
+	EventID        string    `gorm:"primaryKey;type:text" json:"event_id"`
+	InterventionID string    `gorm:"type:text;not null" json:"intervention_id"`
+	TenantID       string    `gorm:"type:text;not null" json:"tenant_id"`
+	Sequence       int64     `gorm:"not null" json:"sequence"`
+	EventType      string    `gorm:"type:text;not null" json:"event_type"`
+	SchemaVersion  int       `gorm:"not null" json:"schema_version"`
+	Payload        string    `gorm:"type:jsonb;not null" json:"payload"`
+	Metadata       string    `gorm:"type:jsonb;not null" json:"metadata"`
+	OccurredAt     time.Time `gorm:"type:timestamptz;not null" json:"occurred_at"`
+	RecordedAt     time.Time `gorm:"type:timestamptz;autoCreateTime" json:"recorded_at"`
+}
+
+func (StoredEvent) TableName() string {
+	return "intervention_events"
+}
+
+// Event rebuilds the domain event as it was produced.
+func (e *StoredEvent) Event() (*events.DomainEvent, error) {
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(e.Metadata), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata of event %s: %w", e.EventID, err)
+	}
+	return &events.DomainEvent{
+		EventID:       e.EventID,
+		EventType:     events.EventType(e.EventType),
+		SchemaVersion: e.SchemaVersion,
+		AggregateID:   e.InterventionID,
+		Sequence:      e.Sequence,
+		TenantID:      e.TenantID,
+		Timestamp:     e.OccurredAt,
+		Payload:       json.RawMessage(e.Payload),
+		Metadata:      metadata,
+	}, nil
+}
+
+type EventStoreRepository struct {
+	db *gorm.DB
+}
+
+func NewEventStoreRepository(db *gorm.DB) *EventStoreRepository {
+	return &EventStoreRepository{db: db}
+}
+
+// WithTx returns a repository bound to the given transaction.
+func (r *EventStoreRepository) WithTx(tx *gorm.DB) *EventStoreRepository {
+	return &EventStoreRepository{db: tx}
+}
+
+// Append records the event. Call it on a repository bound to the
+// transaction that writes the aggregate, so the store never holds an event
+// for a change that was rolled back.
+func (r *EventStoreRepository) Append(ctx context.Context, event *events.DomainEvent) error {
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+	if event.Metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	stored := &StoredEvent{
+		EventID:        event.EventID,
+		InterventionID: event.AggregateID,
+		TenantID:       event.TenantID,
+		Sequence:       event.Sequence,
+		EventType:      string(event.EventType),
+		SchemaVersion:  event.SchemaVersion,
+		Payload:        string(event.Payload),
+		Metadata:       string(metadata),
+		OccurredAt:     event.Timestamp,
+	}
+	return r.db.WithContext(ctx).Create(stored).Error
+}
+
+// Load returns the events of one intervention in sequence order.
+func (r *EventStoreRepository) Load(ctx context.Context, tenantID, interventionID string) ([]*events.DomainEvent, error) {
+	return r.load(ctx, r.db.Where("tenant_id = ? AND intervention_id = ?", tenantID, interventionID))
+}
+
+// LoadUntil returns the events of one intervention that occurred at or
+// before asOf, in sequence order.
+func (r *EventStoreRepository) LoadUntil(ctx context.Context, tenantID, interventionID string, asOf time.Time) ([]*events.DomainEvent, error) {
+	return r.load(ctx, r.db.Where("tenant_id = ? AND intervention_id = ? AND occurred_at <= ?", tenantID, interventionID, asOf))
+}
+
+func (r *EventStoreRepository) load(ctx context.Context, query *gorm.DB) ([]*events.DomainEvent, error) {
+	var stored []*StoredEvent
+	if err := query.WithContext(ctx).Order("sequence ASC").Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	history := make([]*events.DomainEvent, 0, len(stored))
+	for _, row := range stored {
+		event, err := row.Event()
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, event)
+	}
+	return history, nil
+}
+
+// ListAfter returns up to limit stored events with a position after
+// afterPosition, in position order, optionally for one tenant. Replays page
+// through the whole store with it.
+func (r *EventStoreRepository) ListAfter(ctx context.Context, tenantID string, afterPosition int64, limit int) ([]*StoredEvent, error) {
+	query := r.db.WithContext(ctx).Where("position > ?", afterPosition)
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	var stored []*StoredEvent
+	err := query.Order("position ASC").Limit(limit).Find(&stored).Error
+	return stored, err
+}
+
+// CountAfter returns how many events are stored after afterPosition,
+// optionally for one tenant.
+func (r *EventStoreRepository) CountAfter(ctx context.Context, tenantID string, afterPosition int64) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&StoredEvent{}).Where("position > ?", afterPosition)
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
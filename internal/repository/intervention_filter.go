@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/domain"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// InterventionFilter narrows intervention lists and counts. It applies to
+// interventions and interventions_projection alike, which share column
+// names. Empty fields do not filter; set fields are combined with AND, and
+// the values of a list field with OR.
+type InterventionFilter struct {
+	Statuses     []domain.InterventionStatus
+	Types        []domain.InterventionType
+	PatientID    string
+	ScreeningIDs []string
+	CreatedByIDs []string
+	AssignedTeam string
+	AssignedTo   string
+	// Unassigned keeps interventions with neither an assignee nor a team.
+	Unassigned bool
+	// Overdue keeps open interventions whose due date has passed. Completed
+	// and cancelled interventions are never overdue.
+	Overdue bool
+
+	DueAfter      *time.Time
+	DueBefore     *time.Time
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+
+	// ProblemsAny keeps interventions listing at least one of the problems;
+	// ProblemsAll those listing every one of them.
+	ProblemsAny []string
+	ProblemsAll []string
+
+	// VisibleTo keeps interventions assigned to or created by one of the
+	// users, and VisibleAssignedTo those assigned to one of the users. They
+	// are set by authorization, not by callers' filters.
+	VisibleTo         []string
+	VisibleAssignedTo []string
+}
+
+func (f InterventionFilter) Validate() error {
+	if f.DueAfter != nil && f.DueBefore != nil && f.DueAfter.After(*f.DueBefore) {
+		return fmt.Errorf("%w: due date range starts after it ends", ErrInvalidFilter)
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && f.CreatedAfter.After(*f.CreatedBefore) {
+		return fmt.Errorf("%w: created date range starts after it ends", ErrInvalidFilter)
+	}
+	if f.Unassigned && (f.AssignedTo != "" || f.AssignedTeam != "") {
+		return fmt.Errorf("%w: unassigned cannot be combined with an assignee or team", ErrInvalidFilter)
+	}
+	return nil
+}
+
+func (f InterventionFilter) apply(query *gorm.DB) *gorm.DB {
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, status := range f.Statuses {
+			statuses[i] = string(status)
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, len(f.Types))
+		for i, interventionType := range f.Types {
+			types[i] = string(interventionType)
+		}
+		query = query.Where("type IN ?", types)
+	}
+	if f.PatientID != "" {
+		query = query.Where("patient_id = ?", f.PatientID)
+	}
+	if len(f.ScreeningIDs) > 0 {
+		query = query.Where("screening_id IN ?", f.ScreeningIDs)
+	}
+	if len(f.CreatedByIDs) > 0 {
+		query = query.Where("created_by IN ?", f.CreatedByIDs)
+	}
+	if f.AssignedTeam != "" {
+		query = query.Where("assigned_team = ?", f.AssignedTeam)
+	}
+	if f.AssignedTo != "" {
+		query = query.Where("assigned_to = ?", f.AssignedTo)
+	}
+	if f.Unassigned {
+		query = query.Where("(assigned_to IS NULL OR assigned_to = '') AND (assigned_team IS NULL OR assigned_team = '')")
+	}
+	if f.Overdue {
+		query = query.Where("due_at < now() AND status NOT IN ?", []string{string(domain.StatusCompleted), string(domain.StatusCancelled)})
+	}
+	if f.DueAfter != nil {
+		query = query.Where("due_at >= ?", *f.DueAfter)
+	}
+	if f.DueBefore != nil {
+		query = query.Where("due_at < ?", *f.DueBefore)
+	}
+	if f.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *f.CreatedBefore)
+	}
+	if len(f.ProblemsAny) > 0 {
+		query = query.Where("problems && ?", pq.StringArray(f.ProblemsAny))
+	}
+	if len(f.ProblemsAll) > 0 {
+		query = query.Where("problems @> ?", pq.StringArray(f.ProblemsAll))
+	}
+	if f.VisibleTo != nil {
+		query = query.Where("(assigned_to IN ? OR created_by IN ?)", f.VisibleTo, f.VisibleTo)
+	}
+	if f.VisibleAssignedTo != nil {
+		query = query.Where("assigned_to IN ?", f.VisibleAssignedTo)
+	}
+	return query
+}
@@ -26,6 +26,8 @@ type InterventionProjection struct {
 	Notes           *string  `gorm:"type:text" json:"notes,omitempty"`
 	CreatedAt       string   `gorm:"type:timestamptz" json:"created_at"`
 	UpdatedAt       string   `gorm:"type:timestamptz" json:"updated_at"`
+	LastEventID     *string  `gorm:"type:text" json:"last_event_id,omitempty"`
+	LastSequence    int64    `gorm:"not null;default:0" json:"last_sequence"`
 }
 
 func (InterventionProjection) TableName() string {
@@ -51,58 +53,54 @@ func (r *InterventionProjectionRepository) GetByID(ctx context.Context, id, tena
 	return &intervention, nil
 }
 
-func (r *InterventionProjectionRepository) List(ctx context.Context, tenantID string, filters map[string]interface{}) ([]*InterventionProjection, error) {
-	var interventions []*InterventionProjection
-
-	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
-
-	if status, ok := filters["status"].(string); ok && status != "" {
-		query = query.Where("status = ?", status)
-	}
-	if interventionType, ok := filters["type"].(string); ok && interventionType != "" {
-		query = query.Where("type = ?", interventionType)
-	}
-	if patientID, ok := filters["patient_id"].(string); ok && patientID != "" {
-		query = query.Where("patient_id = ?", patientID)
-	}
-	if screeningID, ok := filters["screening_id"].(string); ok && screeningID != "" {
-		query = query.Where("screening_id = ?", screeningID)
+// List returns one page of a tenant's interventions matching filter.
+func (r *InterventionProjectionRepository) List(ctx context.Context, tenantID string, filter InterventionFilter, page PageRequest) ([]*InterventionProjection, *PageInfo, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, err
 	}
-	if screeningIDs, ok := filters["screening_ids"].([]string); ok && len(screeningIDs) > 0 {
-		query = query.Where("screening_id IN ?", screeningIDs)
-	}
-	if createdBy, ok := filters["created_by"].(string); ok && createdBy != "" {
-		query = query.Where("created_by = ?", createdBy)
+	page, err := page.normalize()
+	if err != nil {
+		return nil, nil, err
 	}
-	if createdByIDs, ok := filters["created_by_ids"].([]string); ok && len(createdByIDs) > 0 {
-		query = query.Where("created_by IN ?", createdByIDs)
+
+	query := filter.apply(r.db.WithContext(ctx).Where("tenant_id = ?", tenantID))
+	query, err = paginate(query, InterventionProjection{}.TableName(), page)
+	if err != nil {
+		return nil, nil, err
 	}
-	if assignedTeam, ok := filters["assigned_team"].(string); ok && assignedTeam != "" {
-		query = query.Where("assigned_team = ?", assignedTeam)
+
+	var rows []*pagedProjection
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, nil, err
 	}
 
-	err := query.Order("created_at DESC").Find(&interventions).Error
-	if err != nil {
-		return nil, err
+	fetched := len(rows)
+	if fetched > page.First {
+		rows = rows[:page.First]
 	}
+	interventions := make([]*InterventionProjection, len(rows))
+	lastID, lastSortKey := "", ""
+	for i, row := range rows {
+		interventions[i] = &row.InterventionProjection
+		lastID, lastSortKey = row.ID, row.SortKey
+	}
+	return interventions, pageInfo(page, fetched, lastID, lastSortKey), nil
+}
 
-	return interventions, nil
+// pagedProjection is a projection row read by List, with the sort value
+// paginate selects for its cursor.
+type pagedProjection struct {
+	InterventionProjection
+	SortKey string `gorm:"column:page_sort_key;->"`
 }
 
-func (r *InterventionProjectionRepository) Count(ctx context.Context, tenantID string, filters map[string]interface{}) (int64, error) {
+// Count returns how many of a tenant's interventions match filter, across
+// all pages.
+func (r *InterventionProjectionRepository) Count(ctx context.Context, tenantID string, filter InterventionFilter) (int64, error) {
 	var count int64
 
 	query := r.db.WithContext(ctx).Model(&InterventionProjection{}).Where("tenant_id = ?", tenantID)
-
-	if status, ok := filters["status"].(string); ok && status != "" {
-		query = query.Where("status = ?", status)
-	}
-	if interventionType, ok := filters["type"].(string); ok && interventionType != "" {
-		query = query.Where("type = ?", interventionType)
-	}
-	if patientID, ok := filters["patient_id"].(string); ok && patientID != "" {
-		query = query.Where("patient_id = ?", patientID)
-	}
+	query = filter.apply(query)
 
 	err := query.Count(&count).Error
 	return count, err
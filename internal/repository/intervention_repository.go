@@ -9,6 +9,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrConcurrentUpdate = errors.New("intervention was modified concurrently")
+
 type InterventionRepository struct {
 	db *gorm.DB
 }
@@ -19,6 +21,15 @@ func NewInterventionRepository(db *gorm.DB) *InterventionRepository {
 	}
 }
 
+// WithTx returns a repository bound to the given transaction.
+func (r *InterventionRepository) WithTx(tx *gorm.DB) *InterventionRepository {
+	return &InterventionRepository{db: tx}
+}
+
+func (r *InterventionRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
 func (r *InterventionRepository) Create(ctx context.Context, intervention *domain.Intervention) error {
 	return r.db.WithContext(ctx).Create(intervention).Error
 }
@@ -58,8 +69,29 @@ func (r *InterventionRepository) GetByScreeningID(ctx context.Context, screening
 	return interventions, err
 }
 
+// Update saves the intervention and bumps its version. The write is
+// conditional on the version that was read, so concurrent changes to the
+// same intervention fail with ErrConcurrentUpdate instead of producing two
+// events with the same sequence number.
 func (r *InterventionRepository) Update(ctx context.Context, intervention *domain.Intervention) error {
-	return r.db.WithContext(ctx).Save(intervention).Error
+	expected := intervention.Version
+	intervention.Version++
+
+	result := r.db.WithContext(ctx).
+		Model(intervention).
+		Where("version = ?", expected).
+		Select("*").
+		Omit("User", "CreatedAt").
+		Updates(intervention)
+	if result.Error != nil {
+		intervention.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		intervention.Version = expected
+		return ErrConcurrentUpdate
+	}
+	return nil
 }
 
 func (r *InterventionRepository) Delete(ctx context.Context, id string, tenantID string) error {
@@ -75,43 +107,52 @@ func (r *InterventionRepository) Delete(ctx context.Context, id string, tenantID
 	return nil
 }
 
-func (r *InterventionRepository) List(ctx context.Context, tenantID string, filters map[string]interface{}) ([]*domain.Intervention, error) {
-	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
-
-	if status, ok := filters["status"]; ok {
-		query = query.Where("status = ?", status)
-	}
-	if interventionType, ok := filters["type"]; ok {
-		query = query.Where("type = ?", interventionType)
+// List returns one page of a tenant's interventions matching filter.
+func (r *InterventionRepository) List(ctx context.Context, tenantID string, filter InterventionFilter, page PageRequest) ([]*domain.Intervention, *PageInfo, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, err
 	}
-	if assignedTeam, ok := filters["assigned_team"]; ok {
-		query = query.Where("assigned_team = ?", assignedTeam)
+	page, err := page.normalize()
+	if err != nil {
+		return nil, nil, err
 	}
-	if patientID, ok := filters["patient_id"]; ok {
-		query = query.Where("patient_id = ?", patientID)
+
+	query := filter.apply(r.db.WithContext(ctx).Where("tenant_id = ?", tenantID))
+	query, err = paginate(query, (&domain.Intervention{}).TableName(), page)
+	if err != nil {
+		return nil, nil, err
 	}
-	if screeningID, ok := filters["screening_id"]; ok {
-		query = query.Where("screening_id = ?", screeningID)
+
+	var rows []*pagedIntervention
+	if err := query.Preload("User").Find(&rows).Error; err != nil {
+		return nil, nil, err
 	}
-	if screeningIDs, ok := filters["screening_ids"].([]string); ok && len(screeningIDs) > 0 {
-		query = query.Where("screening_id IN (?)", screeningIDs)
+
+	fetched := len(rows)
+	if fetched > page.First {
+		rows = rows[:page.First]
 	}
-	if createdByIDs, ok := filters["created_by_ids"].([]string); ok && len(createdByIDs) > 0 {
-		query = query.Where("created_by IN (?)", createdByIDs)
-	} else if createdBy, ok := filters["created_by"]; ok {
-		query = query.Where("created_by = ?", createdBy)
+	interventions := make([]*domain.Intervention, len(rows))
+	lastID, lastSortKey := "", ""
+	for i, row := range rows {
+		interventions[i] = &row.Intervention
+		lastID, lastSortKey = row.ID, row.SortKey
 	}
+	return interventions, pageInfo(page, fetched, lastID, lastSortKey), nil
+}
 
-	query = query.Preload("User")
-	var interventions []*domain.Intervention
-	err := query.Order("created_at DESC").Find(&interventions).Error
-	return interventions, err
+// pagedIntervention is an intervention read by List, with the sort value
+// paginate selects for its cursor.
+type pagedIntervention struct {
+	domain.Intervention
+	SortKey string `gorm:"column:page_sort_key;->"`
 }
 
-func (r *InterventionRepository) GetBarrierCounts(ctx context.Context, tenantID string, filters map[string]interface{}) (*domain.BarrierResponse, error) {
-	// Simplified mock implementation - in production this would match the blueprint implementation
-	return &domain.BarrierResponse{
-		ChartData:   []*domain.BarrierCount{},
-		SubtypeData: []*domain.BarrierSubtype{},
-	}, nil
+// Count returns how many of a tenant's interventions match filter, across
+// all pages.
+func (r *InterventionRepository) Count(ctx context.Context, tenantID string, filter InterventionFilter) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&domain.Intervention{}).Where("tenant_id = ?", tenantID)
+	err := filter.apply(query).Count(&count).Error
+	return count, err
 }
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sortByRank marks search cursors, which follow relevance rather than a
+// column.
+const sortByRank SortField = "rank"
+
+const (
+	// searchRank is widened to float8 so the rank read into a cursor,
+	// printed at full precision, compares equal to the row's own rank.
+	searchRank     = "ts_rank_cd(p.search_vector, q)::float8"
+	highlightStyle = "StartSel=<mark>, StopSel=</mark>"
+)
+
+// InterventionSearchResult is one search hit. TitleHighlight and Snippet
+// wrap matching words in <mark> tags; Snippet is taken from the
+// description, notes, problems and referral reasons.
+type InterventionSearchResult struct {
+	InterventionProjection `gorm:"embedded"`
+	Rank                   float64 `json:"rank"`
+	TitleHighlight         string  `json:"title_highlight"`
+	Snippet                string  `json:"snippet"`
+}
+
+// Search returns one page of a tenant's interventions matching the search
+// text, most relevant first. The text uses web search syntax: quoted
+// phrases, OR, and a leading - to exclude a word. Only First and After of
+// page are used.
+func (r *InterventionProjectionRepository) Search(ctx context.Context, tenantID, text string, filter InterventionFilter, page PageRequest) ([]*InterventionSearchResult, *PageInfo, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil, fmt.Errorf("%w: search text is empty", ErrInvalidFilter)
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, nil, err
+	}
+	first := pageSize(page.First)
+
+	query := r.db.WithContext(ctx).
+		Table(InterventionProjection{}.TableName()+" AS p, websearch_to_tsquery('english', ?) AS q", text).
+		Select("p.*, "+searchRank+" AS rank, "+
+			"ts_headline('english', p.title, q, 'HighlightAll=true, "+highlightStyle+"') AS title_highlight, "+
+			"ts_headline('english', concat_ws(' | ', p.description, p.notes, array_to_string(p.problems, ', '), array_to_string(p.referral_reasons, ', ')), q, "+
+			"'MaxFragments=2, MaxWords=20, MinWords=5, "+highlightStyle+"') AS snippet").
+		Where("p.tenant_id = ? AND p.search_vector @@ q", tenantID)
+	query = filter.apply(query)
+
+	if page.After != "" {
+		after, err := decodeCursor(page.After)
+		if err != nil {
+			return nil, nil, err
+		}
+		if after.SortBy != sortByRank {
+			return nil, nil, fmt.Errorf("%w: not a search cursor", ErrInvalidCursor)
+		}
+		if err := checkCursorValue(after.Value, "float8"); err != nil {
+			return nil, nil, err
+		}
+		query = query.Where("("+searchRank+", p.id) < (?::text::float8, ?)", after.Value, after.ID)
+	}
+
+	var results []*InterventionSearchResult
+	err := query.Order("rank DESC").Order("p.id DESC").Limit(first + 1).Find(&results).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &PageInfo{HasNextPage: len(results) > first}
+	if info.HasNextPage {
+		results = results[:first]
+	}
+	if len(results) > 0 {
+		info.EndCursor = searchCursor(results[len(results)-1])
+	}
+	return results, info, nil
+}
+
+// searchCursor is the position after a hit. The rank is written at full
+// precision so the next page starts exactly after it.
+func searchCursor(last *InterventionSearchResult) string {
+	return encodeCursor(cursor{
+		SortBy: sortByRank,
+		Value:  strconv.FormatFloat(last.Rank, 'g', -1, 64),
+		ID:     last.ID,
+	})
+}
+
+// CountSearch returns how many of a tenant's interventions match the search
+// text, across all pages.
+func (r *InterventionProjectionRepository) CountSearch(ctx context.Context, tenantID, text string, filter InterventionFilter) (int64, error) {
+	if strings.TrimSpace(text) == "" {
+		return 0, fmt.Errorf("%w: search text is empty", ErrInvalidFilter)
+	}
+
+	var count int64
+	query := r.db.WithContext(ctx).
+		Table(InterventionProjection{}.TableName()+" AS p, websearch_to_tsquery('english', ?) AS q", text).
+		Where("p.tenant_id = ? AND p.search_vector @@ q", tenantID)
+	err := filter.apply(query).Count(&count).Error
+	return count, err
+}
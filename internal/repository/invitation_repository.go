@@ -1,10 +1,21 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/lambda/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrInvitationChanged means a conditional update found the invitation no
+// longer in a state the change applies to, because it was accepted,
+// revoked or expired in the meantime.
+var ErrInvitationChanged = errors.New("invitation was modified concurrently")
+
 type InvitationRepository struct {
 	db *gorm.DB
 }
@@ -13,18 +24,127 @@ func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
 	return &InvitationRepository{db: db}
 }
 
-func (r *InvitationRepository) CreateInvitation(invitation *domain.Invitation) error {
-	return r.db.Create(invitation).Error
+func (r *InvitationRepository) CreateInvitation(ctx context.Context, invitation *domain.Invitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
 }
 
-func (r *InvitationRepository) FindInvitationByToken(token string) (*domain.Invitation, error) {
+// FindInvitationByToken returns the invitation whatever its status; callers
+// check Status.
+func (r *InvitationRepository) FindInvitationByToken(ctx context.Context, token string) (*domain.Invitation, error) {
 	var invitation domain.Invitation
-	if err := r.db.Where("token = ? AND is_used = ?", token, false).First(&invitation).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&invitation).Error; err != nil {
 		return nil, err
 	}
 	return &invitation, nil
 }
 
-func (r *InvitationRepository) UpdateInvitationStatus(token string, isUsed bool) error {
-	return r.db.Model(&domain.Invitation{}).Where("token = ?", token).Update("is_used", isUsed).Error
+func (r *InvitationRepository) FindInvitation(ctx context.Context, tenantID string, id uuid.UUID) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListPendingInvitations returns the tenant's invitations that can still be
+// accepted, newest first, optionally only those sent by invitedBy.
+func (r *InvitationRepository) ListPendingInvitations(ctx context.Context, tenantID string, invitedBy *uuid.UUID) ([]*domain.Invitation, error) {
+	query := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND status = ? AND expires_at > ?", tenantID, domain.InvitationPending, time.Now())
+	if invitedBy != nil {
+		query = query.Where("invited_by = ?", *invitedBy)
+	}
+
+	var invitations []*domain.Invitation
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// MarkAccepted records that user registered with the invitation and adds
+// them to users, in one transaction. It only applies to a pending
+// invitation that has not expired by at, so an invitation is accepted at
+// most once. A users row already stored under user's ID is overwritten.
+func (r *InvitationRepository) MarkAccepted(ctx context.Context, id uuid.UUID, user *domain.User, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&domain.Invitation{}).
+			Where("id = ? AND status = ? AND expires_at > ?", id, domain.InvitationPending, at).
+			Updates(map[string]interface{}{
+				"status":      domain.InvitationAccepted,
+				"is_used":     true,
+				"accepted_by": user.ID,
+				"accepted_at": at,
+				"updated_at":  at,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvitationChanged
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tenant_id", "email", "username", "role", "navigator_admin_id", "is_deleted", "updated_at"}),
+		}).Create(user).Error
+	})
+}
+
+// MarkRevoked revokes a pending or expired invitation.
+func (r *InvitationRepository) MarkRevoked(ctx context.Context, tenantID string, id uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Invitation{}).
+		Where("id = ? AND tenant_id = ? AND status IN ?", id, tenantID, []domain.InvitationStatus{domain.InvitationPending, domain.InvitationExpired}).
+		Updates(map[string]interface{}{
+			"status":     domain.InvitationRevoked,
+			"revoked_at": at,
+			"updated_at": at,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationChanged
+	}
+	return nil
+}
+
+// RotateToken replaces the token of a pending or expired invitation and
+// makes it pending until expiresAt. The old token stops matching any
+// invitation.
+func (r *InvitationRepository) RotateToken(ctx context.Context, tenantID string, id uuid.UUID, token string, expiresAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Invitation{}).
+		Where("id = ? AND tenant_id = ? AND status IN ?", id, tenantID, []domain.InvitationStatus{domain.InvitationPending, domain.InvitationExpired}).
+		Updates(map[string]interface{}{
+			"token":      token,
+			"status":     domain.InvitationPending,
+			"expires_at": expiresAt,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationChanged
+	}
+	return nil
+}
+
+// ExpirePending marks pending invitations past their expiry as expired, in
+// every tenant, and returns how many it marked.
+func (r *InvitationRepository) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Invitation{}).
+		Where("status = ? AND expires_at <= ?", domain.InvitationPending, now).
+		Updates(map[string]interface{}{
+			"status":     domain.InvitationExpired,
+			"updated_at": now,
+		})
+	return result.RowsAffected, result.Error
 }
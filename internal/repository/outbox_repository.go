@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lambda/internal/events"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusPublished = "published"
+	OutboxStatusFailed    = "failed"
+	// OutboxStatusDead is a message that used up its attempts. It is not
+	// retried, and it keeps blocking the later messages of its aggregate
+	// until an operator requeues or discards it.
+	OutboxStatusDead = "dead"
+	// OutboxStatusDiscarded is a dead message an operator chose not to
+	// publish, which unblocks its aggregate.
+	OutboxStatusDiscarded = "discarded"
+)
+
+// outboxUnpublished are the statuses of messages that still hold back the
+// later messages of their aggregate.
+var outboxUnpublished = []string{OutboxStatusPending, OutboxStatusFailed, OutboxStatusDead}
+
+type OutboxMessage struct {
+	ID          string     `gorm:"primaryKey;type:text" json:"id"`
+	AggregateID string     `gorm:"type:text;not null;index" json:"aggregate_id"`
+	TenantID    string     `gorm:"type:text;not null" json:"tenant_id"`
+	EventType   string     `gorm:"type:text;not null" json:"event_type"`
+	Payload     string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status      string     `gorm:"type:text;not null;default:'pending'" json:"status"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	LastError   *string    `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;autoCreateTime" json:"created_at"`
+	PublishedAt *time.Time `gorm:"type:timestamptz" json:"published_at,omitempty"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+// Event decodes the stored domain event.
+func (m *OutboxMessage) Event() (*events.DomainEvent, error) {
+	var event events.DomainEvent
+	if err := json.Unmarshal([]byte(m.Payload), &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal outbox message %s: %w", m.ID, err)
+	}
+	return &event, nil
+}
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// WithTx returns a repository bound to the given transaction.
+func (r *OutboxRepository) WithTx(tx *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: tx}
+}
+
+func (r *OutboxRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+// Enqueue stores the event so the relay can publish it. Call it on a
+// repository bound to the transaction that writes the aggregate.
+func (r *OutboxRepository) Enqueue(ctx context.Context, event *events.DomainEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	message := &OutboxMessage{
+		ID:          event.EventID,
+		AggregateID: event.AggregateID,
+		TenantID:    event.TenantID,
+		EventType:   string(event.EventType),
+		Payload:     string(data),
+		Status:      OutboxStatusPending,
+	}
+	return r.db.WithContext(ctx).Create(message).Error
+}
+
+// LockPending returns up to limit messages that are next to publish for
+// their aggregate, in insertion order, and locks them for the surrounding
+// transaction. A message is only returned when no earlier message of its
+// aggregate is still unpublished, so a message locked by another relay, or
+// one that failed or is dead, holds back the rest of its aggregate instead
+// of letting them be published ahead of it. Rows locked by another relay
+// are skipped.
+func (r *OutboxRepository) LockPending(ctx context.Context, limit, maxAttempts int) ([]*OutboxMessage, error) {
+	var messages []*OutboxMessage
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status IN ? AND attempts < ?", []string{OutboxStatusPending, OutboxStatusFailed}, maxAttempts).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox earlier
+			WHERE earlier.aggregate_id = outbox.aggregate_id
+			AND earlier.status IN ?
+			AND (earlier.created_at, earlier.id) < (outbox.created_at, outbox.id)
+		)`, outboxUnpublished).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       OutboxStatusPublished,
+			"published_at": publishedAt,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   nil,
+		}).Error
+}
+
+// MarkFailed records a failed attempt. The attempt that reaches
+// maxAttempts makes the message dead.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id string, publishErr error, maxAttempts int) error {
+	return r.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     gorm.Expr("CASE WHEN attempts + 1 >= ? THEN ? ELSE ? END", maxAttempts, OutboxStatusDead, OutboxStatusFailed),
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": publishErr.Error(),
+		}).Error
+}
+
+// ListDead returns the dead messages, oldest first.
+func (r *OutboxRepository) ListDead(ctx context.Context) ([]*OutboxMessage, error) {
+	var messages []*OutboxMessage
+	err := r.db.WithContext(ctx).
+		Where("status = ?", OutboxStatusDead).
+		Order("created_at ASC, id ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+// Requeue gives a dead message a fresh set of attempts.
+func (r *OutboxRepository) Requeue(ctx context.Context, id string) error {
+	return r.setDeadStatus(ctx, id, map[string]interface{}{
+		"status":   OutboxStatusPending,
+		"attempts": 0,
+	})
+}
+
+// Discard gives up on a dead message, so the later messages of its
+// aggregate are published without it.
+func (r *OutboxRepository) Discard(ctx context.Context, id string) error {
+	return r.setDeadStatus(ctx, id, map[string]interface{}{
+		"status": OutboxStatusDiscarded,
+	})
+}
+
+func (r *OutboxRepository) setDeadStatus(ctx context.Context, id string, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ? AND status = ?", id, OutboxStatusDead).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("outbox message %s is not dead", id)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByDueAt     SortField = "due_at"
+	SortByPriority  SortField = "priority"
+	SortByStatus    SortField = "status"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "ASC"
+	SortDesc SortDirection = "DESC"
+)
+
+// sortKeys maps each sort field to a non-null SQL expression and the type
+// its cursor value is cast back to. Interventions without a due date sort
+// after every dated one in ascending order. Priority and status sort by
+// rank rather than alphabetically.
+var sortKeys = map[SortField]struct {
+	expr     string
+	castType string
+}{
+	SortByCreatedAt: {"created_at", "timestamptz"},
+	SortByUpdatedAt: {"updated_at", "timestamptz"},
+	SortByDueAt:     {"COALESCE(due_at, 'infinity'::timestamptz)", "timestamptz"},
+	SortByPriority:  {"CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END", "int"},
+	SortByStatus:    {"CASE status WHEN 'pending' THEN 1 WHEN 'in_progress' THEN 2 WHEN 'completed' THEN 3 WHEN 'cancelled' THEN 4 ELSE 5 END", "int"},
+}
+
+// PageRequest asks for the First rows after the After cursor, ordered by
+// SortBy and then by ID. The zero value is the first DefaultPageSize rows,
+// newest first.
+type PageRequest struct {
+	First     int
+	After     string
+	SortBy    SortField
+	Direction SortDirection
+}
+
+type PageInfo struct {
+	EndCursor   string
+	HasNextPage bool
+}
+
+// cursor is the position of the last row of a page. It records the sort it
+// was issued for, so it cannot be replayed against a different order.
+type cursor struct {
+	SortBy    SortField     `json:"s"`
+	Direction SortDirection `json:"d"`
+	Value     string        `json:"v"`
+	ID        string        `json:"id"`
+}
+
+func (p PageRequest) normalize() (PageRequest, error) {
+	p.First = pageSize(p.First)
+	if p.SortBy == "" {
+		p.SortBy = SortByCreatedAt
+	}
+	if _, ok := sortKeys[p.SortBy]; !ok {
+		return p, fmt.Errorf("unsupported sort field %q", p.SortBy)
+	}
+	if p.Direction == "" {
+		p.Direction = SortDesc
+	}
+	if p.Direction != SortAsc && p.Direction != SortDesc {
+		return p, fmt.Errorf("unsupported sort direction %q", p.Direction)
+	}
+	return p, nil
+}
+
+// pageSize applies the default and the cap to a requested page size.
+func pageSize(first int) int {
+	if first <= 0 {
+		return DefaultPageSize
+	}
+	if first > MaxPageSize {
+		return MaxPageSize
+	}
+	return first
+}
+
+// sortKeyColumn is the column paginate selects the row's sort value into,
+// as text, so the cursor after a page is built from the row exactly as the
+// page query read and ordered it.
+const sortKeyColumn = "page_sort_key"
+
+// paginate selects the rows of table with their sort value, orders them by
+// the requested sort, starts after the cursor and fetches one row more than
+// the page so the caller can tell whether another page follows.
+func paginate(query *gorm.DB, table string, page PageRequest) (*gorm.DB, error) {
+	key := sortKeys[page.SortBy]
+	direction := string(page.Direction)
+
+	if page.After != "" {
+		after, err := decodeCursor(page.After)
+		if err != nil {
+			return nil, err
+		}
+		if after.SortBy != page.SortBy || after.Direction != page.Direction {
+			return nil, fmt.Errorf("%w: issued for a different sort", ErrInvalidCursor)
+		}
+		if err := checkCursorValue(after.Value, key.castType); err != nil {
+			return nil, err
+		}
+		comparison := ">"
+		if page.Direction == SortDesc {
+			comparison = "<"
+		}
+		query = query.Where("("+key.expr+", id) "+comparison+" (?::text::"+key.castType+", ?)", after.Value, after.ID)
+	}
+
+	return query.
+		Select(table + ".*, (" + key.expr + ")::text AS " + sortKeyColumn).
+		Order(key.expr + " " + direction).
+		Order("id " + direction).
+		Limit(page.First + 1), nil
+}
+
+// pageInfo reports whether more rows follow a page of fetched rows and, if
+// so, the cursor after the last row, given its ID and the sort value
+// paginate selected for it.
+func pageInfo(page PageRequest, fetched int, lastID, lastSortKey string) *PageInfo {
+	info := &PageInfo{HasNextPage: fetched > page.First}
+	if lastID == "" {
+		return info
+	}
+	info.EndCursor = encodeCursor(cursor{SortBy: page.SortBy, Direction: page.Direction, Value: lastSortKey, ID: lastID})
+	return info
+}
+
+// timestamptzLayouts are the forms a timestamptz is written in as text:
+// Postgres's own output, with a whole-hour or an hour and minute offset,
+// and RFC 3339.
+var timestamptzLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+}
+
+// checkCursorValue rejects a cursor whose sort value does not read as
+// castType, so that a tampered cursor is an ErrInvalidCursor rather than a
+// failed cast in the page query.
+func checkCursorValue(value, castType string) error {
+	ok := false
+	switch castType {
+	case "timestamptz":
+		if value == "infinity" || value == "-infinity" {
+			ok = true
+			break
+		}
+		for _, layout := range timestamptzLayouts {
+			if t, err := time.Parse(layout, value); err == nil && t.Year() >= 1 {
+				ok = true
+				break
+			}
+		}
+	case "int":
+		_, err := strconv.ParseInt(value, 10, 32)
+		ok = err == nil
+	case "float8":
+		f, err := strconv.ParseFloat(value, 64)
+		ok = err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) && !strings.ContainsAny(value, "xX_")
+	}
+	if !ok {
+		return fmt.Errorf("%w: sort value %q is not a %s", ErrInvalidCursor, value, castType)
+	}
+	return nil
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/domain"
+)
+
+// dryRunDB builds SQL without a database.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=unused"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	return db
+}
+
+func TestPaginateSelectsSortKey(t *testing.T) {
+	db := dryRunDB(t)
+	after := encodeCursor(cursor{SortBy: SortByDueAt, Direction: SortAsc, Value: "2026-01-02 03:04:05.123456+00", ID: "i-1"})
+	page, err := PageRequest{First: 2, After: after, SortBy: SortByDueAt, Direction: SortAsc}.normalize()
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+
+	query, err := paginate(db.Where("tenant_id = ?", "t-1"), "interventions", page)
+	if err != nil {
+		t.Fatalf("paginate: %v", err)
+	}
+	var rows []*pagedIntervention
+	sql := query.Find(&rows).Statement.SQL.String()
+
+	for _, want := range []string{
+		`SELECT interventions.*, (COALESCE(due_at, 'infinity'::timestamptz))::text AS page_sort_key FROM "interventions"`,
+		`(COALESCE(due_at, 'infinity'::timestamptz), id) > ($2::text::timestamptz, $3)`,
+		`ORDER BY COALESCE(due_at, 'infinity'::timestamptz) ASC,id ASC LIMIT $4`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("SQL %q\ndoes not contain %q", sql, want)
+		}
+	}
+}
+
+func TestPaginateRejectsCursorOfAnotherSort(t *testing.T) {
+	after := encodeCursor(cursor{SortBy: SortByCreatedAt, Direction: SortDesc, Value: "x", ID: "i-1"})
+	page, _ := PageRequest{After: after, SortBy: SortByPriority}.normalize()
+	if _, err := paginate(dryRunDB(t), "interventions", page); err == nil {
+		t.Fatal("paginate accepted a created_at cursor for a priority sort")
+	}
+}
+
+func TestPaginateChecksCursorValue(t *testing.T) {
+	tests := []struct {
+		sortBy SortField
+		value  string
+		valid  bool
+	}{
+		{SortByCreatedAt, "2026-01-02 03:04:05.123456+00", true},
+		{SortByCreatedAt, "2026-01-02 08:34:05+05:30", true},
+		{SortByUpdatedAt, "2026-01-02T03:04:05Z", true},
+		{SortByDueAt, "infinity", true},
+		{SortByPriority, "3", true},
+		{SortByStatus, "-1", true},
+		{SortByCreatedAt, "yesterday", false},
+		{SortByCreatedAt, "2026-13-02 03:04:05+00", false},
+		{SortByCreatedAt, "0000-01-02 03:04:05+00", false},
+		{SortByDueAt, "'); DROP TABLE interventions; --", false},
+		{SortByPriority, "high", false},
+		{SortByPriority, "2.5", false},
+		{SortByStatus, "99999999999", false},
+		{SortByStatus, "", false},
+	}
+
+	for _, tt := range tests {
+		after := encodeCursor(cursor{SortBy: tt.sortBy, Direction: SortDesc, Value: tt.value, ID: "i-1"})
+		page, err := PageRequest{After: after, SortBy: tt.sortBy}.normalize()
+		if err != nil {
+			t.Fatalf("normalize: %v", err)
+		}
+		_, err = paginate(dryRunDB(t), "interventions", page)
+		if tt.valid && err != nil {
+			t.Errorf("%s cursor %q: %v", tt.sortBy, tt.value, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s cursor %q: err = %v, want ErrInvalidCursor", tt.sortBy, tt.value, err)
+		}
+	}
+}
+
+func TestPageInfo(t *testing.T) {
+	page := PageRequest{First: 2, SortBy: SortByPriority, Direction: SortDesc}
+
+	info := pageInfo(page, 3, "i-2", "3")
+	if !info.HasNextPage {
+		t.Error("HasNextPage = false with one row more than the page")
+	}
+	c, err := decodeCursor(info.EndCursor)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if *c != (cursor{SortBy: SortByPriority, Direction: SortDesc, Value: "3", ID: "i-2"}) {
+		t.Errorf("cursor = %+v", *c)
+	}
+
+	if info := pageInfo(page, 2, "i-2", "3"); info.HasNextPage {
+		t.Error("HasNextPage = true with a full last page")
+	}
+	if info := pageInfo(page, 0, "", ""); info.EndCursor != "" || info.HasNextPage {
+		t.Errorf("empty page info = %+v", info)
+	}
+}
+
+func TestSearchCursorKeepsFullRankPrecision(t *testing.T) {
+	// A float4 rank widened to float8 in SQL. Printed at float32 precision
+	// it would read "0.1", which is below the row's own rank.
+	rank := float64(float32(0.1))
+	hit := &InterventionSearchResult{Rank: rank}
+	hit.ID = "i-1"
+
+	c, err := decodeCursor(searchCursor(hit))
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	parsed, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil || parsed != rank {
+		t.Fatalf("rank %v written as %q", rank, c.Value)
+	}
+	if c.SortBy != sortByRank || c.ID != "i-1" {
+		t.Errorf("cursor = %+v", *c)
+	}
+}
+
+func TestSearchChecksCursorRank(t *testing.T) {
+	repo := NewInterventionProjectionRepository(dryRunDB(t))
+	for value, valid := range map[string]bool{
+		"0.10000000149011612": true,
+		"1e-05":               true,
+		"NaN":                 false,
+		"Inf":                 false,
+		"0x1p-3":              false,
+		"relevant":            false,
+	} {
+		after := encodeCursor(cursor{SortBy: sortByRank, Value: value, ID: "i-1"})
+		_, _, err := repo.Search(context.Background(), "t-1", "food", InterventionFilter{}, PageRequest{After: after})
+		if valid && err != nil {
+			t.Errorf("rank cursor %q: %v", value, err)
+		}
+		if !valid && !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("rank cursor %q: err = %v, want ErrInvalidCursor", value, err)
+		}
+	}
+}
+
+func TestPagedInterventionKeepsRelations(t *testing.T) {
+	db := dryRunDB(t)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&pagedIntervention{}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if stmt.Schema.Table != (&domain.Intervention{}).TableName() {
+		t.Errorf("table = %q", stmt.Schema.Table)
+	}
+	if _, ok := stmt.Schema.Relationships.Relations["User"]; !ok {
+		t.Error("pagedIntervention lost the User relation, so Preload(\"User\") would fail")
+	}
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/auth"
+	appdb "github.com/lambda/internal/db"
+)
+
+// statementLog is a database/sql driver that answers every query with no
+// rows and records what it was asked to run, including transaction
+// boundaries.
+type statementLog struct{ statements []string }
+
+func (l *statementLog) Connect(ctx context.Context) (driver.Conn, error) { return logConn{l}, nil }
+func (l *statementLog) Driver() driver.Driver                            { return nil }
+
+type logConn struct{ log *statementLog }
+
+func (c logConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("statementLog does not prepare statements")
+}
+func (c logConn) Close() error { return nil }
+func (c logConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+func (c logConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.log.statements = append(c.log.statements, "BEGIN")
+	return logTx{c.log}, nil
+}
+func (c logConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.log.statements = append(c.log.statements, query)
+	return driver.RowsAffected(0), nil
+}
+func (c logConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.log.statements = append(c.log.statements, query)
+	return noRows{}, nil
+}
+
+type logTx struct{ log *statementLog }
+
+func (t logTx) Commit() error   { t.log.statements = append(t.log.statements, "COMMIT"); return nil }
+func (t logTx) Rollback() error { t.log.statements = append(t.log.statements, "ROLLBACK"); return nil }
+
+type noRows struct{}
+
+func (noRows) Columns() []string              { return []string{"name"} }
+func (noRows) Close() error                   { return nil }
+func (noRows) Next(dest []driver.Value) error { return io.EOF }
+
+// tenantDB opens a GORM DB with tenant isolation on top of a statementLog.
+func tenantDB(t *testing.T) (*gorm.DB, *statementLog) {
+	t.Helper()
+	log := &statementLog{}
+	sqlDB := sql.OpenDB(log)
+	sqlDB.SetMaxOpenConns(1)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		t.Fatalf("Use(TenantIsolation): %v", err)
+	}
+	return db, log
+}
+
+func tenantContext() context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", TenantID: "tenant-a", Role: "patient_navigator"})
+}
+
+// assertScopedTransaction checks that statements is one transaction that
+// sets the tenant before selecting from each of tables in turn.
+func assertScopedTransaction(t *testing.T, statements []string, tables ...string) {
+	t.Helper()
+	if len(statements) < 3 || statements[0] != "BEGIN" || statements[len(statements)-1] != "COMMIT" {
+		t.Fatalf("statements = %q, want one committed transaction", statements)
+	}
+	if !strings.Contains(statements[1], "set_config('app.tenant_id'") {
+		t.Fatalf("first statement in the transaction = %q, want the tenant setting", statements[1])
+	}
+	var selects []string
+	for _, statement := range statements[2 : len(statements)-1] {
+		if !strings.Contains(statement, "set_config") {
+			selects = append(selects, statement)
+		}
+	}
+	if len(selects) != len(tables) {
+		t.Fatalf("statements = %q, want %d selects", statements, len(tables))
+	}
+	for i, table := range tables {
+		if !strings.HasPrefix(selects[i], "SELECT") || !strings.Contains(selects[i], table) {
+			t.Errorf("select %d = %q, want one from %s", i, selects[i], table)
+		}
+	}
+}
+
+func TestAnalyticsScanWithPrincipal(t *testing.T) {
+	tests := []struct {
+		name   string
+		read   func(ctx context.Context, repo *InterventionProjectionRepository) error
+		tables []string
+	}{
+		{
+			name: "workload",
+			read: func(ctx context.Context, repo *InterventionProjectionRepository) error {
+				_, err := repo.GetWorkload(ctx, "tenant-a")
+				return err
+			},
+			tables: []string{"intervention_workload_counts", "intervention_workload_counts"},
+		},
+		{
+			name: "barrier counts",
+			read: func(ctx context.Context, repo *InterventionProjectionRepository) error {
+				_, err := repo.GetBarrierCounts(ctx, "tenant-a", BarrierFilter{})
+				return err
+			},
+			tables: []string{"intervention_barrier_counts", "intervention_referral_counts"},
+		},
+		{
+			name: "barrier counts by creator",
+			read: func(ctx context.Context, repo *InterventionProjectionRepository) error {
+				_, err := repo.GetBarrierCounts(ctx, "tenant-a", BarrierFilter{CreatedByIDs: []string{"user-2"}})
+				return err
+			},
+			tables: []string{"unnest(problems)", "unnest(referral_reasons)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, log := tenantDB(t)
+			if err := tt.read(tenantContext(), NewInterventionProjectionRepository(db)); err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			assertScopedTransaction(t, log.statements, tt.tables...)
+		})
+	}
+}
+
+func TestScanWithPrincipalOutsideTransaction(t *testing.T) {
+	db, log := tenantDB(t)
+	var names []string
+	err := db.WithContext(tenantContext()).Table("intervention_workload_counts").Select("assigned_to").Scan(&names).Error
+	if !errors.Is(err, appdb.ErrTenantRowsOutsideTx) {
+		t.Fatalf("Scan = %v, want ErrTenantRowsOutsideTx", err)
+	}
+	if len(log.statements) != 0 {
+		t.Fatalf("statements = %q, want none", log.statements)
+	}
+}
+
+func TestScanWithoutPrincipalIsNotScoped(t *testing.T) {
+	db, log := tenantDB(t)
+	if _, err := NewInterventionProjectionRepository(db).GetWorkload(context.Background(), "tenant-a"); err != nil {
+		t.Fatalf("GetWorkload: %v", err)
+	}
+	for _, statement := range log.statements {
+		if strings.Contains(statement, "set_config") {
+			t.Fatalf("statements = %q, want no tenant setting without a principal", log.statements)
+		}
+	}
+}
+
+func TestGetUserByEmailIsScopedToTenant(t *testing.T) {
+	db, log := tenantDB(t)
+	_, err := NewUserRepository(db).GetUserByEmail(tenantContext(), "tenant-a", "someone@example.com")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("GetUserByEmail = %v, want ErrRecordNotFound", err)
+	}
+
+	// Finding nothing rolls the read's transaction back rather than
+	// committing it.
+	if len(log.statements) != 4 || log.statements[0] != "BEGIN" || log.statements[3] != "ROLLBACK" {
+		t.Fatalf("statements = %q, want one transaction", log.statements)
+	}
+	if !strings.Contains(log.statements[1], "set_config('app.tenant_id'") {
+		t.Errorf("first statement in the transaction = %q, want the tenant setting", log.statements[1])
+	}
+	if query := log.statements[2]; !strings.Contains(query, `FROM "users"`) || !strings.Contains(query, "tenant_id = $1 AND email = $2") {
+		t.Errorf("query = %q, want a select of users by tenant and email", query)
+	}
+}
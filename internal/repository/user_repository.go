@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/google/uuid"
 	"github.com/lambda/internal/domain"
 	"gorm.io/gorm"
 )
@@ -13,13 +16,13 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) CreateUser(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
-func (r *UserRepository) GetUserByEmail(email string) (*domain.User, error) {
+func (r *UserRepository) GetUserByEmail(ctx context.Context, tenantID, email string) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND email = ?", tenantID, email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -29,3 +32,18 @@ func (r *UserRepository) UpdateUserLoginMetadata(userID string) error {
 	// In a real application, you would update fields like last_login_at
 	return nil
 }
+
+// NavigatorIDs returns the IDs of the tenant's active users whose navigator
+// admin is adminID. User IDs are UUIDs, so any other adminID has none.
+func (r *UserRepository) NavigatorIDs(ctx context.Context, tenantID, adminID string) ([]string, error) {
+	var ids []string
+	if _, err := uuid.Parse(adminID); err != nil {
+		return ids, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("tenant_id = ? AND navigator_admin_id = ? AND NOT is_deleted", tenantID, adminID).
+		Order("id").
+		Pluck("id::text", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/domain"
+)
+
+var openStatuses = []string{string(domain.StatusPending), string(domain.StatusInProgress)}
+
+// GetWorkload returns a tenant's open, overdue and completed intervention
+// counts per assignee and per team, read from the pre-aggregated workload
+// table. Assignees and teams with only cancelled interventions are left
+// out.
+func (r *InterventionProjectionRepository) GetWorkload(ctx context.Context, tenantID string) (*domain.WorkloadResponse, error) {
+	response := &domain.WorkloadResponse{
+		ByAssignee: []*domain.WorkloadCount{},
+		ByTeam:     []*domain.WorkloadCount{},
+	}
+	// Scan reads through GORM's Row callback, which tenant isolation only
+	// scopes inside a transaction.
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, grouping := range []struct {
+			column string
+			target *[]*domain.WorkloadCount
+		}{
+			{"assigned_to", &response.ByAssignee},
+			{"assigned_team", &response.ByTeam},
+		} {
+			err := tx.
+				Table("intervention_workload_counts").
+				Select(grouping.column+" AS name, "+
+					"COALESCE(sum(intervention_count) FILTER (WHERE status IN ?), 0) AS open, "+
+					"COALESCE(sum(intervention_count) FILTER (WHERE status IN ? AND due_on < (now() AT TIME ZONE 'UTC')::date), 0) AS overdue, "+
+					"COALESCE(sum(intervention_count) FILTER (WHERE status = ?), 0) AS completed",
+					openStatuses, openStatuses, string(domain.StatusCompleted)).
+				Where("tenant_id = ? AND status <> ?", tenantID, string(domain.StatusCancelled)).
+				Group(grouping.column).
+				Order("open DESC, name").
+				Scan(grouping.target).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
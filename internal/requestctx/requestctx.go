@@ -0,0 +1,148 @@
+// Package requestctx carries who and what caused a request through
+// context.Context, from the GraphQL and API Gateway entry points down to
+// the events the service publishes.
+package requestctx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	lambdaevents "github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+
+	"github.com/lambda/internal/events"
+)
+
+const (
+	HeaderCorrelationID = "X-Correlation-ID"
+	HeaderCausationID   = "X-Causation-ID"
+	HeaderClientApp     = "X-Client-App"
+)
+
+// Event metadata keys, alongside the existing "source".
+const (
+	MetadataCorrelationID = "correlation_id"
+	MetadataCausationID   = "causation_id"
+	MetadataActorID       = "actor_id"
+	MetadataActorRole     = "actor_role"
+	MetadataClientApp     = "client_app"
+)
+
+// Metadata traces a change back to its origin. CorrelationID is shared by
+// everything that follows from one inbound request; CausationID is the ID
+// of the request or event that directly caused this one. ActorID and
+// ActorRole are never read from a request: the auth package sets them from
+// the verified token, and FromEvent carries them over from the event that
+// was handled. ClientApp is whatever the caller says it is.
+type Metadata struct {
+	CorrelationID string
+	CausationID   string
+	ActorID       string
+	ActorRole     string
+	ClientApp     string
+}
+
+type contextKey struct{}
+
+func With(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, contextKey{}, metadata)
+}
+
+func From(ctx context.Context) (Metadata, bool) {
+	metadata, ok := ctx.Value(contextKey{}).(Metadata)
+	return metadata, ok
+}
+
+// FromHTTPRequest reads the tracing headers of an inbound request. A
+// request without a correlation ID starts a new one, and the request itself
+// is the cause.
+func FromHTTPRequest(r *http.Request) Metadata {
+	metadata := Metadata{
+		CorrelationID: r.Header.Get(HeaderCorrelationID),
+		CausationID:   r.Header.Get(HeaderCausationID),
+		ClientApp:     r.Header.Get(HeaderClientApp),
+	}
+	if metadata.CorrelationID == "" {
+		metadata.CorrelationID = uuid.New().String()
+	}
+	if metadata.CausationID == "" {
+		metadata.CausationID = metadata.CorrelationID
+	}
+	if metadata.ClientApp == "" {
+		metadata.ClientApp = r.UserAgent()
+	}
+	return metadata
+}
+
+// FromAPIGatewayRequest reads the tracing headers of a Lambda proxy request.
+// API Gateway's request ID is the cause, and also the correlation ID unless
+// the caller sent one.
+func FromAPIGatewayRequest(request lambdaevents.APIGatewayProxyRequest) Metadata {
+	metadata := Metadata{
+		CorrelationID: header(request.Headers, HeaderCorrelationID),
+		CausationID:   request.RequestContext.RequestID,
+		ClientApp:     header(request.Headers, HeaderClientApp),
+	}
+	if metadata.CausationID == "" {
+		metadata.CausationID = uuid.New().String()
+	}
+	if metadata.CorrelationID == "" {
+		metadata.CorrelationID = metadata.CausationID
+	}
+	if metadata.ClientApp == "" {
+		metadata.ClientApp = header(request.Headers, "User-Agent")
+	}
+	return metadata
+}
+
+// FromEvent continues the trace of an event being handled: work it causes
+// keeps its correlation ID and names the event as the cause.
+func FromEvent(event *events.DomainEvent) Metadata {
+	return Metadata{
+		CorrelationID: event.Metadata[MetadataCorrelationID],
+		CausationID:   event.EventID,
+		ActorID:       event.Metadata[MetadataActorID],
+		ActorRole:     event.Metadata[MetadataActorRole],
+		ClientApp:     event.Metadata[MetadataClientApp],
+	}
+}
+
+// Apply copies the metadata onto an event, leaving unset fields out.
+func (m Metadata) Apply(event *events.DomainEvent) {
+	if event.Metadata == nil {
+		event.Metadata = map[string]string{}
+	}
+	for key, value := range map[string]string{
+		MetadataCorrelationID: m.CorrelationID,
+		MetadataCausationID:   m.CausationID,
+		MetadataActorID:       m.ActorID,
+		MetadataActorRole:     m.ActorRole,
+		MetadataClientApp:     m.ClientApp,
+	} {
+		if value != "" {
+			event.Metadata[key] = value
+		}
+	}
+}
+
+// Middleware puts the request's Metadata on its context and echoes the
+// correlation ID back so callers can quote it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metadata := FromHTTPRequest(r)
+		w.Header().Set(HeaderCorrelationID, metadata.CorrelationID)
+		next.ServeHTTP(w, r.WithContext(With(r.Context(), metadata)))
+	})
+}
+
+// header looks a header up case-insensitively, as API Gateway passes them
+// through with the caller's casing.
+func header(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
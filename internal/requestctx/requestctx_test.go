@@ -0,0 +1,64 @@
+package requestctx
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	lambdaevents "github.com/aws/aws-lambda-go/events"
+
+	"github.com/lambda/internal/events"
+)
+
+func TestActorIsNotReadFromHeaders(t *testing.T) {
+	r := httptest.NewRequest("POST", "/query", nil)
+	r.Header.Set("X-User-ID", "spoofed-user")
+	r.Header.Set("X-User-Role", "navigator_admin")
+	r.Header.Set(HeaderCorrelationID, "correlation-1")
+
+	metadata := FromHTTPRequest(r)
+	if metadata.ActorID != "" || metadata.ActorRole != "" {
+		t.Fatalf("FromHTTPRequest actor = %q/%q, want none", metadata.ActorID, metadata.ActorRole)
+	}
+	if metadata.CorrelationID != "correlation-1" || metadata.CausationID != "correlation-1" {
+		t.Fatalf("FromHTTPRequest ids = %q/%q, want correlation-1", metadata.CorrelationID, metadata.CausationID)
+	}
+
+	request := lambdaevents.APIGatewayProxyRequest{
+		Headers: map[string]string{"x-user-id": "spoofed-user", "x-user-role": "navigator_admin"},
+		RequestContext: lambdaevents.APIGatewayProxyRequestContext{
+			RequestID: "request-1",
+			Authorizer: map[string]interface{}{
+				"claims": map[string]interface{}{"sub": "claimed-user", "custom:role": "navigator_admin"},
+			},
+		},
+	}
+	metadata = FromAPIGatewayRequest(request)
+	if metadata.ActorID != "" || metadata.ActorRole != "" {
+		t.Fatalf("FromAPIGatewayRequest actor = %q/%q, want none", metadata.ActorID, metadata.ActorRole)
+	}
+	if metadata.CausationID != "request-1" || metadata.CorrelationID != "request-1" {
+		t.Fatalf("FromAPIGatewayRequest ids = %q/%q, want request-1", metadata.CorrelationID, metadata.CausationID)
+	}
+}
+
+func TestFromEventContinuesTrace(t *testing.T) {
+	event := &events.DomainEvent{EventID: "event-1"}
+	Metadata{
+		CorrelationID: "correlation-1",
+		CausationID:   "request-1",
+		ActorID:       "user-1",
+		ActorRole:     "patient_navigator",
+		ClientApp:     "navigator-web",
+	}.Apply(event)
+
+	want := Metadata{
+		CorrelationID: "correlation-1",
+		CausationID:   "event-1",
+		ActorID:       "user-1",
+		ActorRole:     "patient_navigator",
+		ClientApp:     "navigator-web",
+	}
+	if got := FromEvent(event); got != want {
+		t.Fatalf("FromEvent = %+v, want %+v", got, want)
+	}
+}
@@ -2,12 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
 )
 
+var (
+	// ErrInvalidCredentials covers a wrong password and an unknown user
+	// alike, so callers cannot probe which emails are registered.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidOTP         = errors.New("invalid one-time password")
+	// ErrSessionExpired means the sign-in session or its code timed out and
+	// sign-in must start again.
+	ErrSessionExpired   = errors.New("sign-in session has expired")
+	ErrUserExists       = errors.New("a user with this email already exists")
+	ErrUserNotConfirmed = errors.New("user has not been confirmed")
+	ErrTooManyAttempts  = errors.New("too many attempts, try again later")
+	// ErrInvalidInput wraps Cognito's rejection of a parameter, such as a
+	// password that does not meet the pool's policy.
+	ErrInvalidInput = errors.New("invalid input")
+)
+
 type AuthService struct {
 	cognitoClient *cognitoidentityprovider.Client
 	userPoolID    string
@@ -23,26 +42,73 @@ func NewAuthService(cfg aws.Config, userPoolID, clientID string) *AuthService {
 	}
 }
 
-func (s *AuthService) CreateCognitoUser(email, password, role, tenantID, navigatorAdminID string) (*string, error) {
-	resp, err := s.cognitoClient.SignUp(context.TODO(), &cognitoidentityprovider.SignUpInput{
-		ClientId: &s.clientID,
-		Password: &password,
-		Username: &email,
-		UserAttributes: []types.AttributeType{
-			{Name: aws.String("email"), Value: aws.String(email)},
-			{Name: aws.String("custom:role"), Value: aws.String(role)},
-			{Name: aws.String("custom:tenant_id"), Value: aws.String(tenantID)},
-			{Name: aws.String("custom:navigator_admin_id"), Value: aws.String(navigatorAdminID)},
-		},
+// CreateCognitoUser creates a confirmed account with a permanent password
+// and returns its Cognito sub. It uses the admin API, so the pool can
+// disallow self sign-up and keep custom:role and custom:tenant_id out of
+// the app client's writable attributes: only the invitation flow, which
+// takes them from the invitation, sets them.
+func (s *AuthService) CreateCognitoUser(ctx context.Context, email, password, role, tenantID, navigatorAdminID string) (*string, error) {
+	attributes := []types.AttributeType{
+		{Name: aws.String("email"), Value: aws.String(email)},
+		{Name: aws.String("email_verified"), Value: aws.String("true")},
+		{Name: aws.String("custom:role"), Value: aws.String(role)},
+		{Name: aws.String("custom:tenant_id"), Value: aws.String(tenantID)},
+	}
+	if navigatorAdminID != "" {
+		attributes = append(attributes, types.AttributeType{Name: aws.String("custom:navigator_admin_id"), Value: aws.String(navigatorAdminID)})
+	}
+	resp, err := s.cognitoClient.AdminCreateUser(ctx, &cognitoidentityprovider.AdminCreateUserInput{
+		UserPoolId:     &s.userPoolID,
+		Username:       &email,
+		MessageAction:  types.MessageActionTypeSuppress,
+		UserAttributes: attributes,
 	})
 	if err != nil {
-		return nil, err
+		return nil, cognitoError(err, ErrInvalidCredentials)
 	}
-	return resp.UserSub, nil
+
+	var sub *string
+	if resp.User != nil {
+		for _, attribute := range resp.User.Attributes {
+			if aws.ToString(attribute.Name) == "sub" {
+				sub = attribute.Value
+			}
+		}
+	}
+	if sub == nil {
+		s.DeleteCognitoUser(ctx, email)
+		return nil, fmt.Errorf("cognito returned no sub for the new user")
+	}
+
+	// A password the pool's policy rejects leaves no account behind.
+	_, err = s.cognitoClient.AdminSetUserPassword(ctx, &cognitoidentityprovider.AdminSetUserPasswordInput{
+		UserPoolId: &s.userPoolID,
+		Username:   &email,
+		Password:   &password,
+		Permanent:  true,
+	})
+	if err != nil {
+		s.DeleteCognitoUser(ctx, email)
+		return nil, cognitoError(err, ErrInvalidCredentials)
+	}
+	return sub, nil
+}
+
+// DeleteCognitoUser removes an account, such as one created for an
+// invitation that then could not be accepted.
+func (s *AuthService) DeleteCognitoUser(ctx context.Context, email string) error {
+	_, err := s.cognitoClient.AdminDeleteUser(ctx, &cognitoidentityprovider.AdminDeleteUserInput{
+		UserPoolId: &s.userPoolID,
+		Username:   &email,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete cognito user %s: %w", email, err)
+	}
+	return nil
 }
 
-func (s *AuthService) StartOTPChallenge(email, password string) (*cognitoidentityprovider.RespondToAuthChallengeOutput, error) {
-	resp, err := s.cognitoClient.InitiateAuth(context.TODO(), &cognitoidentityprovider.InitiateAuthInput{
+func (s *AuthService) StartOTPChallenge(ctx context.Context, email, password string) (*cognitoidentityprovider.RespondToAuthChallengeOutput, error) {
+	resp, err := s.cognitoClient.InitiateAuth(ctx, &cognitoidentityprovider.InitiateAuthInput{
 		AuthFlow: types.AuthFlowTypeCustomAuth,
 		ClientId: &s.clientID,
 		AuthParameters: map[string]string{
@@ -51,10 +117,10 @@ func (s *AuthService) StartOTPChallenge(email, password string) (*cognitoidentit
 		},
 	})
 	if err != nil {
-		return nil, err
+		return nil, cognitoError(err, ErrInvalidCredentials)
 	}
 
-	challengeResp, err := s.cognitoClient.RespondToAuthChallenge(context.TODO(), &cognitoidentityprovider.RespondToAuthChallengeInput{
+	challengeResp, err := s.cognitoClient.RespondToAuthChallenge(ctx, &cognitoidentityprovider.RespondToAuthChallengeInput{
 		ChallengeName: types.ChallengeNameTypeCustomChallenge,
 		ClientId:      &s.clientID,
 		ChallengeResponses: map[string]string{
@@ -63,14 +129,19 @@ func (s *AuthService) StartOTPChallenge(email, password string) (*cognitoidentit
 		Session: resp.Session,
 	})
 	if err != nil {
-		return nil, err
+		return nil, cognitoError(err, ErrInvalidCredentials)
+	}
+	if challengeResp.Session == nil {
+		return nil, fmt.Errorf("cognito returned no session for the OTP challenge")
 	}
 
 	return challengeResp, nil
 }
 
-func (s *AuthService) VerifyOTPChallenge(email, otp, session string) (*types.AuthenticationResultType, error) {
-	resp, err := s.cognitoClient.RespondToAuthChallenge(context.TODO(), &cognitoidentityprovider.RespondToAuthChallengeInput{
+// VerifyOTPChallenge answers the OTP challenge. A wrong code fails the
+// sign-in, so the caller has to start again with StartOTPChallenge.
+func (s *AuthService) VerifyOTPChallenge(ctx context.Context, email, otp, session string) (*types.AuthenticationResultType, error) {
+	resp, err := s.cognitoClient.RespondToAuthChallenge(ctx, &cognitoidentityprovider.RespondToAuthChallengeInput{
 		ChallengeName: types.ChallengeNameTypeCustomChallenge,
 		ClientId:      &s.clientID,
 		ChallengeResponses: map[string]string{
@@ -80,8 +151,56 @@ func (s *AuthService) VerifyOTPChallenge(email, otp, session string) (*types.Aut
 		Session: &session,
 	})
 	if err != nil {
-		return nil, err
+		return nil, cognitoError(err, ErrInvalidOTP)
+	}
+	if resp.AuthenticationResult == nil {
+		return nil, ErrInvalidOTP
 	}
 
 	return resp.AuthenticationResult, nil
 }
+
+// cognitoError translates Cognito's exceptions into this package's errors.
+// notAuthorized is what a NotAuthorizedException means for the call: bad
+// credentials when signing in, a wrong code when answering a challenge.
+// Cognito reports an expired or already used session as
+// NotAuthorizedException too, and only says so in the message.
+func cognitoError(err error, notAuthorized error) error {
+	var (
+		notAuthorizedErr    *types.NotAuthorizedException
+		userNotFoundErr     *types.UserNotFoundException
+		codeMismatchErr     *types.CodeMismatchException
+		expiredCodeErr      *types.ExpiredCodeException
+		usernameExistsErr   *types.UsernameExistsException
+		userNotConfirmedErr *types.UserNotConfirmedException
+		invalidPasswordErr  *types.InvalidPasswordException
+		invalidParameterErr *types.InvalidParameterException
+		tooManyRequestsErr  *types.TooManyRequestsException
+		tooManyFailedErr    *types.TooManyFailedAttemptsException
+		limitExceededErr    *types.LimitExceededException
+	)
+	switch {
+	case errors.As(err, &notAuthorizedErr):
+		if strings.Contains(strings.ToLower(notAuthorizedErr.ErrorMessage()), "invalid session") {
+			return ErrSessionExpired
+		}
+		return notAuthorized
+	case errors.As(err, &userNotFoundErr):
+		return ErrInvalidCredentials
+	case errors.As(err, &codeMismatchErr):
+		return ErrInvalidOTP
+	case errors.As(err, &expiredCodeErr):
+		return ErrSessionExpired
+	case errors.As(err, &usernameExistsErr):
+		return ErrUserExists
+	case errors.As(err, &userNotConfirmedErr):
+		return ErrUserNotConfirmed
+	case errors.As(err, &invalidPasswordErr):
+		return fmt.Errorf("%w: %s", ErrInvalidInput, invalidPasswordErr.ErrorMessage())
+	case errors.As(err, &invalidParameterErr):
+		return fmt.Errorf("%w: %s", ErrInvalidInput, invalidParameterErr.ErrorMessage())
+	case errors.As(err, &tooManyRequestsErr), errors.As(err, &tooManyFailedErr), errors.As(err, &limitExceededErr):
+		return ErrTooManyAttempts
+	}
+	return fmt.Errorf("cognito request failed: %w", err)
+}
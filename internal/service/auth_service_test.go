@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+)
+
+func TestCognitoError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		notAuthorized error
+		want          error
+	}{
+		{"wrong password", &types.NotAuthorizedException{Message: aws.String("Incorrect username or password.")}, ErrInvalidCredentials, ErrInvalidCredentials},
+		{"wrong code", &types.NotAuthorizedException{Message: aws.String("Incorrect username or password.")}, ErrInvalidOTP, ErrInvalidOTP},
+		{"expired session", &types.NotAuthorizedException{Message: aws.String("Invalid session for the user, session is expired.")}, ErrInvalidOTP, ErrSessionExpired},
+		{"used session", &types.NotAuthorizedException{Message: aws.String("Invalid session for the user, session can only be used once.")}, ErrInvalidOTP, ErrSessionExpired},
+		{"unknown user", &types.UserNotFoundException{Message: aws.String("User does not exist.")}, ErrInvalidOTP, ErrInvalidCredentials},
+		{"code mismatch", &types.CodeMismatchException{}, ErrInvalidCredentials, ErrInvalidOTP},
+		{"expired code", &types.ExpiredCodeException{}, ErrInvalidOTP, ErrSessionExpired},
+		{"existing user", &types.UsernameExistsException{}, ErrInvalidCredentials, ErrUserExists},
+		{"unconfirmed", &types.UserNotConfirmedException{}, ErrInvalidCredentials, ErrUserNotConfirmed},
+		{"weak password", &types.InvalidPasswordException{Message: aws.String("Password not long enough")}, ErrInvalidCredentials, ErrInvalidInput},
+		{"bad parameter", &types.InvalidParameterException{Message: aws.String("Invalid email address format.")}, ErrInvalidCredentials, ErrInvalidInput},
+		{"throttled", &types.TooManyRequestsException{}, ErrInvalidCredentials, ErrTooManyAttempts},
+		{"too many failures", &types.TooManyFailedAttemptsException{}, ErrInvalidOTP, ErrTooManyAttempts},
+		{"limit exceeded", &types.LimitExceededException{}, ErrInvalidCredentials, ErrTooManyAttempts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The SDK returns exceptions wrapped in operation errors.
+			err := cognitoError(fmt.Errorf("operation InitiateAuth: %w", tt.err), tt.notAuthorized)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("cognitoError = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCognitoErrorKeepsUnknownErrors(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := cognitoError(cause, ErrInvalidCredentials)
+	if !errors.Is(err, cause) {
+		t.Fatalf("cognitoError = %v, want it to wrap %v", err, cause)
+	}
+	for _, known := range []error{ErrInvalidCredentials, ErrInvalidOTP, ErrSessionExpired, ErrInvalidInput} {
+		if errors.Is(err, known) {
+			t.Errorf("cognitoError(%v) is %v", cause, known)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lambda/internal/authz"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
+)
+
+// InterventionQueryService serves reads from the intervention projection.
+// Like InterventionService, every method checks the caller on ctx against
+// authorizer's policy and fails with authz.ErrForbidden when it may not
+// proceed: intervention reads are scoped to what the caller may read, and
+// the tenant-wide dashboards require authz.ViewAnalytics.
+type InterventionQueryService struct {
+	projections *repository.InterventionProjectionRepository
+	authorizer  *authz.Authorizer
+}
+
+func NewInterventionQueryService(projections *repository.InterventionProjectionRepository, authorizer *authz.Authorizer) *InterventionQueryService {
+	return &InterventionQueryService{projections: projections, authorizer: authorizer}
+}
+
+func (s *InterventionQueryService) GetIntervention(ctx context.Context, tenantID, interventionID string) (*repository.InterventionProjection, error) {
+	intervention, err := s.projections.GetByID(ctx, interventionID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, authz.ReadIntervention, authz.ProjectionResource(intervention)); err != nil {
+		return nil, err
+	}
+	return intervention, nil
+}
+
+func (s *InterventionQueryService) ListInterventions(ctx context.Context, tenantID string, filter repository.InterventionFilter, page repository.PageRequest) ([]*repository.InterventionProjection, *repository.PageInfo, error) {
+	if err := s.authorizer.ScopeFilter(ctx, authz.ReadIntervention, &filter); err != nil {
+		return nil, nil, err
+	}
+	return s.projections.List(ctx, tenantID, filter, page)
+}
+
+func (s *InterventionQueryService) CountInterventions(ctx context.Context, tenantID string, filter repository.InterventionFilter) (int64, error) {
+	if err := s.authorizer.ScopeFilter(ctx, authz.ReadIntervention, &filter); err != nil {
+		return 0, err
+	}
+	return s.projections.Count(ctx, tenantID, filter)
+}
+
+func (s *InterventionQueryService) SearchInterventions(ctx context.Context, tenantID, text string, filter repository.InterventionFilter, page repository.PageRequest) ([]*repository.InterventionSearchResult, *repository.PageInfo, error) {
+	if err := s.authorizer.ScopeFilter(ctx, authz.ReadIntervention, &filter); err != nil {
+		return nil, nil, err
+	}
+	return s.projections.Search(ctx, tenantID, text, filter, page)
+}
+
+func (s *InterventionQueryService) CountSearch(ctx context.Context, tenantID, text string, filter repository.InterventionFilter) (int64, error) {
+	if err := s.authorizer.ScopeFilter(ctx, authz.ReadIntervention, &filter); err != nil {
+		return 0, err
+	}
+	return s.projections.CountSearch(ctx, tenantID, text, filter)
+}
+
+func (s *InterventionQueryService) GetBarrierCounts(ctx context.Context, tenantID string, filter repository.BarrierFilter) (*domain.BarrierResponse, error) {
+	if _, err := s.authorizer.Allow(ctx, authz.ViewAnalytics); err != nil {
+		return nil, err
+	}
+	return s.projections.GetBarrierCounts(ctx, tenantID, filter)
+}
+
+func (s *InterventionQueryService) GetWorkload(ctx context.Context, tenantID string) (*domain.WorkloadResponse, error) {
+	if _, err := s.authorizer.Allow(ctx, authz.ViewAnalytics); err != nil {
+		return nil, err
+	}
+	return s.projections.GetWorkload(ctx, tenantID)
+}
@@ -2,25 +2,43 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lambda/internal/aggregate"
+	"github.com/lambda/internal/authz"
 	"github.com/lambda/internal/domain"
 	"github.com/lambda/internal/events"
 	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/requestctx"
 	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
+// ErrNothingToUpdate is returned by UpdateIntervention when updates sets
+// none of the fields it applies.
+var ErrNothingToUpdate = errors.New("update sets none of assigned_to, assigned_team, priority, notes or problems")
+
 type InterventionService struct {
-	repo      *repository.InterventionRepository
-	publisher events.EventPublisher
+	repo       *repository.InterventionRepository
+	outbox     *repository.OutboxRepository
+	eventStore *repository.EventStoreRepository
+	authorizer *authz.Authorizer
 }
 
-func NewInterventionService(repo *repository.InterventionRepository, publisher events.EventPublisher) *InterventionService {
+// NewInterventionService builds the write-side service. Domain events are
+// appended to the event store and written to the outbox in the same
+// transaction as the intervention change, and published to Kinesis by the
+// outbox relay. Every method checks the caller on ctx against authorizer's
+// policy and fails with authz.ErrForbidden when it may not proceed.
+func NewInterventionService(repo *repository.InterventionRepository, outbox *repository.OutboxRepository, eventStore *repository.EventStoreRepository, authorizer *authz.Authorizer) *InterventionService {
 	return &InterventionService{
-		repo:      repo,
-		publisher: publisher,
+		repo:       repo,
+		outbox:     outbox,
+		eventStore: eventStore,
+		authorizer: authorizer,
 	}
 }
 
@@ -56,49 +74,54 @@ type CreateInterventionsResponse struct {
 }
 
 func (s *InterventionService) CreateInterventions(ctx context.Context, tenantID, userID string, req *CreateInterventionsRequest) (*CreateInterventionsResponse, error) {
+	if _, err := s.authorizer.Allow(ctx, authz.CreateIntervention); err != nil {
+		return nil, err
+	}
+
 	response := &CreateInterventionsResponse{
 		InterventionIDs: []string{},
 		CreatedTasks:    []CreatedTask{},
 	}
 
-	for _, item := range req.Items {
-		priority := "medium"
-		if item.Priority != nil && *item.Priority != "" {
-			priority = *item.Priority
-		}
+	err := s.withinTx(ctx, func(tx *txScope) error {
+		for _, item := range req.Items {
+			priority := "medium"
+			if item.Priority != nil && *item.Priority != "" {
+				priority = *item.Priority
+			}
 
-		intervention := &domain.Intervention{
-			ID:              "int_" + uuid.New().String(),
-			TenantID:        tenantID,
-			PatientID:       req.PatientID,
-			ScreeningID:     req.ScreeningID,
-			Type:            item.Type,
-			Title:           item.Title,
-			Description:     item.Description,
-			Status:          domain.StatusPending,
-			Priority:        priority,
-			CreatedBy:       userID,
-			AssignedTo:      item.AssignedTo,
-			AssignedTeam:    item.AssignedTeam,
-			ReferralReasons: pq.StringArray(item.ReferralReasons),
-			Problems:        pq.StringArray(item.Problems),
-			DueAt:           item.DueInDay,
-		}
+			intervention := &domain.Intervention{
+				ID:              "int_" + uuid.New().String(),
+				TenantID:        tenantID,
+				PatientID:       req.PatientID,
+				ScreeningID:     req.ScreeningID,
+				Type:            item.Type,
+				Title:           item.Title,
+				Description:     item.Description,
+				Status:          domain.StatusPending,
+				Priority:        priority,
+				CreatedBy:       userID,
+				AssignedTo:      item.AssignedTo,
+				AssignedTeam:    item.AssignedTeam,
+				ReferralReasons: pq.StringArray(item.ReferralReasons),
+				Problems:        pq.StringArray(item.Problems),
+				DueAt:           item.DueInDay,
+				Version:         1,
+			}
 
-		if err := s.repo.Create(ctx, intervention); err != nil {
-			return nil, fmt.Errorf("failed to create intervention: %w", err)
-		}
+			if err := tx.interventions.Create(ctx, intervention); err != nil {
+				return fmt.Errorf("failed to create intervention: %w", err)
+			}
 
-		response.InterventionIDs = append(response.InterventionIDs, intervention.ID)
+			response.InterventionIDs = append(response.InterventionIDs, intervention.ID)
 
-		assigneeRole := s.getAssigneeRoleForInterventionType(item.Type)
-		response.CreatedTasks = append(response.CreatedTasks, CreatedTask{
-			TaskID:       "",
-			AssigneeRole: assigneeRole,
-		})
+			assigneeRole := s.getAssigneeRoleForInterventionType(item.Type)
+			response.CreatedTasks = append(response.CreatedTasks, CreatedTask{
+				TaskID:       "",
+				AssigneeRole: assigneeRole,
+			})
 
-		if s.publisher != nil {
-			event := events.NewInterventionCreatedEvent(&events.InterventionCreatedEvent{
+			event, err := events.NewInterventionCreatedEvent(&events.InterventionCreatedEvent{
 				InterventionID:  intervention.ID,
 				TenantID:        intervention.TenantID,
 				PatientID:       intervention.PatientID,
@@ -116,142 +139,326 @@ func (s *InterventionService) CreateInterventions(ctx context.Context, tenantID,
 				Problems:        intervention.Problems,
 				CreatedAt:       intervention.CreatedAt,
 			})
-			if err := s.publisher.Publish(ctx, event); err != nil {
-				return nil, fmt.Errorf("failed to publish intervention created event: %w", err)
+			if err != nil {
+				return err
+			}
+			if err := tx.record(ctx, intervention, event); err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return response, nil
 }
 
 func (s *InterventionService) GetInterventionByID(ctx context.Context, tenantID, interventionID string) (*domain.Intervention, error) {
-	return s.repo.GetByID(ctx, interventionID, tenantID)
+	return s.getAuthorized(ctx, authz.ReadIntervention, tenantID, interventionID)
+}
+
+// GetInterventionAsOf rebuilds the intervention from the event store as it
+// was at asOf. It returns aggregate.ErrNoHistory if nothing had happened to
+// it by then. Whether the caller may read its history depends on the
+// intervention as it is now.
+func (s *InterventionService) GetInterventionAsOf(ctx context.Context, tenantID, interventionID string, asOf time.Time) (*domain.Intervention, error) {
+	if s.eventStore == nil {
+		return nil, errors.New("event store is not configured")
+	}
+	if _, err := s.getAuthorized(ctx, authz.ReadIntervention, tenantID, interventionID); err != nil {
+		return nil, err
+	}
+	history, err := s.eventStore.LoadUntil(ctx, tenantID, interventionID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	rebuilt, err := aggregate.RehydrateIntervention(history)
+	if err != nil {
+		return nil, err
+	}
+	return &rebuilt.Intervention, nil
 }
 
-func (s *InterventionService) ListInterventions(ctx context.Context, tenantID string, filters map[string]interface{}) ([]*domain.Intervention, error) {
-	return s.repo.List(ctx, tenantID, filters)
+func (s *InterventionService) ListInterventions(ctx context.Context, tenantID string, filter repository.InterventionFilter, page repository.PageRequest) ([]*domain.Intervention, *repository.PageInfo, error) {
+	if err := s.authorizer.ScopeFilter(ctx, authz.ReadIntervention, &filter); err != nil {
+		return nil, nil, err
+	}
+	return s.repo.List(ctx, tenantID, filter, page)
+}
+
+func (s *InterventionService) CountInterventions(ctx context.Context, tenantID string, filter repository.InterventionFilter) (int64, error) {
+	if err := s.authorizer.ScopeFilter(ctx, authz.ReadIntervention, &filter); err != nil {
+		return 0, err
+	}
+	return s.repo.Count(ctx, tenantID, filter)
 }
 
 func (s *InterventionService) UpdateIntervention(ctx context.Context, tenantID string, interventionID string, updates map[string]interface{}) error {
-	intervention, err := s.repo.GetByID(ctx, interventionID, tenantID)
+	intervention, err := s.getAuthorized(ctx, authz.UpdateIntervention, tenantID, interventionID)
 	if err != nil {
 		return err
 	}
 
+	// Only the fields below are applied and published; anything else in
+	// updates is ignored so it never reaches the event stream.
+	var changes events.InterventionFieldChanges
 	if assignedTo, ok := updates["assigned_to"].(string); ok {
 		intervention.AssignedTo = &assignedTo
+		changes.AssignedTo = &assignedTo
 	}
 	if assignedTeam, ok := updates["assigned_team"].(string); ok {
 		intervention.AssignedTeam = &assignedTeam
+		changes.AssignedTeam = &assignedTeam
 	}
 	if priority, ok := updates["priority"].(string); ok {
 		intervention.Priority = priority
+		changes.Priority = &priority
 	}
 	if notes, ok := updates["notes"].(string); ok {
 		intervention.Notes = &notes
+		changes.Notes = &notes
 	}
-	if problems, ok := updates["problems"].([]interface{}); ok {
-		var problemsStr []string
-		for _, p := range problems {
-			if s, ok := p.(string); ok {
-				problemsStr = append(problemsStr, s)
-			}
-		}
-		intervention.Problems = pq.StringArray(problemsStr)
+	if problems, ok := toStringSlice(updates["problems"]); ok {
+		intervention.Problems = pq.StringArray(problems)
+		changes.Problems = &problems
 	}
-
-	if err := s.repo.Update(ctx, intervention); err != nil {
-		return err
+	if changes == (events.InterventionFieldChanges{}) {
+		return ErrNothingToUpdate
 	}
 
-	if s.publisher != nil {
-		event := events.NewInterventionUpdatedEvent(&events.InterventionUpdatedEvent{
+	return s.withinTx(ctx, func(tx *txScope) error {
+		if err := tx.interventions.Update(ctx, intervention); err != nil {
+			return err
+		}
+
+		event, err := events.NewInterventionUpdatedEvent(&events.InterventionUpdatedEvent{
 			InterventionID: interventionID,
 			TenantID:       tenantID,
-			UpdatedFields:  updates,
+			UpdatedFields:  changes,
 			UpdatedAt:      time.Now().UTC(),
 		})
-		if err := s.publisher.Publish(ctx, event); err != nil {
-			return fmt.Errorf("failed to publish intervention updated event: %w", err)
+		if err != nil {
+			return err
 		}
-	}
-
-	return nil
+		return tx.record(ctx, intervention, event)
+	})
 }
 
 func (s *InterventionService) CompleteIntervention(ctx context.Context, tenantID string, interventionID string, notes string) error {
-	intervention, err := s.repo.GetByID(ctx, interventionID, tenantID)
+	intervention, err := s.getAuthorized(ctx, authz.CompleteIntervention, tenantID, interventionID)
 	if err != nil {
 		return err
 	}
 
+	if err := intervention.TransitionTo(domain.StatusCompleted); err != nil {
+		return err
+	}
+
 	now := time.Now().UTC()
-	intervention.Status = domain.StatusCompleted
 	intervention.CompletedAt = &now
 	if notes != "" {
 		intervention.Notes = &notes
 	}
 
-	if err := s.repo.Update(ctx, intervention); err != nil {
-		return err
-	}
+	return s.withinTx(ctx, func(tx *txScope) error {
+		if err := tx.interventions.Update(ctx, intervention); err != nil {
+			return err
+		}
 
-	if s.publisher != nil {
 		var notesPtr *string
 		if notes != "" {
 			notesPtr = &notes
 		}
-		event := events.NewInterventionCompletedEvent(&events.InterventionCompletedEvent{
+		event, err := events.NewInterventionCompletedEvent(&events.InterventionCompletedEvent{
 			InterventionID: interventionID,
 			TenantID:       tenantID,
 			CompletedAt:    now,
 			Notes:          notesPtr,
 		})
-		if err := s.publisher.Publish(ctx, event); err != nil {
-			return fmt.Errorf("failed to publish intervention completed event: %w", err)
+		if err != nil {
+			return err
 		}
-	}
-
-	return nil
+		return tx.record(ctx, intervention, event)
+	})
 }
 
 func (s *InterventionService) CancelIntervention(ctx context.Context, tenantID string, interventionID string, reason string) error {
-	intervention, err := s.repo.GetByID(ctx, interventionID, tenantID)
+	intervention, err := s.getAuthorized(ctx, authz.CancelIntervention, tenantID, interventionID)
 	if err != nil {
 		return err
 	}
 
-	intervention.Status = domain.StatusCancelled
+	if err := intervention.TransitionTo(domain.StatusCancelled); err != nil {
+		return err
+	}
 	if reason != "" {
 		intervention.Notes = &reason
 	}
 
-	if err := s.repo.Update(ctx, intervention); err != nil {
+	return s.withinTx(ctx, func(tx *txScope) error {
+		if err := tx.interventions.Update(ctx, intervention); err != nil {
+			return err
+		}
+
+		var reasonPtr *string
+		if reason != "" {
+			reasonPtr = &reason
+		}
+		event, err := events.NewInterventionCancelledEvent(&events.InterventionCancelledEvent{
+			InterventionID: interventionID,
+			TenantID:       tenantID,
+			CancelledAt:    time.Now().UTC(),
+			Reason:         reasonPtr,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, intervention, event)
+	})
+}
+
+func (s *InterventionService) StartIntervention(ctx context.Context, tenantID string, interventionID string) error {
+	intervention, err := s.getAuthorized(ctx, authz.StartIntervention, tenantID, interventionID)
+	if err != nil {
+		return err
+	}
+
+	if err := intervention.TransitionTo(domain.StatusInProgress); err != nil {
+		return err
+	}
+
+	return s.withinTx(ctx, func(tx *txScope) error {
+		if err := tx.interventions.Update(ctx, intervention); err != nil {
+			return err
+		}
+
+		event, err := events.NewInterventionStartedEvent(&events.InterventionStartedEvent{
+			InterventionID: interventionID,
+			TenantID:       tenantID,
+			StartedAt:      time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, intervention, event)
+	})
+}
+
+func (s *InterventionService) ReopenIntervention(ctx context.Context, tenantID string, interventionID string, reason string) error {
+	intervention, err := s.getAuthorized(ctx, authz.ReopenIntervention, tenantID, interventionID)
+	if err != nil {
 		return err
 	}
 
-	if s.publisher != nil {
+	previousStatus := intervention.Status
+	if err := intervention.TransitionTo(domain.StatusPending); err != nil {
+		return err
+	}
+	intervention.CompletedAt = nil
+	if reason != "" {
+		intervention.Notes = &reason
+	}
+
+	return s.withinTx(ctx, func(tx *txScope) error {
+		if err := tx.interventions.Update(ctx, intervention); err != nil {
+			return err
+		}
+
 		var reasonPtr *string
 		if reason != "" {
 			reasonPtr = &reason
 		}
-		event := events.NewInterventionCancelledEvent(&events.InterventionCancelledEvent{
+		event, err := events.NewInterventionReopenedEvent(&events.InterventionReopenedEvent{
 			InterventionID: interventionID,
 			TenantID:       tenantID,
-			CancelledAt:    time.Now().UTC(),
+			PreviousStatus: previousStatus,
+			ReopenedAt:     time.Now().UTC(),
 			Reason:         reasonPtr,
 		})
-		if err := s.publisher.Publish(ctx, event); err != nil {
-			return fmt.Errorf("failed to publish intervention cancelled event: %w", err)
+		if err != nil {
+			return err
 		}
+		return tx.record(ctx, intervention, event)
+	})
+}
+
+// getAuthorized loads the intervention and checks the caller may perform
+// action on it.
+func (s *InterventionService) getAuthorized(ctx context.Context, action authz.Action, tenantID, interventionID string) (*domain.Intervention, error) {
+	intervention, err := s.repo.GetByID(ctx, interventionID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, action, authz.ResourceOf(intervention)); err != nil {
+		return nil, err
 	}
+	return intervention, nil
+}
 
-	return nil
+// toStringSlice accepts both []string (GraphQL) and []interface{} (JSON
+// request bodies).
+func toStringSlice(v interface{}) ([]string, bool) {
+	switch values := v.(type) {
+	case []string:
+		return values, true
+	case []interface{}:
+		result := make([]string, 0, len(values))
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result, true
+	}
+	return nil, false
 }
 
-func (s *InterventionService) GetBarrierCounts(ctx context.Context, tenantID string, filters map[string]interface{}) (*domain.BarrierResponse, error) {
-	return s.repo.GetBarrierCounts(ctx, tenantID, filters)
+// txScope holds the repositories bound to one write-model transaction.
+type txScope struct {
+	interventions *repository.InterventionRepository
+	outbox        *repository.OutboxRepository
+	eventStore    *repository.EventStoreRepository
+}
+
+// withinTx runs fn with repositories bound to a single write-model
+// transaction, so an intervention change and its event are committed or
+// rolled back together.
+func (s *InterventionService) withinTx(ctx context.Context, fn func(tx *txScope) error) error {
+	return s.repo.Transaction(ctx, func(db *gorm.DB) error {
+		scope := &txScope{interventions: s.repo.WithTx(db)}
+		if s.outbox != nil {
+			scope.outbox = s.outbox.WithTx(db)
+		}
+		if s.eventStore != nil {
+			scope.eventStore = s.eventStore.WithTx(db)
+		}
+		return fn(scope)
+	})
+}
+
+// record stamps the event with the intervention's version, which the
+// projection uses to apply events for one intervention in order, and with
+// the request metadata on ctx. It then appends the event to the event store
+// and writes it to the outbox.
+func (tx *txScope) record(ctx context.Context, intervention *domain.Intervention, event *events.DomainEvent) error {
+	event.Sequence = intervention.Version
+	if metadata, ok := requestctx.From(ctx); ok {
+		metadata.Apply(event)
+	}
+
+	if tx.eventStore != nil {
+		if err := tx.eventStore.Append(ctx, event); err != nil {
+			return fmt.Errorf("failed to store %s event: %w", event.EventType, err)
+		}
+	}
+	if tx.outbox != nil {
+		if err := tx.outbox.Enqueue(ctx, event); err != nil {
+			return fmt.Errorf("failed to enqueue %s event: %w", event.EventType, err)
+		}
+	}
+	return nil
 }
 
 func (s *InterventionService) getAssigneeRoleForInterventionType(interventionType domain.InterventionType) string {
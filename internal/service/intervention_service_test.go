@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/aggregate"
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
+)
+
+// These tests need a migrated write model at TEST_DATABASE_URL. Each runs
+// in a transaction that is rolled back.
+func writeModelDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+func newTestInterventionService(db *gorm.DB) *InterventionService {
+	return NewInterventionService(
+		repository.NewInterventionRepository(db),
+		repository.NewOutboxRepository(db),
+		repository.NewEventStoreRepository(db),
+		authz.NewAuthorizer(authz.DefaultPolicy, repository.NewUserRepository(db)),
+	)
+}
+
+func callerContext(tenantID, userID string, role domain.Role) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID, TenantID: tenantID, Role: string(role)})
+}
+
+// createIntervention has the caller on ctx create one intervention with
+// every optional field set, and returns its ID.
+func createIntervention(t *testing.T, svc *InterventionService, ctx context.Context, tenantID, userID string) string {
+	t.Helper()
+	description := "Refer to the food bank on Main Street"
+	priority := "high"
+	due := time.Now().Add(72 * time.Hour).UTC()
+	created, err := svc.CreateInterventions(ctx, tenantID, userID, &CreateInterventionsRequest{
+		PatientID:   "patient-" + uuid.NewString(),
+		ScreeningID: "screening-1",
+		Items: []InterventionItem{{
+			Type:            domain.TypeSocialWork,
+			Title:           "Food bank referral",
+			Description:     &description,
+			Priority:        &priority,
+			DueInDay:        &due,
+			ReferralReasons: []string{"food insecurity"},
+			Problems:        []string{"food"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("CreateInterventions: %v", err)
+	}
+	return created.InterventionIDs[0]
+}
+
+// comparable drops what replaying events cannot reproduce, the row's own
+// updated_at and the preloaded assignee, and rounds times to what Postgres
+// stores.
+func comparable(intervention domain.Intervention) domain.Intervention {
+	round := func(at *time.Time) *time.Time {
+		if at == nil {
+			return nil
+		}
+		rounded := at.Round(time.Microsecond).UTC()
+		return &rounded
+	}
+	intervention.UpdatedAt = time.Time{}
+	intervention.User = nil
+	intervention.CreatedAt = *round(&intervention.CreatedAt)
+	intervention.DueAt = round(intervention.DueAt)
+	intervention.CompletedAt = round(intervention.CompletedAt)
+	return intervention
+}
+
+// assertRehydrated replays the intervention's events and compares the
+// result with the row the service wrote.
+func assertRehydrated(t *testing.T, db *gorm.DB, tenantID, id string) {
+	t.Helper()
+	var row domain.Intervention
+	if err := db.Where("id = ? AND tenant_id = ?", id, tenantID).Take(&row).Error; err != nil {
+		t.Fatalf("read intervention: %v", err)
+	}
+	history, err := repository.NewEventStoreRepository(db).Load(context.Background(), tenantID, id)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	rebuilt, err := aggregate.RehydrateIntervention(history)
+	if err != nil {
+		t.Fatalf("RehydrateIntervention: %v", err)
+	}
+	if got, want := comparable(rebuilt.Intervention), comparable(row); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rebuilt from %d events:\n%+v\nrow:\n%+v", len(history), got, want)
+	}
+}
+
+func TestRehydrationReproducesTheRow(t *testing.T) {
+	db := writeModelDB(t)
+	svc := newTestInterventionService(db)
+	tenantID := "tenant-" + uuid.NewString()
+	navigator := uuid.NewString()
+	ctx := callerContext(tenantID, navigator, domain.RolePatientNavigator)
+
+	id := createIntervention(t, svc, ctx, tenantID, navigator)
+	assertRehydrated(t, db, tenantID, id)
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"update", func() error {
+			return svc.UpdateIntervention(ctx, tenantID, id, map[string]interface{}{
+				"assigned_to":   navigator,
+				"assigned_team": "social work",
+				"priority":      "urgent",
+				"notes":         "Patient prefers mornings",
+				"problems":      []interface{}{"food", "transport"},
+			})
+		}},
+		{"start", func() error { return svc.StartIntervention(ctx, tenantID, id) }},
+		{"complete", func() error { return svc.CompleteIntervention(ctx, tenantID, id, "Enrolled") }},
+		{"reopen", func() error { return svc.ReopenIntervention(ctx, tenantID, id, "Enrollment lapsed") }},
+		{"cancel", func() error { return svc.CancelIntervention(ctx, tenantID, id, "Moved out of area") }},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		assertRehydrated(t, db, tenantID, id)
+	}
+}
+
+func TestEmptyUpdateAppendsNoEvent(t *testing.T) {
+	db := writeModelDB(t)
+	svc := newTestInterventionService(db)
+	tenantID := "tenant-" + uuid.NewString()
+	navigator := uuid.NewString()
+	ctx := callerContext(tenantID, navigator, domain.RolePatientNavigator)
+	id := createIntervention(t, svc, ctx, tenantID, navigator)
+
+	err := svc.UpdateIntervention(ctx, tenantID, id, map[string]interface{}{"status": "completed"})
+	if !errors.Is(err, ErrNothingToUpdate) {
+		t.Fatalf("UpdateIntervention = %v, want ErrNothingToUpdate", err)
+	}
+
+	history, err := repository.NewEventStoreRepository(db).Load(context.Background(), tenantID, id)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("history has %d events, want only the created event", len(history))
+	}
+	assertRehydrated(t, db, tenantID, id)
+}
+
+func TestNavigatorAdminWorksTheirNavigatorsInterventions(t *testing.T) {
+	db := writeModelDB(t)
+	svc := newTestInterventionService(db)
+	users := repository.NewUserRepository(db)
+	tenantID := "tenant-" + uuid.NewString()
+	admin := uuid.New()
+
+	// One navigator reports to the admin; the other to nobody.
+	navigator, outsider := uuid.New(), uuid.New()
+	for _, user := range []*domain.User{
+		{ID: navigator, TenantID: tenantID, Email: navigator.String() + "@example.com", Username: navigator.String(), Role: domain.RolePatientNavigator, NavigatorAdminID: &admin},
+		{ID: outsider, TenantID: tenantID, Email: outsider.String() + "@example.com", Username: outsider.String(), Role: domain.RolePatientNavigator},
+	} {
+		if err := users.CreateUser(context.Background(), user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+	teamWork := createIntervention(t, svc, callerContext(tenantID, navigator.String(), domain.RolePatientNavigator), tenantID, navigator.String())
+	otherWork := createIntervention(t, svc, callerContext(tenantID, outsider.String(), domain.RolePatientNavigator), tenantID, outsider.String())
+
+	ctx := callerContext(tenantID, admin.String(), domain.RoleNavigatorAdmin)
+	if _, err := svc.GetInterventionByID(ctx, tenantID, teamWork); err != nil {
+		t.Errorf("admin reading their navigator's intervention: %v", err)
+	}
+	if err := svc.UpdateIntervention(ctx, tenantID, teamWork, map[string]interface{}{"priority": "urgent"}); err != nil {
+		t.Errorf("admin updating their navigator's intervention: %v", err)
+	}
+	if _, err := svc.GetInterventionByID(ctx, tenantID, otherWork); !errors.Is(err, authz.ErrForbidden) {
+		t.Errorf("admin reading another navigator's intervention: err = %v, want ErrForbidden", err)
+	}
+	if err := svc.UpdateIntervention(ctx, tenantID, otherWork, map[string]interface{}{"priority": "urgent"}); !errors.Is(err, authz.ErrForbidden) {
+		t.Errorf("admin updating another navigator's intervention: err = %v, want ErrForbidden", err)
+	}
+
+	listed, _, err := svc.ListInterventions(ctx, tenantID, repository.InterventionFilter{}, repository.PageRequest{})
+	if err != nil {
+		t.Fatalf("ListInterventions: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != teamWork || listed[0].Priority != "urgent" {
+		t.Fatalf("admin lists %d interventions, want only their navigator's, updated", len(listed))
+	}
+
+	// A deleted navigator is no longer on the team.
+	if err := db.Model(&domain.User{}).Where("id = ?", navigator).Update("is_deleted", true).Error; err != nil {
+		t.Fatalf("delete navigator: %v", err)
+	}
+	if _, err := svc.GetInterventionByID(ctx, tenantID, teamWork); !errors.Is(err, authz.ErrForbidden) {
+		t.Errorf("admin reading a deleted navigator's intervention: err = %v, want ErrForbidden", err)
+	}
+}
@@ -1,50 +1,353 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
 	"github.com/lambda/internal/domain"
 	"github.com/lambda/internal/repository"
-	"github.com/golang-jwt/jwt/v4"
-	"time"
 )
 
+var (
+	ErrInvalidInvitation  = errors.New("invalid invitation")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationExpired  = errors.New("invitation has expired")
+	ErrInvitationUsed     = errors.New("invitation has already been used")
+	ErrInvitationRevoked  = errors.New("invitation has been revoked")
+)
+
+// invitationTTL is how long an invitation can be accepted.
+const invitationTTL = 7 * 24 * time.Hour
+
+// invitationStore is the part of InvitationRepository the service uses.
+type invitationStore interface {
+	CreateInvitation(ctx context.Context, invitation *domain.Invitation) error
+	FindInvitationByToken(ctx context.Context, token string) (*domain.Invitation, error)
+	FindInvitation(ctx context.Context, tenantID string, id uuid.UUID) (*domain.Invitation, error)
+	ListPendingInvitations(ctx context.Context, tenantID string, invitedBy *uuid.UUID) ([]*domain.Invitation, error)
+	MarkAccepted(ctx context.Context, id uuid.UUID, user *domain.User, at time.Time) error
+	MarkRevoked(ctx context.Context, tenantID string, id uuid.UUID, at time.Time) error
+	RotateToken(ctx context.Context, tenantID string, id uuid.UUID, token string, expiresAt time.Time) error
+	ExpirePending(ctx context.Context, now time.Time) (int64, error)
+}
+
+// InvitationService manages invitations through their lifecycle. Sending,
+// revoking, resending and listing are for navigator admins, who manage the
+// invitations of their tenant; validating and accepting are done by the
+// invited person, who has no account yet, with the token alone.
 type InvitationService struct {
-	repo      *repository.InvitationRepository
+	repo      invitationStore
+	accounts  Accounts
 	jwtSecret []byte
 }
 
-func NewInvitationService(repo *repository.InvitationRepository, jwtSecret string) *InvitationService {
+// NewInvitationService returns a service that registers accepted
+// invitations' users with accounts, which may be nil where invitations are
+// not accepted.
+func NewInvitationService(repo *repository.InvitationRepository, accounts Accounts, jwtSecret string) *InvitationService {
 	return &InvitationService{
 		repo:      repo,
+		accounts:  accounts,
 		jwtSecret: []byte(jwtSecret),
 	}
 }
 
-func (s *InvitationService) CreateInvitation(invitation *domain.Invitation) (string, error) {
-	claims := jwt.MapClaims{
-		"email": invitation.Email,
-		"role":  invitation.Role,
-		"exp":   time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.jwtSecret)
+// CreateInvitation invites someone to the caller's tenant and returns the
+// token to send them.
+func (s *InvitationService) CreateInvitation(ctx context.Context, invitation *domain.Invitation) (string, error) {
+	principal, inviterID, err := s.inviter(ctx)
 	if err != nil {
 		return "", err
 	}
+	if !strings.Contains(invitation.Email, "@") {
+		return "", fmt.Errorf("%w: %q is not an email address", ErrInvalidInput, invitation.Email)
+	}
+	if !invitation.Role.Valid() {
+		return "", fmt.Errorf("%w: unknown role %q", ErrInvalidInput, invitation.Role)
+	}
+	if invitation.ID == uuid.Nil {
+		invitation.ID = uuid.New()
+	}
+	invitation.TenantID = principal.TenantID
+	invitation.InvitedBy = inviterID
+	invitation.Status = domain.InvitationPending
 
-	invitation.Token = tokenString
-	invitation.ExpiresAt = time.Now().Add(time.Hour * 24 * 7)
-	if err := s.repo.CreateInvitation(invitation); err != nil {
+	token, expiresAt, err := s.sign(invitation)
+	if err != nil {
 		return "", err
 	}
-	return tokenString, nil
+	invitation.Token = token
+	invitation.ExpiresAt = expiresAt
+	if err := s.repo.CreateInvitation(ctx, invitation); err != nil {
+		return "", err
+	}
+	return token, nil
 }
 
-func (s *InvitationService) ValidateInvitationToken(tokenString string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+// ValidateInvitationToken checks the token's signature and expiry, and that
+// it belongs to an invitation that can still be accepted, which it returns.
+func (s *InvitationService) ValidateInvitationToken(ctx context.Context, tokenString string) (*domain.Invitation, error) {
+	_, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return s.jwtSecret, nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrInvitationExpired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInvitation, err)
+	}
+
+	invitation, err := s.repo.FindInvitationByToken(ctx, tokenString)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidInvitation
+	}
 	if err != nil {
 		return nil, err
 	}
-	return token, nil
+	if err := invitationStateError(invitation, time.Now()); err != nil {
+		return nil, err
+	}
+	// Invitations sent before they recorded a tenant cannot say which
+	// tenant the new user joins.
+	if invitation.TenantID == "" {
+		return nil, fmt.Errorf("%w: invitation has no tenant", ErrInvalidInvitation)
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation registers the invited person with the invitation's
+// email, role and tenant and the password they chose, and marks the
+// invitation accepted by the new account in the same transaction that adds
+// them to users. Cognito's PostConfirmation trigger does not run for
+// accounts created by an admin, so this is where their users row is
+// written. If the invitation cannot be marked, because it was accepted or
+// revoked meanwhile or has just expired, the account is deleted again, so
+// only accepted invitations leave accounts behind.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, tokenString, password string) (*domain.Invitation, error) {
+	if s.accounts == nil {
+		return nil, errors.New("invitation service cannot register accounts")
+	}
+	invitation, err := s.ValidateInvitationToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.accounts.CreateCognitoUser(ctx, invitation.Email, password, string(invitation.Role), invitation.TenantID, invitation.NavigatorAdminID())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markAccepted(ctx, invitation, *sub); err != nil {
+		if deleteErr := s.accounts.DeleteCognitoUser(ctx, invitation.Email); deleteErr != nil {
+			return nil, fmt.Errorf("%w; removing the account created for it also failed: %v", err, deleteErr)
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// markAccepted records that the account userID registered with the
+// invitation, if it is still pending, and stores the user with the
+// invitation's tenant, role and navigator admin.
+func (s *InvitationService) markAccepted(ctx context.Context, invitation *domain.Invitation, userID string) error {
+	acceptedBy, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("user ID %q is not a UUID", userID)
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:        acceptedBy,
+		TenantID:  invitation.TenantID,
+		Email:     invitation.Email,
+		Username:  invitation.Email,
+		Role:      invitation.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if invitation.NavigatorAdminID() != "" {
+		navigatorAdminID := invitation.InvitedBy
+		user.NavigatorAdminID = &navigatorAdminID
+	}
+	if err := s.repo.MarkAccepted(ctx, invitation.ID, user, now); err != nil {
+		if errors.Is(err, repository.ErrInvitationChanged) {
+			return s.changedError(ctx, invitation, err)
+		}
+		return err
+	}
+	invitation.Status = domain.InvitationAccepted
+	invitation.IsUsed = true
+	invitation.AcceptedBy = &acceptedBy
+	invitation.AcceptedAt = &now
+	return nil
+}
+
+// RevokeInvitation withdraws a pending or expired invitation of the
+// caller's tenant, so its token can no longer be accepted or resent.
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id string) (*domain.Invitation, error) {
+	invitation, err := s.managedInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.repo.MarkRevoked(ctx, invitation.TenantID, invitation.ID, now); err != nil {
+		if errors.Is(err, repository.ErrInvitationChanged) {
+			return nil, s.changedError(ctx, invitation, err)
+		}
+		return nil, err
+	}
+	invitation.Status = domain.InvitationRevoked
+	invitation.RevokedAt = &now
+	return invitation, nil
+}
+
+// ResendInvitation gives a pending or expired invitation of the caller's
+// tenant a new token and a new expiry, and returns it with the new token.
+// The previous token stops working.
+func (s *InvitationService) ResendInvitation(ctx context.Context, id string) (*domain.Invitation, error) {
+	invitation, err := s.managedInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := s.sign(invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RotateToken(ctx, invitation.TenantID, invitation.ID, token, expiresAt); err != nil {
+		if errors.Is(err, repository.ErrInvitationChanged) {
+			return nil, s.changedError(ctx, invitation, err)
+		}
+		return nil, err
+	}
+	invitation.Token = token
+	invitation.ExpiresAt = expiresAt
+	invitation.Status = domain.InvitationPending
+	return invitation, nil
+}
+
+// ListPendingInvitations returns the invitations of the caller's tenant
+// that can still be accepted. A non-empty invitedBy keeps only those that
+// user sent.
+func (s *InvitationService) ListPendingInvitations(ctx context.Context, invitedBy string) ([]*domain.Invitation, error) {
+	principal, _, err := s.inviter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var inviter *uuid.UUID
+	if invitedBy != "" {
+		id, err := uuid.Parse(invitedBy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invitedBy %q is not a UUID", ErrInvalidInput, invitedBy)
+		}
+		inviter = &id
+	}
+	return s.repo.ListPendingInvitations(ctx, principal.TenantID, inviter)
+}
+
+// ExpireInvitations marks every tenant's pending invitations that are past
+// their expiry as expired, and returns how many it marked. It is for the
+// invitation sweeper, which runs without a principal.
+func (s *InvitationService) ExpireInvitations(ctx context.Context) (int64, error) {
+	return s.repo.ExpirePending(ctx, time.Now())
+}
+
+// inviter returns the caller, who must be a navigator admin, and their ID.
+func (s *InvitationService) inviter(ctx context.Context) (*auth.Principal, uuid.UUID, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	if domain.Role(principal.Role) != domain.RoleNavigatorAdmin {
+		return nil, uuid.Nil, fmt.Errorf("%w: only %s may manage invitations", authz.ErrForbidden, domain.RoleNavigatorAdmin)
+	}
+	inviterID, err := uuid.Parse(principal.UserID)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("%w: user ID %q is not a UUID", authz.ErrForbidden, principal.UserID)
+	}
+	return principal, inviterID, nil
+}
+
+// managedInvitation loads an invitation of the caller's tenant for the
+// caller to revoke or resend. Accepted and revoked invitations are final.
+func (s *InvitationService) managedInvitation(ctx context.Context, id string) (*domain.Invitation, error) {
+	principal, _, err := s.inviter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invitationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvitationNotFound
+	}
+
+	invitation, err := s.repo.FindInvitation(ctx, principal.TenantID, invitationID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch invitation.Status {
+	case domain.InvitationAccepted:
+		return nil, ErrInvitationUsed
+	case domain.InvitationRevoked:
+		return nil, ErrInvitationRevoked
+	}
+	return invitation, nil
+}
+
+// changedError explains a conditional update that lost a race, by what
+// the invitation has become since it was read.
+func (s *InvitationService) changedError(ctx context.Context, invitation *domain.Invitation, err error) error {
+	current, findErr := s.repo.FindInvitation(ctx, invitation.TenantID, invitation.ID)
+	if findErr != nil {
+		return err
+	}
+	if stateErr := invitationStateError(current, time.Now()); stateErr != nil {
+		return stateErr
+	}
+	return err
+}
+
+// sign issues a token for the invitation that expires invitationTTL from
+// now. Its jti is the invitation's ID.
+func (s *InvitationService) sign(invitation *domain.Invitation) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(invitationTTL)
+	claims := jwt.MapClaims{
+		"jti":   invitation.ID.String(),
+		"email": invitation.Email,
+		"role":  invitation.Role,
+		"iat":   now.Unix(),
+		"exp":   expiresAt.Unix(),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// invitationStateError is why the invitation cannot be accepted at now,
+// or nil if it can.
+func invitationStateError(invitation *domain.Invitation, now time.Time) error {
+	switch {
+	case invitation.Status == domain.InvitationAccepted || invitation.IsUsed:
+		return ErrInvitationUsed
+	case invitation.Status == domain.InvitationRevoked:
+		return ErrInvitationRevoked
+	case invitation.Status == domain.InvitationExpired || now.After(invitation.ExpiresAt):
+		return ErrInvitationExpired
+	}
+	return nil
 }
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
+)
+
+const testSecret = "test-secret"
+
+var adminID = uuid.MustParse("0b6c7d2e-5f4a-4c1b-9e8d-7a6f5e4d3c2b")
+
+// invitationTable keeps invitations, and the users who accepted them, in
+// memory with the conditional updates of InvitationRepository. beforeMark,
+// when set, runs before MarkAccepted, to change the invitation under an
+// accept in progress.
+type invitationTable struct {
+	invitations map[uuid.UUID]*domain.Invitation
+	users       map[uuid.UUID]*domain.User
+	beforeMark  func(invitation *domain.Invitation)
+	markErr     error
+}
+
+func newInvitationTable() *invitationTable {
+	return &invitationTable{invitations: map[uuid.UUID]*domain.Invitation{}, users: map[uuid.UUID]*domain.User{}}
+}
+
+func (t *invitationTable) CreateInvitation(ctx context.Context, invitation *domain.Invitation) error {
+	stored := *invitation
+	t.invitations[invitation.ID] = &stored
+	return nil
+}
+
+func (t *invitationTable) FindInvitationByToken(ctx context.Context, token string) (*domain.Invitation, error) {
+	for _, invitation := range t.invitations {
+		if invitation.Token == token {
+			found := *invitation
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (t *invitationTable) FindInvitation(ctx context.Context, tenantID string, id uuid.UUID) (*domain.Invitation, error) {
+	invitation, ok := t.invitations[id]
+	if !ok || invitation.TenantID != tenantID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *invitation
+	return &found, nil
+}
+
+func (t *invitationTable) ListPendingInvitations(ctx context.Context, tenantID string, invitedBy *uuid.UUID) ([]*domain.Invitation, error) {
+	var pending []*domain.Invitation
+	for _, invitation := range t.invitations {
+		if invitation.TenantID == tenantID && invitation.Status == domain.InvitationPending && (invitedBy == nil || invitation.InvitedBy == *invitedBy) {
+			found := *invitation
+			pending = append(pending, &found)
+		}
+	}
+	return pending, nil
+}
+
+func (t *invitationTable) MarkAccepted(ctx context.Context, id uuid.UUID, user *domain.User, at time.Time) error {
+	invitation := t.invitations[id]
+	if t.beforeMark != nil {
+		t.beforeMark(invitation)
+	}
+	if t.markErr != nil {
+		return t.markErr
+	}
+	if invitation.Status != domain.InvitationPending || !invitation.ExpiresAt.After(at) {
+		return repository.ErrInvitationChanged
+	}
+	invitation.Status = domain.InvitationAccepted
+	invitation.IsUsed = true
+	invitation.AcceptedBy = &user.ID
+	invitation.AcceptedAt = &at
+	stored := *user
+	t.users[user.ID] = &stored
+	return nil
+}
+
+func (t *invitationTable) MarkRevoked(ctx context.Context, tenantID string, id uuid.UUID, at time.Time) error {
+	invitation, ok := t.invitations[id]
+	if !ok || invitation.TenantID != tenantID || (invitation.Status != domain.InvitationPending && invitation.Status != domain.InvitationExpired) {
+		return repository.ErrInvitationChanged
+	}
+	invitation.Status = domain.InvitationRevoked
+	invitation.RevokedAt = &at
+	return nil
+}
+
+func (t *invitationTable) RotateToken(ctx context.Context, tenantID string, id uuid.UUID, token string, expiresAt time.Time) error {
+	invitation, ok := t.invitations[id]
+	if !ok || invitation.TenantID != tenantID || (invitation.Status != domain.InvitationPending && invitation.Status != domain.InvitationExpired) {
+		return repository.ErrInvitationChanged
+	}
+	invitation.Token = token
+	invitation.Status = domain.InvitationPending
+	invitation.ExpiresAt = expiresAt
+	return nil
+}
+
+func (t *invitationTable) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	var expired int64
+	for _, invitation := range t.invitations {
+		if invitation.Status == domain.InvitationPending && !invitation.ExpiresAt.After(now) {
+			invitation.Status = domain.InvitationExpired
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// accountBook records the accounts an InvitationService creates and
+// deletes.
+type accountBook struct {
+	created   map[string]string
+	deleted   []string
+	createErr error
+	deleteErr error
+}
+
+func (a *accountBook) CreateCognitoUser(ctx context.Context, email, password, role, tenantID, navigatorAdminID string) (*string, error) {
+	if a.createErr != nil {
+		return nil, a.createErr
+	}
+	if a.created == nil {
+		a.created = map[string]string{}
+	}
+	sub := uuid.NewString()
+	a.created[email] = role + " in " + tenantID
+	return &sub, nil
+}
+
+func (a *accountBook) DeleteCognitoUser(ctx context.Context, email string) error {
+	a.deleted = append(a.deleted, email)
+	return a.deleteErr
+}
+
+func newTestInvitationService() (*InvitationService, *invitationTable, *accountBook) {
+	table := newInvitationTable()
+	accounts := &accountBook{}
+	return &InvitationService{repo: table, accounts: accounts, jwtSecret: []byte(testSecret)}, table, accounts
+}
+
+func adminContext(tenantID string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: adminID.String(), TenantID: tenantID, Role: string(domain.RoleNavigatorAdmin)})
+}
+
+// seed stores an invitation of tenant-a in status, with a token issued an
+// hour ago that is valid for six more days.
+func seed(t *testing.T, table *invitationTable, status domain.InvitationStatus, expiresAt time.Time) *domain.Invitation {
+	t.Helper()
+	invitation := &domain.Invitation{
+		ID:        uuid.New(),
+		TenantID:  "tenant-a",
+		Email:     "invitee@example.com",
+		Role:      domain.RoleSocialWorker,
+		InvitedBy: adminID,
+		Status:    status,
+		ExpiresAt: expiresAt,
+		IsUsed:    status == domain.InvitationAccepted,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti": invitation.ID.String(),
+		"iat": time.Now().Add(-time.Hour).Unix(),
+		"exp": time.Now().Add(6 * 24 * time.Hour).Unix(),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	invitation.Token = token
+	table.CreateInvitation(context.Background(), invitation)
+	return invitation
+}
+
+func TestInvitationStateError(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		invitation domain.Invitation
+		want       error
+	}{
+		{"pending", domain.Invitation{Status: domain.InvitationPending, ExpiresAt: now.Add(time.Hour)}, nil},
+		{"pending past its expiry", domain.Invitation{Status: domain.InvitationPending, ExpiresAt: now.Add(-time.Hour)}, ErrInvitationExpired},
+		{"expired", domain.Invitation{Status: domain.InvitationExpired, ExpiresAt: now.Add(time.Hour)}, ErrInvitationExpired},
+		{"accepted", domain.Invitation{Status: domain.InvitationAccepted, ExpiresAt: now.Add(time.Hour)}, ErrInvitationUsed},
+		{"used before statuses", domain.Invitation{Status: domain.InvitationPending, IsUsed: true, ExpiresAt: now.Add(time.Hour)}, ErrInvitationUsed},
+		{"accepted past its expiry", domain.Invitation{Status: domain.InvitationAccepted, ExpiresAt: now.Add(-time.Hour)}, ErrInvitationUsed},
+		{"revoked", domain.Invitation{Status: domain.InvitationRevoked, ExpiresAt: now.Add(time.Hour)}, ErrInvitationRevoked},
+		{"revoked past its expiry", domain.Invitation{Status: domain.InvitationRevoked, ExpiresAt: now.Add(-time.Hour)}, ErrInvitationRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := invitationStateError(&tt.invitation, now); err != tt.want {
+				t.Fatalf("invitationStateError = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	s, table, accounts := newTestInvitationService()
+	token, err := s.CreateInvitation(adminContext("tenant-a"), &domain.Invitation{Email: "navigator@example.com", Role: domain.RolePatientNavigator})
+	if err != nil {
+		t.Fatalf("CreateInvitation: %v", err)
+	}
+
+	invitation, err := s.AcceptInvitation(context.Background(), token, "password")
+	if err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	if invitation.Status != domain.InvitationAccepted || invitation.AcceptedBy == nil {
+		t.Fatalf("invitation = %+v, want it accepted", invitation)
+	}
+	if stored := table.invitations[invitation.ID]; *stored.AcceptedBy != *invitation.AcceptedBy {
+		t.Errorf("stored accepted_by = %v, want %v", stored.AcceptedBy, invitation.AcceptedBy)
+	}
+	if got := accounts.created["navigator@example.com"]; got != "patient_navigator in tenant-a" {
+		t.Errorf("account = %q, want a patient_navigator in tenant-a", got)
+	}
+	user, ok := table.users[*invitation.AcceptedBy]
+	if !ok {
+		t.Fatalf("no user stored for the account %v", *invitation.AcceptedBy)
+	}
+	if user.TenantID != "tenant-a" || user.Email != "navigator@example.com" || user.Role != domain.RolePatientNavigator ||
+		user.NavigatorAdminID == nil || *user.NavigatorAdminID != adminID {
+		t.Errorf("user = %+v, want a patient_navigator of tenant-a reporting to the inviter", user)
+	}
+
+	if _, err := s.AcceptInvitation(context.Background(), token, "password"); !errors.Is(err, ErrInvitationUsed) {
+		t.Fatalf("second AcceptInvitation = %v, want ErrInvitationUsed", err)
+	}
+	if len(accounts.created) != 1 || len(accounts.deleted) != 0 {
+		t.Errorf("accounts created %v and deleted %v, want the one account kept", accounts.created, accounts.deleted)
+	}
+}
+
+func TestAcceptInvitationThatCannotBeAccepted(t *testing.T) {
+	tests := []struct {
+		status    domain.InvitationStatus
+		expiresAt time.Time
+		want      error
+	}{
+		{domain.InvitationAccepted, time.Now().Add(time.Hour), ErrInvitationUsed},
+		{domain.InvitationRevoked, time.Now().Add(time.Hour), ErrInvitationRevoked},
+		{domain.InvitationExpired, time.Now().Add(-time.Hour), ErrInvitationExpired},
+		{domain.InvitationPending, time.Now().Add(-time.Hour), ErrInvitationExpired},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			s, table, accounts := newTestInvitationService()
+			invitation := seed(t, table, tt.status, tt.expiresAt)
+			if _, err := s.AcceptInvitation(context.Background(), invitation.Token, "password"); !errors.Is(err, tt.want) {
+				t.Fatalf("AcceptInvitation = %v, want %v", err, tt.want)
+			}
+			if len(accounts.created) != 0 {
+				t.Errorf("accounts created: %v", accounts.created)
+			}
+		})
+	}
+}
+
+func TestAcceptInvitationDeletesTheAccountWhenMarkingFails(t *testing.T) {
+	storeDown := errors.New("connection reset")
+	tests := []struct {
+		name       string
+		beforeMark func(invitation *domain.Invitation)
+		markErr    error
+		want       error
+	}{
+		{
+			name:       "revoked meanwhile",
+			beforeMark: func(invitation *domain.Invitation) { invitation.Status = domain.InvitationRevoked },
+			want:       ErrInvitationRevoked,
+		},
+		{
+			name: "accepted meanwhile",
+			beforeMark: func(invitation *domain.Invitation) {
+				invitation.Status = domain.InvitationAccepted
+				invitation.IsUsed = true
+			},
+			want: ErrInvitationUsed,
+		},
+		{
+			name:       "expired meanwhile",
+			beforeMark: func(invitation *domain.Invitation) { invitation.ExpiresAt = time.Now().Add(-time.Second) },
+			want:       ErrInvitationExpired,
+		},
+		{name: "store failure", markErr: storeDown, want: storeDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, table, accounts := newTestInvitationService()
+			invitation := seed(t, table, domain.InvitationPending, time.Now().Add(time.Hour))
+			table.beforeMark = tt.beforeMark
+			table.markErr = tt.markErr
+
+			if _, err := s.AcceptInvitation(context.Background(), invitation.Token, "password"); !errors.Is(err, tt.want) {
+				t.Fatalf("AcceptInvitation = %v, want %v", err, tt.want)
+			}
+			if len(accounts.deleted) != 1 || accounts.deleted[0] != invitation.Email {
+				t.Fatalf("deleted accounts = %v, want %s", accounts.deleted, invitation.Email)
+			}
+			if table.invitations[invitation.ID].AcceptedBy != nil || len(table.users) != 0 {
+				t.Errorf("invitation was accepted, or its user stored")
+			}
+		})
+	}
+}
+
+func TestAcceptedSpecialistReportsToNobody(t *testing.T) {
+	s, table, _ := newTestInvitationService()
+	invitation := seed(t, table, domain.InvitationPending, time.Now().Add(time.Hour))
+
+	accepted, err := s.AcceptInvitation(context.Background(), invitation.Token, "password")
+	if err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	user := table.users[*accepted.AcceptedBy]
+	if user == nil || user.Role != domain.RoleSocialWorker || user.NavigatorAdminID != nil {
+		t.Fatalf("user = %+v, want a social_worker without a navigator admin", user)
+	}
+}
+
+func TestAcceptInvitationReportsAFailedDelete(t *testing.T) {
+	s, table, accounts := newTestInvitationService()
+	invitation := seed(t, table, domain.InvitationPending, time.Now().Add(time.Hour))
+	table.beforeMark = func(invitation *domain.Invitation) { invitation.Status = domain.InvitationRevoked }
+	accounts.deleteErr = errors.New("throttled")
+
+	_, err := s.AcceptInvitation(context.Background(), invitation.Token, "password")
+	if !errors.Is(err, ErrInvitationRevoked) {
+		t.Fatalf("AcceptInvitation = %v, want ErrInvitationRevoked", err)
+	}
+	if want := "invitation has been revoked; removing the account created for it also failed: throttled"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestAcceptInvitationKeepsItPendingWhenRegistrationFails(t *testing.T) {
+	s, table, accounts := newTestInvitationService()
+	invitation := seed(t, table, domain.InvitationPending, time.Now().Add(time.Hour))
+	accounts.createErr = ErrUserExists
+
+	if _, err := s.AcceptInvitation(context.Background(), invitation.Token, "password"); !errors.Is(err, ErrUserExists) {
+		t.Fatalf("AcceptInvitation = %v, want ErrUserExists", err)
+	}
+	if status := table.invitations[invitation.ID].Status; status != domain.InvitationPending {
+		t.Errorf("status = %s, want pending", status)
+	}
+	if len(accounts.deleted) != 0 {
+		t.Errorf("deleted accounts = %v, want none", accounts.deleted)
+	}
+}
+
+func TestRevokeAndResendFinalInvitations(t *testing.T) {
+	tests := []struct {
+		status domain.InvitationStatus
+		want   error
+	}{
+		{domain.InvitationAccepted, ErrInvitationUsed},
+		{domain.InvitationRevoked, ErrInvitationRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			s, table, _ := newTestInvitationService()
+			invitation := seed(t, table, tt.status, time.Now().Add(time.Hour))
+			ctx := adminContext("tenant-a")
+
+			if _, err := s.RevokeInvitation(ctx, invitation.ID.String()); !errors.Is(err, tt.want) {
+				t.Errorf("RevokeInvitation = %v, want %v", err, tt.want)
+			}
+			if _, err := s.ResendInvitation(ctx, invitation.ID.String()); !errors.Is(err, tt.want) {
+				t.Errorf("ResendInvitation = %v, want %v", err, tt.want)
+			}
+			if stored := table.invitations[invitation.ID]; stored.Status != tt.status || stored.Token != invitation.Token {
+				t.Errorf("invitation changed to %+v", stored)
+			}
+		})
+	}
+}
+
+func TestRevokeInvitation(t *testing.T) {
+	for _, status := range []domain.InvitationStatus{domain.InvitationPending, domain.InvitationExpired} {
+		t.Run(string(status), func(t *testing.T) {
+			s, table, _ := newTestInvitationService()
+			invitation := seed(t, table, status, time.Now().Add(time.Hour))
+
+			revoked, err := s.RevokeInvitation(adminContext("tenant-a"), invitation.ID.String())
+			if err != nil {
+				t.Fatalf("RevokeInvitation: %v", err)
+			}
+			if revoked.Status != domain.InvitationRevoked || revoked.RevokedAt == nil {
+				t.Errorf("invitation = %+v, want it revoked", revoked)
+			}
+			if _, err := s.ValidateInvitationToken(context.Background(), invitation.Token); !errors.Is(err, ErrInvitationRevoked) {
+				t.Errorf("ValidateInvitationToken = %v, want ErrInvitationRevoked", err)
+			}
+		})
+	}
+}
+
+func TestResendExpiredInvitation(t *testing.T) {
+	s, table, _ := newTestInvitationService()
+	invitation := seed(t, table, domain.InvitationExpired, time.Now().Add(-time.Hour))
+
+	resent, err := s.ResendInvitation(adminContext("tenant-a"), invitation.ID.String())
+	if err != nil {
+		t.Fatalf("ResendInvitation: %v", err)
+	}
+	if resent.Status != domain.InvitationPending || !resent.ExpiresAt.After(time.Now().Add(invitationTTL-time.Minute)) {
+		t.Errorf("invitation = %+v, want it pending for another %v", resent, invitationTTL)
+	}
+	if _, err := s.ValidateInvitationToken(context.Background(), resent.Token); err != nil {
+		t.Errorf("new token: %v", err)
+	}
+	if _, err := s.ValidateInvitationToken(context.Background(), invitation.Token); !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("old token = %v, want ErrInvalidInvitation", err)
+	}
+}
+
+func TestManagingInvitationsNeedsANavigatorAdmin(t *testing.T) {
+	s, table, _ := newTestInvitationService()
+	invitation := seed(t, table, domain.InvitationPending, time.Now().Add(time.Hour))
+	id := invitation.ID.String()
+	manage := map[string]func(ctx context.Context) error{
+		"create": func(ctx context.Context) error {
+			_, err := s.CreateInvitation(ctx, &domain.Invitation{Email: "someone@example.com", Role: domain.RoleSocialWorker})
+			return err
+		},
+		"revoke": func(ctx context.Context) error { _, err := s.RevokeInvitation(ctx, id); return err },
+		"resend": func(ctx context.Context) error { _, err := s.ResendInvitation(ctx, id); return err },
+		"list":   func(ctx context.Context) error { _, err := s.ListPendingInvitations(ctx, ""); return err },
+	}
+	navigator := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: adminID.String(), TenantID: "tenant-a", Role: string(domain.RolePatientNavigator)})
+	for name, run := range manage {
+		if err := run(context.Background()); !errors.Is(err, auth.ErrUnauthenticated) {
+			t.Errorf("%s without a principal = %v, want ErrUnauthenticated", name, err)
+		}
+		if err := run(navigator); !errors.Is(err, authz.ErrForbidden) {
+			t.Errorf("%s as a patient navigator = %v, want ErrForbidden", name, err)
+		}
+	}
+
+	// Another tenant's admin cannot see the invitation.
+	if _, err := s.RevokeInvitation(adminContext("tenant-b"), id); !errors.Is(err, ErrInvitationNotFound) {
+		t.Errorf("RevokeInvitation from another tenant = %v, want ErrInvitationNotFound", err)
+	}
+	if status := table.invitations[invitation.ID].Status; status != domain.InvitationPending {
+		t.Errorf("status = %s, want pending", status)
+	}
+}
+
+func TestExpireInvitations(t *testing.T) {
+	s, table, _ := newTestInvitationService()
+	overdue := seed(t, table, domain.InvitationPending, time.Now().Add(-time.Minute))
+	current := seed(t, table, domain.InvitationPending, time.Now().Add(time.Hour))
+	accepted := seed(t, table, domain.InvitationAccepted, time.Now().Add(-time.Minute))
+	revoked := seed(t, table, domain.InvitationRevoked, time.Now().Add(-time.Minute))
+
+	expired, err := s.ExpireInvitations(context.Background())
+	if err != nil {
+		t.Fatalf("ExpireInvitations: %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("expired %d invitations, want 1", expired)
+	}
+	want := map[*domain.Invitation]domain.InvitationStatus{
+		overdue:  domain.InvitationExpired,
+		current:  domain.InvitationPending,
+		accepted: domain.InvitationAccepted,
+		revoked:  domain.InvitationRevoked,
+	}
+	for invitation, status := range want {
+		if got := table.invitations[invitation.ID].Status; got != status {
+			t.Errorf("invitation seeded %s is %s, want %s", invitation.Status, got, status)
+		}
+	}
+	if _, err := s.ValidateInvitationToken(context.Background(), overdue.Token); !errors.Is(err, ErrInvitationExpired) {
+		t.Errorf("ValidateInvitationToken = %v, want ErrInvitationExpired", err)
+	}
+
+	// A second sweep finds nothing more to expire.
+	if expired, err := s.ExpireInvitations(context.Background()); err != nil || expired != 0 {
+		t.Errorf("second sweep = %d, %v; want 0", expired, err)
+	}
+}
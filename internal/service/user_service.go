@@ -1,22 +1,118 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
 	"github.com/lambda/internal/domain"
 	"github.com/lambda/internal/repository"
 )
 
+// Accounts creates and removes people's sign-in accounts. AuthService
+// implements it with Cognito.
+type Accounts interface {
+	CreateCognitoUser(ctx context.Context, email, password, role, tenantID, navigatorAdminID string) (*string, error)
+	DeleteCognitoUser(ctx context.Context, email string) error
+}
+
+// userStore is the part of UserRepository the service uses.
+type userStore interface {
+	CreateUser(ctx context.Context, user *domain.User) error
+	GetUserByEmail(ctx context.Context, tenantID, email string) (*domain.User, error)
+}
+
 type UserService struct {
-	repo *repository.UserRepository
+	repo     userStore
+	accounts Accounts
+}
+
+// NewUserService returns a service that registers users' accounts with
+// accounts, which may be nil where users are not registered.
+func NewUserService(repo *repository.UserRepository, accounts Accounts) *UserService {
+	return &UserService{repo: repo, accounts: accounts}
 }
 
-func NewUserService(repo *repository.UserRepository) *UserService {
-	return &UserService{repo: repo}
+func (s *UserService) CreateUser(ctx context.Context, user *domain.User) error {
+	return s.repo.CreateUser(ctx, user)
 }
 
-func (s *UserService) CreateUser(user *domain.User) error {
-	return s.repo.CreateUser(user)
+func (s *UserService) GetUserByEmail(ctx context.Context, tenantID, email string) (*domain.User, error) {
+	return s.repo.GetUserByEmail(ctx, tenantID, email)
 }
 
-func (s *UserService) GetUserByEmail(email string) (*domain.User, error) {
-	return s.repo.GetUserByEmail(email)
+// RegisterUser creates an account, and its users row, for someone in the
+// caller's tenant without an invitation. Only navigator admins may do it.
+// The tenant is the caller's, and navigators report to the caller;
+// tenantID and navigatorAdminID are only checked against those, so a
+// client cannot choose them. If the row cannot be stored, the account is
+// deleted again.
+func (s *UserService) RegisterUser(ctx context.Context, email, password string, role domain.Role, tenantID, navigatorAdminID string) (*domain.User, error) {
+	if s.accounts == nil {
+		return nil, errors.New("user service cannot register accounts")
+	}
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if domain.Role(principal.Role) != domain.RoleNavigatorAdmin {
+		return nil, fmt.Errorf("%w: only %s may register users", authz.ErrForbidden, domain.RoleNavigatorAdmin)
+	}
+	adminID, err := uuid.Parse(principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: user ID %q is not a UUID", authz.ErrForbidden, principal.UserID)
+	}
+	if tenantID != "" && tenantID != principal.TenantID {
+		return nil, fmt.Errorf("%w: users can only be registered in your own tenant", authz.ErrForbidden)
+	}
+	if !strings.Contains(email, "@") {
+		return nil, fmt.Errorf("%w: %q is not an email address", ErrInvalidInput, email)
+	}
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		TenantID:  principal.TenantID,
+		Email:     email,
+		Username:  email,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	switch role {
+	case domain.RolePatientNavigator, domain.RoleNurseNavigator:
+		user.NavigatorAdminID = &adminID
+	}
+	if navigatorAdminID != "" && (user.NavigatorAdminID == nil || navigatorAdminID != adminID.String()) {
+		return nil, fmt.Errorf("%w: a navigator's navigator admin is the one registering them, and other roles have none", ErrInvalidInput)
+	}
+	var reportsTo string
+	if user.NavigatorAdminID != nil {
+		reportsTo = user.NavigatorAdminID.String()
+	}
+
+	sub, err := s.accounts.CreateCognitoUser(ctx, email, password, string(role), principal.TenantID, reportsTo)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID, err = uuid.Parse(*sub); err != nil {
+		err = fmt.Errorf("user ID %q is not a UUID", *sub)
+	} else {
+		err = s.repo.CreateUser(ctx, user)
+	}
+	if err != nil {
+		if deleteErr := s.accounts.DeleteCognitoUser(ctx, email); deleteErr != nil {
+			return nil, fmt.Errorf("%w; removing the account created for it also failed: %v", err, deleteErr)
+		}
+		return nil, err
+	}
+	return user, nil
 }
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	"github.com/lambda/internal/domain"
+)
+
+var registrarID = uuid.MustParse("5d1e7c0a-3b2f-4e6d-8a9c-1f0e2d3c4b5a")
+
+// userDirectory keeps users in memory, failing to store them with
+// createErr when it is set.
+type userDirectory struct {
+	users     map[uuid.UUID]*domain.User
+	createErr error
+}
+
+func (d *userDirectory) CreateUser(ctx context.Context, user *domain.User) error {
+	if d.createErr != nil {
+		return d.createErr
+	}
+	stored := *user
+	d.users[user.ID] = &stored
+	return nil
+}
+
+func (d *userDirectory) GetUserByEmail(ctx context.Context, tenantID, email string) (*domain.User, error) {
+	for _, user := range d.users {
+		if user.TenantID == tenantID && user.Email == email {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// signups records the attributes of the accounts a UserService creates,
+// and the emails of those it deletes.
+type signups struct {
+	created   map[string][3]string
+	deleted   []string
+	createErr error
+}
+
+func (s *signups) CreateCognitoUser(ctx context.Context, email, password, role, tenantID, navigatorAdminID string) (*string, error) {
+	if s.createErr != nil {
+		return nil, s.createErr
+	}
+	s.created[email] = [3]string{role, tenantID, navigatorAdminID}
+	sub := uuid.NewString()
+	return &sub, nil
+}
+
+func (s *signups) DeleteCognitoUser(ctx context.Context, email string) error {
+	s.deleted = append(s.deleted, email)
+	return nil
+}
+
+func newTestUserService() (*UserService, *userDirectory, *signups) {
+	directory := &userDirectory{users: map[uuid.UUID]*domain.User{}}
+	accounts := &signups{created: map[string][3]string{}}
+	return &UserService{repo: directory, accounts: accounts}, directory, accounts
+}
+
+func registrarContext(role domain.Role) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: registrarID.String(), TenantID: "tenant-a", Role: string(role)})
+}
+
+func TestRegisterUser(t *testing.T) {
+	tests := []struct {
+		name             string
+		role             domain.Role
+		tenantID         string
+		navigatorAdminID string
+		reportsTo        string
+	}{
+		{"navigator", domain.RolePatientNavigator, "", "", registrarID.String()},
+		{"navigator naming the caller", domain.RoleNurseNavigator, "tenant-a", registrarID.String(), registrarID.String()},
+		{"specialist", domain.RoleSocialWorker, "tenant-a", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, directory, accounts := newTestUserService()
+
+			user, err := s.RegisterUser(registrarContext(domain.RoleNavigatorAdmin), "someone@example.com", "password", tt.role, tt.tenantID, tt.navigatorAdminID)
+			if err != nil {
+				t.Fatalf("RegisterUser: %v", err)
+			}
+			if got, want := accounts.created["someone@example.com"], [3]string{string(tt.role), "tenant-a", tt.reportsTo}; got != want {
+				t.Errorf("account = %v, want %v", got, want)
+			}
+			stored := directory.users[user.ID]
+			if stored == nil || stored.TenantID != "tenant-a" || stored.Email != "someone@example.com" || stored.Role != tt.role {
+				t.Fatalf("stored user = %+v, want a %s of tenant-a", stored, tt.role)
+			}
+			var reportsTo string
+			if stored.NavigatorAdminID != nil {
+				reportsTo = stored.NavigatorAdminID.String()
+			}
+			if reportsTo != tt.reportsTo {
+				t.Errorf("navigator admin = %q, want %q", reportsTo, tt.reportsTo)
+			}
+		})
+	}
+}
+
+func TestRegisterUserRejects(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		email            string
+		role             domain.Role
+		tenantID         string
+		navigatorAdminID string
+		want             error
+	}{
+		{"anonymous", context.Background(), "someone@example.com", domain.RolePatientNavigator, "", "", auth.ErrUnauthenticated},
+		{"navigator", registrarContext(domain.RolePatientNavigator), "someone@example.com", domain.RolePatientNavigator, "", "", authz.ErrForbidden},
+		{"other tenant", registrarContext(domain.RoleNavigatorAdmin), "someone@example.com", domain.RolePatientNavigator, "tenant-b", "", authz.ErrForbidden},
+		{"bad email", registrarContext(domain.RoleNavigatorAdmin), "someone", domain.RolePatientNavigator, "", "", ErrInvalidInput},
+		{"unknown role", registrarContext(domain.RoleNavigatorAdmin), "someone@example.com", "superuser", "", "", ErrInvalidInput},
+		{"another navigator admin", registrarContext(domain.RoleNavigatorAdmin), "someone@example.com", domain.RolePatientNavigator, "", uuid.NewString(), ErrInvalidInput},
+		{"navigator admin for a specialist", registrarContext(domain.RoleNavigatorAdmin), "someone@example.com", domain.RoleSocialWorker, "", registrarID.String(), ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, directory, accounts := newTestUserService()
+
+			if _, err := s.RegisterUser(tt.ctx, tt.email, "password", tt.role, tt.tenantID, tt.navigatorAdminID); !errors.Is(err, tt.want) {
+				t.Fatalf("RegisterUser = %v, want %v", err, tt.want)
+			}
+			if len(accounts.created) != 0 || len(directory.users) != 0 {
+				t.Errorf("accounts %v and users %v created, want none", accounts.created, directory.users)
+			}
+		})
+	}
+}
+
+func TestRegisterUserDeletesTheAccountWhenStoringFails(t *testing.T) {
+	s, directory, accounts := newTestUserService()
+	directory.createErr = errors.New("connection reset")
+
+	if _, err := s.RegisterUser(registrarContext(domain.RoleNavigatorAdmin), "someone@example.com", "password", domain.RolePatientNavigator, "", ""); !errors.Is(err, directory.createErr) {
+		t.Fatalf("RegisterUser = %v, want the store's error", err)
+	}
+	if len(accounts.deleted) != 1 || accounts.deleted[0] != "someone@example.com" {
+		t.Errorf("deleted accounts = %v, want the one created", accounts.deleted)
+	}
+}
+
+func TestRegisterExistingUser(t *testing.T) {
+	s, directory, accounts := newTestUserService()
+	accounts.createErr = ErrUserExists
+
+	if _, err := s.RegisterUser(registrarContext(domain.RoleNavigatorAdmin), "someone@example.com", "password", domain.RolePatientNavigator, "", ""); !errors.Is(err, ErrUserExists) {
+		t.Fatalf("RegisterUser = %v, want ErrUserExists", err)
+	}
+	if len(accounts.deleted) != 0 || len(directory.users) != 0 {
+		t.Errorf("deleted accounts %v and stored users %v, want neither", accounts.deleted, directory.users)
+	}
+}
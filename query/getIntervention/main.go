@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -10,12 +11,17 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	appdb "github.com/lambda/internal/db"
 	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/service"
 )
 
 var (
-	db             *gorm.DB
-	projectionRepo *repository.InterventionProjectionRepository
+	db           *gorm.DB
+	queryService *service.InterventionQueryService
+	verifier     *auth.Verifier
 )
 
 func init() {
@@ -35,15 +41,41 @@ func init() {
 	if err != nil {
 		panic("failed to connect to read database: " + err.Error())
 	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
+	}
 
-	projectionRepo = repository.NewInterventionProjectionRepository(db)
+	// The users that make up a navigator admin's team live in the write
+	// model.
+	writeDSN := os.Getenv("DATABASE_URL")
+	if writeDSN == "" {
+		writeDSN = "host=localhost user=postgres password=postgres dbname=write_model port=5432 sslmode=disable"
+	}
+	writeDB, err := gorm.Open(postgres.Open(writeDSN), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to write database: " + err.Error())
+	}
+	if err := writeDB.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
+	}
+	authorizer := authz.NewAuthorizer(authz.DefaultPolicy, repository.NewUserRepository(writeDB))
+	queryService = service.NewInterventionQueryService(repository.NewInterventionProjectionRepository(db), authorizer)
+
+	verifier, err = auth.NewVerifierFromEnv()
+	if err != nil {
+		panic("failed to configure token verification: " + err.Error())
+	}
 }
 
 func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	tenantID := request.Headers["X-Tenant-ID"]
-	if tenantID == "" {
-		tenantID = "default-tenant"
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 401,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
 	}
+	tenantID := principal.TenantID
 
 	interventionID := request.PathParameters["id"]
 	if interventionID == "" {
@@ -57,7 +89,7 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 		}, nil
 	}
 
-	intervention, err := projectionRepo.GetByID(ctx, interventionID, tenantID)
+	intervention, err := queryService.GetIntervention(ctx, tenantID, interventionID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return events.APIGatewayProxyResponse{
@@ -65,8 +97,12 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 				Body:       `{"error": "Intervention not found"}`,
 			}, nil
 		}
+		statusCode := 500
+		if errors.Is(err, authz.ErrForbidden) {
+			statusCode = 403
+		}
 		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
+			StatusCode: statusCode,
 			Body:       `{"error": "` + err.Error() + `"}`,
 		}, nil
 	}
@@ -90,5 +126,5 @@ func getEnv(key, defaultValue string) string {
 }
 
 func main() {
-	lambda.Start(HandleRequest)
+	lambda.Start(auth.RequireAPIGateway(verifier, HandleRequest))
 }
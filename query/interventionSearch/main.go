@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	appdb "github.com/lambda/internal/db"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/service"
+)
+
+var (
+	db           *gorm.DB
+	queryService *service.InterventionQueryService
+	verifier     *auth.Verifier
+)
+
+func init() {
+	dsn := os.Getenv("READ_DB_URL")
+	if dsn == "" {
+		host := getEnv("READ_DB_HOST", "localhost")
+		port := getEnv("READ_DB_PORT", "5433")
+		user := getEnv("READ_DB_USER", "postgres")
+		password := getEnv("READ_DB_PASSWORD", "postgres")
+		dbname := getEnv("READ_DB_NAME", "read_model")
+		sslmode := getEnv("READ_DB_SSLMODE", "disable")
+		dsn = "host=" + host + " port=" + port + " user=" + user + " password=" + password + " dbname=" + dbname + " sslmode=" + sslmode
+	}
+
+	var err error
+	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to read database: " + err.Error())
+	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
+	}
+
+	// The users that make up a navigator admin's team live in the write
+	// model.
+	writeDSN := os.Getenv("DATABASE_URL")
+	if writeDSN == "" {
+		writeDSN = "host=localhost user=postgres password=postgres dbname=write_model port=5432 sslmode=disable"
+	}
+	writeDB, err := gorm.Open(postgres.Open(writeDSN), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to write database: " + err.Error())
+	}
+	if err := writeDB.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
+	}
+	authorizer := authz.NewAuthorizer(authz.DefaultPolicy, repository.NewUserRepository(writeDB))
+	queryService = service.NewInterventionQueryService(repository.NewInterventionProjectionRepository(db), authorizer)
+
+	verifier, err = auth.NewVerifierFromEnv()
+	if err != nil {
+		panic("failed to configure token verification: " + err.Error())
+	}
+}
+
+func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 401,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+	tenantID := principal.TenantID
+
+	filter, err := parseFilter(ctx, request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+
+	text := request.QueryStringParameters["q"]
+	if strings.TrimSpace(text) == "" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Body:       `{"error": "q is required"}`,
+		}, nil
+	}
+
+	page := repository.PageRequest{After: request.QueryStringParameters["after"]}
+	if first := request.QueryStringParameters["first"]; first != "" {
+		n, err := strconv.Atoi(first)
+		if err != nil {
+			return events.APIGatewayProxyResponse{
+				StatusCode: 400,
+				Body:       `{"error": "first must be a number"}`,
+			}, nil
+		}
+		page.First = n
+	}
+
+	results, pageInfo, err := queryService.SearchInterventions(ctx, tenantID, text, filter, page)
+	if errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, repository.ErrInvalidFilter) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+	if errors.Is(err, authz.ErrForbidden) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 403,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+
+	total, err := queryService.CountSearch(ctx, tenantID, text, filter)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"results": results,
+		"total":   total,
+		"page_info": map[string]interface{}{
+			"end_cursor":    pageInfo.EndCursor,
+			"has_next_page": pageInfo.HasNextPage,
+		},
+	})
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(body),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}, nil
+}
+
+// parseFilter reads the same filters as the list endpoint from the query
+// string. List values are comma-separated, dates are RFC 3339, and
+// assigned_to=me means the caller.
+func parseFilter(ctx context.Context, request events.APIGatewayProxyRequest) (repository.InterventionFilter, error) {
+	params := request.QueryStringParameters
+	filter := repository.InterventionFilter{
+		PatientID:    params["patient_id"],
+		ScreeningIDs: splitList(params["screening_id"]),
+		CreatedByIDs: splitList(params["created_by"]),
+		AssignedTeam: params["assigned_team"],
+		AssignedTo:   params["assigned_to"],
+		Unassigned:   params["unassigned"] == "true",
+		Overdue:      params["overdue"] == "true",
+		ProblemsAny:  splitList(params["problems_any"]),
+		ProblemsAll:  splitList(params["problems_all"]),
+	}
+	for _, status := range splitList(params["status"]) {
+		filter.Statuses = append(filter.Statuses, domain.InterventionStatus(status))
+	}
+	for _, interventionType := range splitList(params["type"]) {
+		filter.Types = append(filter.Types, domain.InterventionType(interventionType))
+	}
+
+	if filter.AssignedTo == "me" {
+		principal, err := auth.PrincipalFrom(ctx)
+		if err != nil {
+			return filter, err
+		}
+		filter.AssignedTo = principal.UserID
+	}
+
+	for _, field := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"due_after", &filter.DueAfter},
+		{"due_before", &filter.DueBefore},
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+	} {
+		value := params[field.name]
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an RFC 3339 timestamp", field.name)
+		}
+		*field.target = &t
+	}
+	return filter, filter.Validate()
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func main() {
+	lambda.Start(auth.RequireAPIGateway(verifier, HandleRequest))
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/auth"
+	"github.com/lambda/internal/authz"
+	appdb "github.com/lambda/internal/db"
+	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/service"
+)
+
+var (
+	db           *gorm.DB
+	queryService *service.InterventionQueryService
+	verifier     *auth.Verifier
+)
+
+func init() {
+	dsn := os.Getenv("READ_DB_URL")
+	if dsn == "" {
+		host := getEnv("READ_DB_HOST", "localhost")
+		port := getEnv("READ_DB_PORT", "5433")
+		user := getEnv("READ_DB_USER", "postgres")
+		password := getEnv("READ_DB_PASSWORD", "postgres")
+		dbname := getEnv("READ_DB_NAME", "read_model")
+		sslmode := getEnv("READ_DB_SSLMODE", "disable")
+		dsn = "host=" + host + " port=" + port + " user=" + user + " password=" + password + " dbname=" + dbname + " sslmode=" + sslmode
+	}
+
+	var err error
+	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to read database: " + err.Error())
+	}
+	if err := db.Use(appdb.TenantIsolation{}); err != nil {
+		panic("failed to enable tenant isolation: " + err.Error())
+	}
+
+	queryService = service.NewInterventionQueryService(repository.NewInterventionProjectionRepository(db), authz.NewAuthorizer(authz.DefaultPolicy, nil))
+
+	verifier, err = auth.NewVerifierFromEnv()
+	if err != nil {
+		panic("failed to configure token verification: " + err.Error())
+	}
+}
+
+func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	principal, err := auth.PrincipalFrom(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 401,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+	tenantID := principal.TenantID
+
+	workload, err := queryService.GetWorkload(ctx, tenantID)
+	if errors.Is(err, authz.ErrForbidden) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 403,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Body:       `{"error": "` + err.Error() + `"}`,
+		}, nil
+	}
+
+	body, _ := json.Marshal(workload)
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(body),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func main() {
+	lambda.Start(auth.RequireAPIGateway(verifier, HandleRequest))
+}
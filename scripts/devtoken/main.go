@@ -0,0 +1,126 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/lambda/internal/auth"
+)
+
+// Mints Cognito-shaped ID tokens signed by a local key, and writes that
+// key's JWKS, so the services can verify tokens without a user pool.
+//
+//	go run ./scripts/devtoken -tenant default-tenant -role navigator_admin
+//
+// Start the services with COGNITO_ISSUER set to -issuer and
+// COGNITO_JWKS_FILE set to -jwks, then send the printed token as
+// "Authorization: Bearer <token>".
+func main() {
+	keyPath := flag.String("key", ".devtoken/key.pem", "RSA private key; created if missing")
+	jwksPath := flag.String("jwks", ".devtoken/jwks.json", "where to write the public JWKS")
+	issuer := flag.String("issuer", "http://localhost/devtoken", "iss claim")
+	clientID := flag.String("client", getEnv("COGNITO_CLIENT_ID", "local-client"), "aud claim")
+	tenantID := flag.String("tenant", "default-tenant", "custom:tenant_id claim")
+	role := flag.String("role", "patient_navigator", "custom:role claim")
+	sub := flag.String("sub", "", "sub claim; a random UUID by default")
+	email := flag.String("email", "", "email claim")
+	ttl := flag.Duration("ttl", time.Hour, "token lifetime")
+	flag.Parse()
+
+	key, err := loadOrCreateKey(*keyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	kid := keyID(&key.PublicKey)
+
+	jwks, err := json.MarshalIndent(auth.JWKS{Keys: []auth.JWK{auth.NewJWK(kid, &key.PublicKey)}}, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*jwksPath, jwks, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	if *sub == "" {
+		*sub = uuid.New().String()
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":              *issuer,
+		"aud":              *clientID,
+		"sub":              *sub,
+		"token_use":        "id",
+		"cognito:username": *sub,
+		"custom:tenant_id": *tenantID,
+		"custom:role":      *role,
+		"iat":              now.Unix(),
+		"exp":              now.Add(*ttl).Unix(),
+	}
+	if *email != "" {
+		claims["email"] = *email
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	absJWKS, _ := filepath.Abs(*jwksPath)
+	fmt.Fprintf(os.Stderr, "COGNITO_ISSUER=%s COGNITO_JWKS_FILE=%s COGNITO_CLIENT_ID=%s\n", *issuer, absJWKS, *clientID)
+	fmt.Println(signed)
+}
+
+func loadOrCreateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("%s is not a PEM file", path)
+		}
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	data = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, os.WriteFile(path, data, 0o600)
+}
+
+// keyID derives a stable key ID from the public key, so regenerating the
+// JWKS from the same key keeps existing tokens valid.
+func keyID(key *rsa.PublicKey) string {
+	sum := sha256.Sum256(key.N.Bytes())
+	return hex.EncodeToString(sum[:8])
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
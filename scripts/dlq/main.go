@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Inspects and repairs the intervention event dead letter queue.
+//
+//	go run ./scripts/dlq list                              # every message, decoded
+//	go run ./scripts/dlq summary                           # counts by failure reason and event type
+//	go run ./scripts/dlq show -id <message-id>             # one message's body and attributes
+//	go run ./scripts/dlq edit -id <message-id>             # edit the body in $EDITOR (or -body file)
+//	go run ./scripts/dlq drop -id <id1>,<id2>              # delete messages
+//	go run ./scripts/dlq redrive -type 'intervention.*'    # send messages back to the source queue
+//
+// drop and redrive select messages with -id, -type, -reason or -all and
+// accept -dry-run. The queue defaults to DLQ_QUEUE_URL; queue URLs on
+// amazonaws.com use the normal AWS credential chain, any other host is
+// treated as LocalStack. Messages are hidden from other consumers while a
+// command runs and released afterwards unless they were consumed.
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	queueURL := flags.String("queue", getEnv("DLQ_QUEUE_URL", "http://localhost:4566/000000000000/intervention-dlq.fifo"), "dead letter queue URL")
+	visibility := flags.Int("visibility", 120, "seconds scanned messages stay hidden while the command runs")
+	ids := flags.String("id", "", "comma-separated message IDs to select")
+	eventType := flags.String("type", "", "select messages whose event type matches this pattern (path.Match syntax)")
+	reason := flags.String("reason", "", "select messages whose failure reason contains this text")
+	all := flags.Bool("all", false, "select every message")
+	asJSON := flags.Bool("json", false, "list: print messages as JSON")
+	bodyFile := flags.String("body", "", "edit: read the new body from this file (- for stdin) instead of $EDITOR")
+	force := flags.Bool("force", false, "edit: keep a body that still does not decode")
+	sourceURL := flags.String("source", "", "redrive: queue to send messages to (default: the queue whose redrive policy targets the DLQ)")
+	dryRun := flags.Bool("dry-run", false, "drop, redrive: print the selected messages without changing anything")
+	flags.Parse(args)
+
+	sel := selector{ids: splitIDs(*ids), eventType: *eventType, reason: *reason, all: *all}
+	switch command {
+	case "list", "summary":
+	case "show", "edit":
+		if len(sel.ids) != 1 {
+			log.Fatal("select one message with -id")
+		}
+	case "drop", "redrive":
+		if sel.empty() {
+			log.Fatal("select messages with -id, -type, -reason or -all")
+		}
+	default:
+		usage()
+	}
+
+	if command == "edit" && *bodyFile == "" && *visibility < 900 {
+		// Leave time to edit by hand.
+		*visibility = 900
+	}
+
+	ctx := context.Background()
+	client, err := newSQSClient(ctx, *queueURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	queue := &deadLetterQueue{client: client, url: *queueURL, visibility: int32(*visibility)}
+
+	letters, err := queue.scan(ctx)
+	if err != nil {
+		log.Fatalf("failed to read %s: %v", *queueURL, err)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].SentAt.Before(letters[j].SentAt) })
+
+	var consumed map[string]bool
+	switch command {
+	case "list":
+		err = list(letters, sel, *asJSON)
+	case "summary":
+		summarize(letters)
+	case "show":
+		var letter *deadLetter
+		if letter, err = one(letters, sel); err == nil {
+			show(letter)
+		}
+	case "edit":
+		var letter *deadLetter
+		if letter, err = one(letters, sel); err == nil {
+			consumed, err = edit(ctx, queue, letter, *bodyFile, *force)
+		}
+	case "drop":
+		consumed, err = drop(ctx, queue, sel.apply(letters), *dryRun)
+	case "redrive":
+		consumed, err = redrive(ctx, queue, sel.apply(letters), *sourceURL, *dryRun)
+	}
+
+	var remaining []*deadLetter
+	for _, letter := range letters {
+		if !consumed[letter.MessageID] {
+			remaining = append(remaining, letter)
+		}
+	}
+	if releaseErr := queue.release(ctx, remaining); releaseErr != nil {
+		log.Printf("Failed to release messages, they reappear after %ds: %v", *visibility, releaseErr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: dlq <list|summary|show|edit|drop|redrive> [flags]")
+	os.Exit(2)
+}
+
+type selector struct {
+	ids       map[string]bool
+	eventType string
+	reason    string
+	all       bool
+}
+
+func (s selector) empty() bool {
+	return !s.all && len(s.ids) == 0 && s.eventType == "" && s.reason == ""
+}
+
+// apply returns the letters matching every given criterion.
+func (s selector) apply(letters []*deadLetter) []*deadLetter {
+	var selected []*deadLetter
+	for _, letter := range letters {
+		if len(s.ids) > 0 && !s.ids[letter.MessageID] {
+			continue
+		}
+		if s.eventType != "" {
+			if ok, _ := path.Match(s.eventType, letter.EventType); !ok {
+				continue
+			}
+		}
+		if s.reason != "" && !strings.Contains(letter.Reason, s.reason) {
+			continue
+		}
+		selected = append(selected, letter)
+	}
+	return selected
+}
+
+// one returns the single letter selected by -id.
+func one(letters []*deadLetter, s selector) (*deadLetter, error) {
+	selected := s.apply(letters)
+	if len(selected) == 0 {
+		return nil, errors.New("message not found or not visible; it may be in flight elsewhere")
+	}
+	return selected[0], nil
+}
+
+func list(letters []*deadLetter, s selector, asJSON bool) error {
+	if !s.empty() {
+		letters = s.apply(letters)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if letters == nil {
+			letters = []*deadLetter{}
+		}
+		return encoder.Encode(letters)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MESSAGE ID\tSENT\tRECEIVES\tEVENT TYPE\tAGGREGATE\tREASON")
+	for _, letter := range letters {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", letter.MessageID, letter.SentAt.Format("2006-01-02 15:04:05"),
+			letter.ReceiveCount, letter.EventType, letter.AggregateID, letter.Reason)
+	}
+	fmt.Fprintf(w, "\n%d messages\n", len(letters))
+	return w.Flush()
+}
+
+func summarize(letters []*deadLetter) {
+	type group struct{ reason, eventType string }
+	counts := map[group]int{}
+	for _, letter := range letters {
+		counts[group{letter.Reason, letter.EventType}]++
+	}
+
+	groups := make([]group, 0, len(counts))
+	for g := range counts {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if counts[groups[i]] != counts[groups[j]] {
+			return counts[groups[i]] > counts[groups[j]]
+		}
+		return groups[i].reason+groups[i].eventType < groups[j].reason+groups[j].eventType
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNT\tEVENT TYPE\tREASON")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", counts[g], g.eventType, g.reason)
+	}
+	fmt.Fprintf(w, "\n%d messages\n", len(letters))
+	w.Flush()
+}
+
+func show(letter *deadLetter) {
+	fmt.Printf("Message:   %s\n", letter.MessageID)
+	fmt.Printf("Sent:      %s (%d receives)\n", letter.SentAt.Format("2006-01-02 15:04:05"), letter.ReceiveCount)
+	fmt.Printf("Group:     %s\n", letter.GroupID)
+	fmt.Printf("Reason:    %s\n", letter.Reason)
+	if letter.DecodeError != "" {
+		fmt.Printf("Decoding:  %s\n", letter.DecodeError)
+	}
+
+	names := make([]string, 0, len(letter.attributes))
+	for name := range letter.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %s = %s\n", name, derefString(letter.attributes[name].StringValue))
+	}
+
+	fmt.Println()
+	fmt.Println(prettyJSON(letter.Body))
+}
+
+// edit replaces a message with an edited copy. The edited body must decode
+// as a valid event unless force is set.
+func edit(ctx context.Context, queue *deadLetterQueue, letter *deadLetter, bodyFile string, force bool) (map[string]bool, error) {
+	body, err := editedBody(letter, bodyFile)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(body) == strings.TrimSpace(prettyJSON(letter.Body)) || strings.TrimSpace(body) == strings.TrimSpace(letter.Body) {
+		log.Println("Body unchanged")
+		return nil, nil
+	}
+
+	compact := &bytes.Buffer{}
+	if err := json.Compact(compact, []byte(body)); err != nil {
+		return nil, fmt.Errorf("edited body is not JSON: %w", err)
+	}
+	edited := &deadLetter{MessageID: letter.MessageID, Body: compact.String()}
+	edited.decode()
+	if edited.DecodeError != "" && !force {
+		return nil, fmt.Errorf("edited body still does not decode (use -force to keep it): %s", edited.DecodeError)
+	}
+
+	newID, err := queue.replace(ctx, letter, compact.String())
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Replaced message %s with %s", letter.MessageID, newID)
+	return map[string]bool{letter.MessageID: true}, nil
+}
+
+func editedBody(letter *deadLetter, bodyFile string) (string, error) {
+	switch bodyFile {
+	case "-":
+		body, err := io.ReadAll(os.Stdin)
+		return string(body), err
+	case "":
+	default:
+		body, err := os.ReadFile(bodyFile)
+		return string(body), err
+	}
+
+	file, err := os.CreateTemp("", "dlq-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(prettyJSON(letter.Body) + "\n"); err != nil {
+		return "", err
+	}
+	file.Close()
+
+	cmd := exec.Command(getEnv("EDITOR", "vi"), file.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+	body, err := os.ReadFile(file.Name())
+	return string(body), err
+}
+
+func drop(ctx context.Context, queue *deadLetterQueue, letters []*deadLetter, dryRun bool) (map[string]bool, error) {
+	consumed := map[string]bool{}
+	for _, letter := range letters {
+		if dryRun {
+			log.Printf("Would drop %s (%s, %s)", letter.MessageID, letter.EventType, letter.Reason)
+			continue
+		}
+		if err := queue.delete(ctx, letter); err != nil {
+			return consumed, fmt.Errorf("failed to drop message %s: %w", letter.MessageID, err)
+		}
+		consumed[letter.MessageID] = true
+		log.Printf("Dropped %s (%s)", letter.MessageID, letter.EventType)
+	}
+	log.Printf("%d of %d selected messages dropped", len(consumed), len(letters))
+	return consumed, nil
+}
+
+// redrive sends the selected messages back in the order they were
+// dead-lettered, so events of one aggregate keep their relative order.
+func redrive(ctx context.Context, queue *deadLetterQueue, letters []*deadLetter, sourceURL string, dryRun bool) (map[string]bool, error) {
+	if sourceURL == "" {
+		var err error
+		if sourceURL, err = queue.sourceQueue(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	consumed := map[string]bool{}
+	for _, letter := range letters {
+		if dryRun {
+			log.Printf("Would redrive %s (%s) to %s", letter.MessageID, letter.EventType, sourceURL)
+			continue
+		}
+		if letter.DecodeError != "" {
+			log.Printf("Warning: message %s still does not decode: %s", letter.MessageID, letter.DecodeError)
+		}
+		if err := queue.redrive(ctx, sourceURL, letter); err != nil {
+			return consumed, err
+		}
+		consumed[letter.MessageID] = true
+		log.Printf("Redrove %s (%s)", letter.MessageID, letter.EventType)
+	}
+	log.Printf("%d of %d selected messages redriven to %s", len(consumed), len(letters), sourceURL)
+	return consumed, nil
+}
+
+func splitIDs(value string) map[string]bool {
+	ids := map[string]bool{}
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+func prettyJSON(body string) string {
+	pretty := &bytes.Buffer{}
+	if err := json.Indent(pretty, []byte(body), "", "  "); err != nil {
+		return body
+	}
+	return pretty.String()
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+
+	"github.com/lambda/internal/events"
+	"github.com/lambda/internal/projection"
+)
+
+// Messages that SQS moved here after maxReceiveCount failed deliveries
+// carry no failure attributes of their own.
+const maxReceivesReason = "exceeded maxReceiveCount"
+
+// deadLetter is one DLQ message, decoded as far as it will go.
+type deadLetter struct {
+	MessageID    string    `json:"message_id"`
+	GroupID      string    `json:"group_id,omitempty"`
+	ReceiveCount int       `json:"receive_count"`
+	SentAt       time.Time `json:"sent_at"`
+	Reason       string    `json:"reason"`
+	EventID      string    `json:"event_id,omitempty"`
+	EventType    string    `json:"event_type"`
+	AggregateID  string    `json:"aggregate_id,omitempty"`
+	TenantID     string    `json:"tenant_id,omitempty"`
+	DecodeError  string    `json:"decode_error,omitempty"`
+	Body         string    `json:"body"`
+
+	attributes    map[string]types.MessageAttributeValue
+	receiptHandle string
+	event         *events.DomainEvent
+}
+
+func newDeadLetter(message types.Message) *deadLetter {
+	letter := &deadLetter{
+		MessageID:     aws.ToString(message.MessageId),
+		GroupID:       message.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)],
+		Body:          aws.ToString(message.Body),
+		Reason:        maxReceivesReason,
+		EventType:     "unknown",
+		attributes:    message.MessageAttributes,
+		receiptHandle: aws.ToString(message.ReceiptHandle),
+	}
+	letter.ReceiveCount, _ = strconv.Atoi(message.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	if sent, err := strconv.ParseInt(message.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+		letter.SentAt = time.UnixMilli(sent).UTC()
+	}
+	for _, name := range []string{"failure_detail", "failure_reason"} {
+		if value := aws.ToString(message.MessageAttributes[name].StringValue); value != "" {
+			letter.Reason = value
+			break
+		}
+	}
+
+	letter.decode()
+	return letter
+}
+
+// decode reads the body as a domain event and records why it cannot be
+// applied, if it still cannot.
+func (l *deadLetter) decode() {
+	event, err := projection.DecodeEvent(l.MessageID, []byte(l.Body))
+	if err != nil {
+		l.DecodeError = err.Error()
+		return
+	}
+	l.event = event
+	l.EventID = event.EventID
+	l.EventType = string(event.EventType)
+	l.AggregateID = event.AggregateID
+	l.TenantID = event.TenantID
+	if _, err := events.DefaultRegistry.Decode(event); err != nil {
+		l.DecodeError = err.Error()
+	}
+}
+
+type deadLetterQueue struct {
+	client     *sqs.Client
+	url        string
+	visibility int32
+}
+
+// newSQSClient talks to real SQS for amazonaws.com queue URLs and to the
+// queue URL's host otherwise, which covers LocalStack.
+func newSQSClient(ctx context.Context, queueURL string) (*sqs.Client, error) {
+	parsed, err := url.Parse(queueURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid queue URL %q", queueURL)
+	}
+
+	if strings.HasSuffix(parsed.Hostname(), ".amazonaws.com") {
+		var options []func(*config.LoadOptions) error
+		if parts := strings.Split(parsed.Hostname(), "."); len(parts) == 4 && parts[0] == "sqs" {
+			options = append(options, config.WithRegion(parts[1]))
+		}
+		cfg, err := config.LoadDefaultConfig(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		return sqs.NewFromConfig(cfg), nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(getEnv("AWS_REGION", "us-east-1")),
+		config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		})),
+	)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := parsed.Scheme + "://" + parsed.Host
+	return sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+	}), nil
+}
+
+// scan receives every message currently visible in the queue and keeps
+// them hidden for the queue's visibility timeout, so the command can act on
+// them. On a FIFO queue only the first messages of each message group are
+// returned until those are dealt with.
+func (q *deadLetterQueue) scan(ctx context.Context) ([]*deadLetter, error) {
+	var letters []*deadLetter
+	seen := map[string]bool{}
+	for empty := 0; empty < 2; {
+		resp, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:                    aws.String(q.url),
+			MaxNumberOfMessages:         10,
+			WaitTimeSeconds:             1,
+			VisibilityTimeout:           q.visibility,
+			MessageAttributeNames:       []string{"All"},
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+		})
+		if err != nil {
+			return letters, err
+		}
+		if len(resp.Messages) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+		for _, message := range resp.Messages {
+			if id := aws.ToString(message.MessageId); !seen[id] {
+				seen[id] = true
+				letters = append(letters, newDeadLetter(message))
+			}
+		}
+	}
+	return letters, nil
+}
+
+// release makes messages the command did not consume visible again.
+func (q *deadLetterQueue) release(ctx context.Context, letters []*deadLetter) error {
+	for start := 0; start < len(letters); start += 10 {
+		end := min(start+10, len(letters))
+		entries := make([]types.ChangeMessageVisibilityBatchRequestEntry, 0, end-start)
+		for i, letter := range letters[start:end] {
+			entries = append(entries, types.ChangeMessageVisibilityBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(i)),
+				ReceiptHandle:     aws.String(letter.receiptHandle),
+				VisibilityTimeout: 0,
+			})
+		}
+		resp, err := q.client.ChangeMessageVisibilityBatch(ctx, &sqs.ChangeMessageVisibilityBatchInput{
+			QueueUrl: aws.String(q.url),
+			Entries:  entries,
+		})
+		if err != nil {
+			return err
+		}
+		if len(resp.Failed) > 0 {
+			return fmt.Errorf("failed to release %d messages: %s", len(resp.Failed), aws.ToString(resp.Failed[0].Message))
+		}
+	}
+	return nil
+}
+
+func (q *deadLetterQueue) delete(ctx context.Context, letter *deadLetter) error {
+	_, err := q.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(q.url),
+		ReceiptHandle: aws.String(letter.receiptHandle),
+	})
+	return err
+}
+
+// replace swaps a message for one with a new body, keeping its attributes
+// and message group and marking it edited.
+func (q *deadLetterQueue) replace(ctx context.Context, letter *deadLetter, body string) (string, error) {
+	attributes := map[string]types.MessageAttributeValue{}
+	for name, value := range letter.attributes {
+		attributes[name] = value
+	}
+	attributes["edited_from"] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(letter.MessageID)}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(q.url),
+		MessageBody:       aws.String(body),
+		MessageAttributes: attributes,
+	}
+	if isFIFOQueue(q.url) {
+		input.MessageGroupId = aws.String(groupOr(letter.GroupID, letter.MessageID))
+		input.MessageDeduplicationId = aws.String(uuid.New().String())
+	}
+
+	resp, err := q.client.SendMessage(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(resp.MessageId), q.delete(ctx, letter)
+}
+
+// sourceQueue finds the queue whose redrive policy points at this one.
+func (q *deadLetterQueue) sourceQueue(ctx context.Context) (string, error) {
+	resp, err := q.client.ListDeadLetterSourceQueues(ctx, &sqs.ListDeadLetterSourceQueuesInput{QueueUrl: aws.String(q.url)})
+	if err != nil {
+		return "", err
+	}
+	switch len(resp.QueueUrls) {
+	case 0:
+		return "", errors.New("no queue uses this DLQ in its redrive policy; pass -source")
+	case 1:
+		return resp.QueueUrls[0], nil
+	default:
+		return "", fmt.Errorf("several queues use this DLQ (%s); pass -source", strings.Join(resp.QueueUrls, ", "))
+	}
+}
+
+// redrive sends the message back to the source queue without the failure
+// attributes, then deletes it here. On a FIFO source queue the event's
+// aggregate is the message group again, as the forwarder sends it.
+func (q *deadLetterQueue) redrive(ctx context.Context, sourceURL string, letter *deadLetter) error {
+	attributes := map[string]types.MessageAttributeValue{}
+	for name, value := range letter.attributes {
+		if !strings.HasPrefix(name, "failure_") && name != "edited_from" {
+			attributes[name] = value
+		}
+	}
+	attributes["redriven_from"] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(letter.MessageID)}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(sourceURL),
+		MessageBody:       aws.String(letter.Body),
+		MessageAttributes: attributes,
+	}
+	if isFIFOQueue(sourceURL) {
+		groupID := letter.GroupID
+		if letter.event != nil {
+			groupID = letter.event.MessageGroupID()
+		}
+		input.MessageGroupId = aws.String(groupOr(groupID, letter.MessageID))
+		// The event ID may still be inside the source queue's
+		// deduplication window, so deduplicate on this DLQ message instead.
+		input.MessageDeduplicationId = aws.String(letter.MessageID)
+	}
+
+	if _, err := q.client.SendMessage(ctx, input); err != nil {
+		return fmt.Errorf("failed to send message %s to %s: %w", letter.MessageID, sourceURL, err)
+	}
+	return q.delete(ctx, letter)
+}
+
+func isFIFOQueue(queueURL string) bool {
+	return strings.HasSuffix(queueURL, ".fifo")
+}
+
+func groupOr(groupID, fallback string) string {
+	if groupID != "" {
+		return groupID
+	}
+	return fallback
+}
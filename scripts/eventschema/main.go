@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/lambda/internal/events"
+)
+
+// Writes one JSON Schema document per registered event type, named
+// <event_type>.v<schema_version>.schema.json.
+func main() {
+	outDir := flag.String("out", "schemas/events", "directory to write the schema documents to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	eventTypes := events.DefaultRegistry.EventTypes()
+	sort.Slice(eventTypes, func(i, j int) bool { return eventTypes[i] < eventTypes[j] })
+
+	for _, eventType := range eventTypes {
+		schema, err := events.DefaultRegistry.JSONSchema(eventType)
+		if err != nil {
+			log.Fatal(err)
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		path := filepath.Join(*outDir, schema["$id"].(string))
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Wrote", path)
+	}
+}
@@ -23,10 +23,19 @@ aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name screening
 aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-dlq
 aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-events --attributes '{ "RedrivePolicy": "{\"deadLetterTargetArn\":\"arn:aws:sqs:us-east-1:000000000000:intervention-dlq\",\"maxReceiveCount\":\"5\"}" }'
 
+aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-dlq.fifo --attributes '{ "FifoQueue": "true" }'
+aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-events.fifo --attributes '{ "FifoQueue": "true", "RedrivePolicy": "{\"deadLetterTargetArn\":\"arn:aws:sqs:us-east-1:000000000000:intervention-dlq.fifo\",\"maxReceiveCount\":\"5\"}" }'
+
+aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-notifications-dlq
+aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-notifications --attributes '{ "RedrivePolicy": "{\"deadLetterTargetArn\":\"arn:aws:sqs:us-east-1:000000000000:intervention-notifications-dlq\",\"maxReceiveCount\":\"5\"}" }'
+
+aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-audit-dlq
+aws --endpoint-url=http://localhost:4566 sqs create-queue --queue-name intervention-audit --attributes '{ "RedrivePolicy": "{\"deadLetterTargetArn\":\"arn:aws:sqs:us-east-1:000000000000:intervention-audit-dlq\",\"maxReceiveCount\":\"5\"}" }'
+
 echo "Creating S3 bucket..."
 aws --endpoint-url=http://localhost:4566 s3 mb s3://audit-archive
 
 echo "LocalStack initialized successfully!"
 echo "Kinesis streams: cdc-stream, intervention-events"
-echo "SQS queues: intervention-events, patient, screening"
+echo "SQS queues: intervention-events, intervention-events.fifo, intervention-notifications, intervention-audit, patient, screening"
 
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id TEXT PRIMARY KEY,
+    aggregate_id TEXT NOT NULL,
+    tenant_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_status_created_at ON outbox(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate_id ON outbox(aggregate_id);
+-- The relay only picks a message when no earlier message of its aggregate
+-- is unpublished; this index answers that check.
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished_aggregate ON outbox(aggregate_id, created_at, id)
+    WHERE status IN ('pending', 'failed', 'dead');
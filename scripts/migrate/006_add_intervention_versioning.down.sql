@@ -0,0 +1,3 @@
+ALTER TABLE interventions_projection DROP COLUMN IF EXISTS last_sequence;
+ALTER TABLE interventions_projection DROP COLUMN IF EXISTS last_event_id;
+ALTER TABLE interventions DROP COLUMN IF EXISTS version;
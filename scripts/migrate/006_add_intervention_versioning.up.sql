@@ -0,0 +1,4 @@
+ALTER TABLE interventions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE interventions_projection ADD COLUMN IF NOT EXISTS last_event_id TEXT;
+ALTER TABLE interventions_projection ADD COLUMN IF NOT EXISTS last_sequence BIGINT NOT NULL DEFAULT 0;
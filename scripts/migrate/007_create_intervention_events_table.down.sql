@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS intervention_events;
+DROP FUNCTION IF EXISTS reject_intervention_event_change();
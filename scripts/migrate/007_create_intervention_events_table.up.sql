@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS intervention_events (
+    position BIGSERIAL UNIQUE,
+    event_id TEXT PRIMARY KEY,
+    intervention_id TEXT NOT NULL,
+    tenant_id TEXT NOT NULL,
+    sequence BIGINT NOT NULL,
+    event_type TEXT NOT NULL,
+    schema_version INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (intervention_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_intervention_events_tenant_occurred_at ON intervention_events(tenant_id, occurred_at);
+
+-- The event store is append-only.
+CREATE OR REPLACE FUNCTION reject_intervention_event_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'intervention_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS intervention_events_append_only ON intervention_events;
+CREATE TRIGGER intervention_events_append_only
+    BEFORE UPDATE OR DELETE ON intervention_events
+    FOR EACH ROW EXECUTE FUNCTION reject_intervention_event_change();
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_interventions_tenant_created_at_id;
+DROP INDEX IF EXISTS idx_interventions_tenant_updated_at_id;
+DROP INDEX IF EXISTS idx_interventions_tenant_due_at_id;
+DROP INDEX IF EXISTS idx_interventions_tenant_priority_id;
+DROP INDEX IF EXISTS idx_interventions_tenant_status_id;
+DROP INDEX IF EXISTS idx_interventions_projection_tenant_created_at_id;
+DROP INDEX IF EXISTS idx_interventions_projection_tenant_updated_at_id;
+DROP INDEX IF EXISTS idx_interventions_projection_tenant_due_at_id;
+DROP INDEX IF EXISTS idx_interventions_projection_tenant_priority_id;
+DROP INDEX IF EXISTS idx_interventions_projection_tenant_status_id;
@@ -0,0 +1,13 @@
+-- Keyset pagination orders by (sort key, id) within a tenant. The expressions
+-- must match sortKeys in internal/repository/pagination.go.
+CREATE INDEX IF NOT EXISTS idx_interventions_tenant_created_at_id ON interventions(tenant_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_interventions_tenant_updated_at_id ON interventions(tenant_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_interventions_tenant_due_at_id ON interventions(tenant_id, (COALESCE(due_at, 'infinity'::timestamptz)), id);
+CREATE INDEX IF NOT EXISTS idx_interventions_tenant_priority_id ON interventions(tenant_id, (CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END), id);
+CREATE INDEX IF NOT EXISTS idx_interventions_tenant_status_id ON interventions(tenant_id, (CASE status WHEN 'pending' THEN 1 WHEN 'in_progress' THEN 2 WHEN 'completed' THEN 3 WHEN 'cancelled' THEN 4 ELSE 5 END), id);
+
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_tenant_created_at_id ON interventions_projection(tenant_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_tenant_updated_at_id ON interventions_projection(tenant_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_tenant_due_at_id ON interventions_projection(tenant_id, (COALESCE(due_at, 'infinity'::timestamptz)), id);
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_tenant_priority_id ON interventions_projection(tenant_id, (CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END), id);
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_tenant_status_id ON interventions_projection(tenant_id, (CASE status WHEN 'pending' THEN 1 WHEN 'in_progress' THEN 2 WHEN 'completed' THEN 3 WHEN 'cancelled' THEN 4 ELSE 5 END), id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_interventions_problems;
+DROP INDEX IF EXISTS idx_interventions_tenant_due_at;
+DROP INDEX IF EXISTS idx_interventions_projection_problems;
+DROP INDEX IF EXISTS idx_interventions_projection_tenant_due_at;
@@ -0,0 +1,6 @@
+-- Support the problems any/all filters (&& and @>) and due date ranges.
+CREATE INDEX IF NOT EXISTS idx_interventions_problems ON interventions USING GIN (problems);
+CREATE INDEX IF NOT EXISTS idx_interventions_tenant_due_at ON interventions(tenant_id, due_at);
+
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_problems ON interventions_projection USING GIN (problems);
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_tenant_due_at ON interventions_projection(tenant_id, due_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_interventions_projection_search;
+ALTER TABLE interventions_projection DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,11 @@
+-- Full-text search over interventions. The projection worker keeps
+-- search_vector up to date; the expression must match SearchVector in
+-- internal/projection/search.go.
+ALTER TABLE interventions_projection ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+UPDATE interventions_projection SET search_vector =
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(array_to_string(problems, ' '), '') || ' ' || coalesce(array_to_string(referral_reasons, ' '), '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '') || ' ' || coalesce(notes, '')), 'C');
+
+CREATE INDEX IF NOT EXISTS idx_interventions_projection_search ON interventions_projection USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS intervention_workload_counts;
+DROP TABLE IF EXISTS intervention_referral_counts;
+DROP TABLE IF EXISTS intervention_barrier_counts;
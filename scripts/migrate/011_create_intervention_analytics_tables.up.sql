@@ -0,0 +1,53 @@
+-- Pre-aggregated dashboards maintained by the projection worker; see
+-- internal/projection/analytics.go. Each row counts interventions, and
+-- rows whose count reaches zero are deleted.
+CREATE TABLE IF NOT EXISTS intervention_barrier_counts (
+    tenant_id TEXT NOT NULL,
+    month DATE NOT NULL,
+    type TEXT NOT NULL,
+    problem TEXT NOT NULL,
+    intervention_count INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, month, type, problem)
+);
+
+CREATE TABLE IF NOT EXISTS intervention_referral_counts (
+    tenant_id TEXT NOT NULL,
+    month DATE NOT NULL,
+    type TEXT NOT NULL,
+    referral_reason TEXT NOT NULL,
+    intervention_count INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, month, type, referral_reason)
+);
+
+-- Unassigned interventions have an empty assignee and team; those without
+-- a due date are due on 'infinity'.
+CREATE TABLE IF NOT EXISTS intervention_workload_counts (
+    tenant_id TEXT NOT NULL,
+    assigned_to TEXT NOT NULL,
+    assigned_team TEXT NOT NULL,
+    status TEXT NOT NULL,
+    due_on DATE NOT NULL,
+    intervention_count INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, assigned_to, assigned_team, status, due_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_intervention_workload_counts_team ON intervention_workload_counts(tenant_id, assigned_team);
+
+INSERT INTO intervention_barrier_counts (tenant_id, month, type, problem, intervention_count)
+SELECT tenant_id, date_trunc('month', created_at AT TIME ZONE 'UTC')::date, type, problem, count(DISTINCT id)
+FROM interventions_projection, unnest(problems) AS problem
+GROUP BY 1, 2, 3, 4
+ON CONFLICT DO NOTHING;
+
+INSERT INTO intervention_referral_counts (tenant_id, month, type, referral_reason, intervention_count)
+SELECT tenant_id, date_trunc('month', created_at AT TIME ZONE 'UTC')::date, type, reason, count(DISTINCT id)
+FROM interventions_projection, unnest(referral_reasons) AS reason
+GROUP BY 1, 2, 3, 4
+ON CONFLICT DO NOTHING;
+
+INSERT INTO intervention_workload_counts (tenant_id, assigned_to, assigned_team, status, due_on, intervention_count)
+SELECT tenant_id, COALESCE(assigned_to, ''), COALESCE(assigned_team, ''), status,
+    COALESCE((due_at AT TIME ZONE 'UTC')::date, 'infinity'::date), count(*)
+FROM interventions_projection
+GROUP BY 1, 2, 3, 4, 5
+ON CONFLICT DO NOTHING;
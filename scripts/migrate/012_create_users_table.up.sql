@@ -0,0 +1,16 @@
+-- Staff and patient accounts mirrored from Cognito. Intervention
+-- authorization reads navigator_admin_id to find an admin's navigators.
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    email TEXT NOT NULL UNIQUE,
+    username TEXT NOT NULL UNIQUE,
+    phone_number TEXT NOT NULL DEFAULT '',
+    role TEXT NOT NULL,
+    navigator_admin_id UUID,
+    is_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_tenant_navigator_admin ON users(tenant_id, navigator_admin_id) WHERE NOT is_deleted;
@@ -0,0 +1,30 @@
+DROP POLICY IF EXISTS tenant_isolation ON intervention_workload_counts;
+ALTER TABLE intervention_workload_counts DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON intervention_referral_counts;
+ALTER TABLE intervention_referral_counts DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON intervention_barrier_counts;
+ALTER TABLE intervention_barrier_counts DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON interventions_projection;
+ALTER TABLE interventions_projection DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON users;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON outbox;
+ALTER TABLE outbox DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON intervention_events;
+ALTER TABLE intervention_events DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON interventions;
+ALTER TABLE interventions DISABLE ROW LEVEL SECURITY;
+
+-- tenant_app is shared by every database of the cluster, so it is left in
+-- place; only this database's grants are removed.
+REVOKE ALL ON
+    interventions,
+    intervention_events,
+    outbox,
+    users,
+    interventions_projection,
+    intervention_barrier_counts,
+    intervention_referral_counts,
+    intervention_workload_counts
+FROM tenant_app;
+REVOKE ALL ON SEQUENCE intervention_events_position_seq FROM tenant_app;
@@ -0,0 +1,69 @@
+-- Row-level security for request traffic. internal/db runs every statement
+-- made for an authenticated request as tenant_app with app.tenant_id set to
+-- the caller's tenant, so a query that forgets its tenant_id filter still
+-- only sees and writes that tenant's rows. Workers, scripts and migrations
+-- keep the connecting role, which owns the tables and is not subject to
+-- these policies. The patients tables carry no tenant yet and are left out.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'tenant_app') THEN
+        CREATE ROLE tenant_app NOLOGIN;
+    END IF;
+END
+$$;
+
+-- The application's login role switches to tenant_app, so it must be a
+-- member; run migrations as that role or grant it separately.
+GRANT tenant_app TO CURRENT_USER;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON
+    interventions,
+    intervention_events,
+    outbox,
+    users,
+    interventions_projection,
+    intervention_barrier_counts,
+    intervention_referral_counts,
+    intervention_workload_counts
+TO tenant_app;
+GRANT USAGE ON SEQUENCE intervention_events_position_seq TO tenant_app;
+
+ALTER TABLE interventions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON interventions TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE intervention_events ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON intervention_events TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON users TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE interventions_projection ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON interventions_projection TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE intervention_barrier_counts ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON intervention_barrier_counts TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE intervention_referral_counts ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON intervention_referral_counts TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE intervention_workload_counts ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON intervention_workload_counts TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
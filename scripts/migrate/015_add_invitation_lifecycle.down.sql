@@ -0,0 +1,12 @@
+DROP POLICY IF EXISTS tenant_isolation ON invitations;
+ALTER TABLE invitations DISABLE ROW LEVEL SECURITY;
+REVOKE ALL ON invitations FROM tenant_app;
+
+DROP INDEX IF EXISTS idx_invitations_tenant_status;
+
+ALTER TABLE invitations DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE invitations DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE invitations DROP COLUMN IF EXISTS accepted_at;
+ALTER TABLE invitations DROP COLUMN IF EXISTS accepted_by;
+ALTER TABLE invitations DROP COLUMN IF EXISTS status;
+ALTER TABLE invitations DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,24 @@
+-- Invitations move from pending to accepted, revoked or expired. tenant_id
+-- is the inviter's tenant, which the accepted user joins; accepted_by is
+-- the Cognito sub of the user who registered with the invitation.
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS accepted_by UUID;
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS accepted_at TIMESTAMPTZ;
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE invitations SET status = 'accepted' WHERE is_used;
+UPDATE invitations SET status = 'expired' WHERE status = 'pending' AND expires_at < NOW();
+
+CREATE INDEX IF NOT EXISTS idx_invitations_tenant_status ON invitations(tenant_id, status, invited_by);
+
+-- Invitations are managed by authenticated navigator admins, so they get
+-- the same tenant isolation as the tables of migration 013. Accepting an
+-- invitation is anonymous and runs as the connecting role.
+GRANT SELECT, INSERT, UPDATE, DELETE ON invitations TO tenant_app;
+
+ALTER TABLE invitations ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON invitations TO tenant_app
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
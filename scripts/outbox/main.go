@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/repository"
+)
+
+// Inspects and unblocks dead outbox messages. A dead message used up its
+// publish attempts and holds back every later message of its aggregate.
+//
+//	go run ./scripts/outbox dead                       # list dead messages
+//	go run ./scripts/outbox requeue -id <id1>,<id2>    # retry them with fresh attempts
+//	go run ./scripts/outbox discard -id <id1>,<id2>    # drop them and release their aggregates
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	ids := flags.String("id", "", "comma-separated message IDs")
+	dsn := flags.String("dsn", getEnv("WRITE_DB_URL", "host=localhost user=postgres password=postgres dbname=write_model port=5434 sslmode=disable"), "write model DSN")
+	flags.Parse(args)
+
+	ctx := context.Background()
+	repo := repository.NewOutboxRepository(openDB(*dsn))
+
+	switch command {
+	case "dead":
+		messages, err := repo.ListDead(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tAGGREGATE\tEVENT TYPE\tATTEMPTS\tCREATED\tLAST ERROR")
+		for _, m := range messages {
+			lastError := ""
+			if m.LastError != nil {
+				lastError = *m.LastError
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", m.ID, m.AggregateID, m.EventType, m.Attempts, m.CreatedAt.Format("2006-01-02 15:04:05"), lastError)
+		}
+		w.Flush()
+	case "requeue", "discard":
+		if *ids == "" {
+			log.Fatal("select messages with -id")
+		}
+		change := repo.Requeue
+		if command == "discard" {
+			change = repo.Discard
+		}
+		for _, id := range strings.Split(*ids, ",") {
+			if err := change(ctx, strings.TrimSpace(id)); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("%s: %s\n", command, id)
+		}
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: outbox dead | requeue -id <ids> | discard -id <ids>")
+	os.Exit(2)
+}
+
+func openDB(dsn string) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	return db
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/aggregate"
+	"github.com/lambda/internal/domain"
+	"github.com/lambda/internal/projection"
+	"github.com/lambda/internal/repository"
+)
+
+// Rebuilds interventions_projection by replaying events through the same
+// projector the intervention event worker uses.
+//
+//	go run ./scripts/rebuildprojection                     # rebuild side by side from the event store, then swap
+//	go run ./scripts/rebuildprojection -source snapshot    # replay the current write-model rows instead
+//	go run ./scripts/rebuildprojection -mode truncate      # empty the live table and replay into it
+//	go run ./scripts/rebuildprojection -dry-run            # rebuild side by side and print the diff only
+//	go run ./scripts/rebuildprojection -analytics-only     # recompute the analytics tables from the live projection
+//
+// Every rebuild finishes by recomputing the analytics tables.
+//
+// Interventions written before the event store existed have no history
+// there; rebuild from a snapshot to include them.
+func main() {
+	source := flag.String("source", "events", "events (intervention_events) or snapshot (current interventions rows)")
+	mode := flag.String("mode", "swap", "swap: build a new table and swap it in; truncate: empty the live table and replay into it")
+	dryRun := flag.Bool("dry-run", false, "rebuild side by side and print the differences from the live table without changing it")
+	analyticsOnly := flag.Bool("analytics-only", false, "only recompute the analytics tables from the live projection")
+	tenantID := flag.String("tenant", "", "only rebuild this tenant's interventions")
+	batchSize := flag.Int("batch", 500, "events or interventions read per query")
+	diffLimit := flag.Int("diff-limit", 50, "maximum number of differing interventions to print in dry-run mode")
+	writeDSN := flag.String("write-dsn", getEnv("WRITE_DB_URL", "host=localhost user=postgres password=postgres dbname=write_model port=5434 sslmode=disable"), "write model DSN")
+	readDSN := flag.String("read-dsn", getEnv("READ_DB_URL", "host=localhost user=postgres password=postgres dbname=read_model port=5433 sslmode=disable"), "read model DSN")
+	flag.Parse()
+
+	if *source != "events" && *source != "snapshot" {
+		log.Fatalf("unknown source %q", *source)
+	}
+	if *mode != "swap" && *mode != "truncate" {
+		log.Fatalf("unknown mode %q", *mode)
+	}
+
+	ctx := context.Background()
+	writeDB := openDB(*writeDSN)
+	readDB := openDB(*readDSN)
+
+	r := &rebuilder{
+		writeDB:    writeDB,
+		readDB:     readDB,
+		eventStore: repository.NewEventStoreRepository(writeDB),
+		source:     *source,
+		tenantID:   *tenantID,
+		batchSize:  *batchSize,
+	}
+
+	if *analyticsOnly {
+		if err := projection.RebuildAnalytics(ctx, readDB, *tenantID); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Analytics rebuilt")
+		return
+	}
+
+	live := projection.ProjectionTable
+	if *dryRun {
+		target := live + "_rebuild"
+		if err := r.createSideTable(live, target, false); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := r.replay(ctx, projection.NewInterventionProjector(readDB).WithTable(target), 0); err != nil {
+			log.Fatal(err)
+		}
+		if err := r.diff(live, target, *diffLimit); err != nil {
+			log.Fatal(err)
+		}
+		if err := readDB.Exec("DROP TABLE " + target).Error; err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	switch *mode {
+	case "truncate":
+		if err := r.clear(live); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := r.replay(ctx, projection.NewInterventionProjector(readDB), 0); err != nil {
+			log.Fatal(err)
+		}
+	case "swap":
+		target := live + "_rebuild"
+		if err := r.createSideTable(live, target, true); err != nil {
+			log.Fatal(err)
+		}
+		position, err := r.replay(ctx, projection.NewInterventionProjector(readDB).WithTable(target), 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := r.swap(ctx, live, target, position); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := projection.RebuildAnalytics(ctx, readDB, *tenantID); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Projection rebuilt")
+}
+
+type rebuilder struct {
+	writeDB    *gorm.DB
+	readDB     *gorm.DB
+	eventStore *repository.EventStoreRepository
+	source     string
+	tenantID   string
+	batchSize  int
+}
+
+// createSideTable creates an empty copy of the live table. When only one
+// tenant is rebuilt and keepOthers is set, the other tenants' rows are
+// copied over so the swap leaves them untouched.
+func (r *rebuilder) createSideTable(live, target string, keepOthers bool) error {
+	if err := r.readDB.Exec("DROP TABLE IF EXISTS " + target).Error; err != nil {
+		return err
+	}
+	if err := r.readDB.Exec("CREATE TABLE " + target + " (LIKE " + live + " INCLUDING ALL)").Error; err != nil {
+		return err
+	}
+	if keepOthers && r.tenantID != "" {
+		return r.copyOtherTenants(r.readDB, live, target)
+	}
+	return nil
+}
+
+func (r *rebuilder) copyOtherTenants(db *gorm.DB, live, target string) error {
+	if err := db.Exec("DELETE FROM "+target+" WHERE tenant_id <> ?", r.tenantID).Error; err != nil {
+		return err
+	}
+	return db.Exec("INSERT INTO "+target+" SELECT * FROM "+live+" WHERE tenant_id <> ?", r.tenantID).Error
+}
+
+func (r *rebuilder) clear(live string) error {
+	if r.tenantID != "" {
+		return r.readDB.Exec("DELETE FROM "+live+" WHERE tenant_id = ?", r.tenantID).Error
+	}
+	return r.readDB.Exec("TRUNCATE " + live).Error
+}
+
+// swap replaces the live table with the rebuilt one. The live table is
+// locked first, so the worker cannot apply an event to it that the rebuilt
+// table would miss; events stored since the replay are caught up under the
+// lock. Worker writes blocked by the lock fail once the old table is
+// dropped and are redelivered against the new one.
+func (r *rebuilder) swap(ctx context.Context, live, target string, position int64) error {
+	return r.readDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE " + live + " IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		if r.source == "events" {
+			log.Printf("Catching up events after position %d", position)
+			if _, err := r.replay(ctx, projection.NewInterventionProjector(tx).WithTable(target), position); err != nil {
+				return err
+			}
+		}
+		if r.tenantID != "" {
+			if err := r.copyOtherTenants(tx, live, target); err != nil {
+				return err
+			}
+		}
+
+		for _, statement := range []string{
+			"ALTER TABLE " + live + " RENAME TO " + live + "_old",
+			"ALTER TABLE " + target + " RENAME TO " + live,
+			"DROP TABLE " + live + "_old",
+		} {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// replay applies the source through projector and returns the event-store
+// position it reached.
+func (r *rebuilder) replay(ctx context.Context, projector *projection.InterventionProjector, afterPosition int64) (int64, error) {
+	if r.source == "snapshot" {
+		return 0, r.replaySnapshot(ctx, projector)
+	}
+	return r.replayEvents(ctx, projector, afterPosition)
+}
+
+func (r *rebuilder) replayEvents(ctx context.Context, projector *projection.InterventionProjector, afterPosition int64) (int64, error) {
+	total, err := r.eventStore.CountAfter(ctx, r.tenantID, afterPosition)
+	if err != nil {
+		return afterPosition, err
+	}
+	progress := &progress{label: "events", total: total}
+
+	position := afterPosition
+	for {
+		stored, err := r.eventStore.ListAfter(ctx, r.tenantID, position, r.batchSize)
+		if err != nil {
+			return position, err
+		}
+		if len(stored) == 0 {
+			return position, nil
+		}
+
+		for _, row := range stored {
+			event, err := row.Event()
+			if err != nil {
+				return position, err
+			}
+			if err := projector.Apply(ctx, event); err != nil {
+				return position, fmt.Errorf("failed to apply event %s at position %d: %w", row.EventID, row.Position, err)
+			}
+			position = row.Position
+		}
+		progress.add(len(stored))
+	}
+}
+
+func (r *rebuilder) replaySnapshot(ctx context.Context, projector *projection.InterventionProjector) error {
+	query := r.writeDB.WithContext(ctx).Model(&domain.Intervention{})
+	if r.tenantID != "" {
+		query = query.Where("tenant_id = ?", r.tenantID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return err
+	}
+	progress := &progress{label: "interventions", total: total}
+
+	var interventions []*domain.Intervention
+	result := query.FindInBatches(&interventions, r.batchSize, func(tx *gorm.DB, batch int) error {
+		for _, intervention := range interventions {
+			history, err := aggregate.SnapshotEvents(intervention)
+			if err != nil {
+				return err
+			}
+			for _, event := range history {
+				if err := projector.Apply(ctx, event); err != nil {
+					return fmt.Errorf("failed to apply snapshot of intervention %s: %w", intervention.ID, err)
+				}
+			}
+		}
+		progress.add(len(interventions))
+		return nil
+	})
+	return result.Error
+}
+
+// diff prints the interventions whose live and rebuilt rows differ,
+// ignoring the columns that only track event delivery.
+func (r *rebuilder) diff(live, target string, limit int) error {
+	row := func(alias string) string {
+		return "CASE WHEN " + alias + ".id IS NULL THEN NULL ELSE to_jsonb(" + alias + ") - 'last_event_id' - 'last_sequence' END"
+	}
+	query := "SELECT COALESCE(l.id, r.id) AS id, " + row("l") + " AS live, " + row("r") + " AS rebuilt" +
+		" FROM " + live + " l FULL OUTER JOIN " + target + " r ON l.id = r.id" +
+		" WHERE (" + row("l") + ") IS DISTINCT FROM (" + row("r") + ")"
+	args := []interface{}{}
+	if r.tenantID != "" {
+		query += " AND COALESCE(l.tenant_id, r.tenant_id) = ?"
+		args = append(args, r.tenantID)
+	}
+	query += " ORDER BY 1"
+
+	var rows []struct {
+		ID      string
+		Live    *string
+		Rebuilt *string
+	}
+	if err := r.readDB.Raw(query, args...).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	onlyLive, onlyRebuilt, changed := 0, 0, 0
+	for i, row := range rows {
+		show := i < limit
+		switch {
+		case row.Rebuilt == nil:
+			onlyLive++
+			if show {
+				fmt.Printf("- %s only in live table\n", row.ID)
+			}
+		case row.Live == nil:
+			onlyRebuilt++
+			if show {
+				fmt.Printf("+ %s only in rebuilt table\n", row.ID)
+			}
+		default:
+			changed++
+			if show {
+				fmt.Printf("~ %s\n", row.ID)
+				if err := printChangedColumns(*row.Live, *row.Rebuilt); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if len(rows) > limit {
+		fmt.Printf("... %d more\n", len(rows)-limit)
+	}
+	fmt.Printf("Dry run: %d changed, %d only in live table, %d only in rebuilt table\n", changed, onlyLive, onlyRebuilt)
+	return nil
+}
+
+func printChangedColumns(live, rebuilt string) error {
+	var before, after map[string]interface{}
+	if err := json.Unmarshal([]byte(live), &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(rebuilt), &after); err != nil {
+		return err
+	}
+
+	columns := make([]string, 0, len(before))
+	for column := range before {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		if !reflect.DeepEqual(before[column], after[column]) {
+			fmt.Printf("    %s: %v -> %v\n", column, before[column], after[column])
+		}
+	}
+	return nil
+}
+
+type progress struct {
+	label string
+	total int64
+	done  int64
+}
+
+func (p *progress) add(n int) {
+	p.done += int64(n)
+	percent := 100.0
+	if p.total > 0 {
+		percent = float64(p.done) * 100 / float64(p.total)
+	}
+	log.Printf("Replayed %d/%d %s (%.1f%%)", p.done, p.total, p.label, percent)
+}
+
+func openDB(dsn string) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	return db
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/lambda/internal/db"
+	"github.com/lambda/internal/events"
+	"github.com/lambda/internal/reconcile"
+)
+
+// Compares interventions in the write model with interventions_projection
+// in the read model, tenant by tenant, and prints the differences as JSON.
+//
+//	go run ./scripts/reconcile                          # every tenant
+//	go run ./scripts/reconcile -tenant a,b              # only these tenants
+//	go run ./scripts/reconcile -republish               # also publish the repair events to Kinesis
+//
+// Connections come from the same environment as the services (WRITE_DB_*,
+// READ_DB_*, REDIS_*, AWS_*). Rows marked "rebuild" cannot be repaired by
+// republishing; run scripts/rebuildprojection for them.
+func main() {
+	tenants := flag.String("tenant", "", "comma-separated tenants to check (default: every tenant in either model)")
+	republish := flag.Bool("republish", false, "publish the events that repair missing and stale rows")
+	streamName := flag.String("stream", getEnv("KINESIS_STREAM_NAME", "intervention-events"), "Kinesis stream to republish to")
+	out := flag.String("out", "", "write the report to this file instead of stdout")
+	flag.Parse()
+
+	ctx := context.Background()
+	dbConfig, err := db.NewDBConfig(ctx)
+	if err != nil {
+		log.Fatalf("failed to initialize database config: %v", err)
+	}
+	defer dbConfig.Close()
+
+	quiet := &gorm.Session{Logger: logger.Default.LogMode(logger.Warn)}
+	checker := reconcile.NewChecker(dbConfig.WriteDB.Session(quiet), dbConfig.ReadDB.Session(quiet))
+
+	var tenantIDs []string
+	if *tenants != "" {
+		tenantIDs = strings.Split(*tenants, ",")
+	} else if tenantIDs, err = checker.Tenants(ctx); err != nil {
+		log.Fatalf("failed to list tenants: %v", err)
+	}
+
+	var publisher events.EventPublisher
+	if *republish {
+		publisher = events.NewKinesisEventPublisher(dbConfig.AWS, *streamName)
+	}
+
+	report := struct {
+		CheckedAt time.Time                 `json:"checked_at"`
+		Republish bool                      `json:"republish"`
+		Tenants   []*reconcile.TenantReport `json:"tenants"`
+	}{CheckedAt: time.Now().UTC(), Republish: *republish, Tenants: []*reconcile.TenantReport{}}
+
+	for _, tenantID := range tenantIDs {
+		tenant, err := checker.CheckTenant(ctx, tenantID)
+		if err != nil {
+			log.Fatalf("failed to check tenant %s: %v", tenantID, err)
+		}
+		log.Printf("Tenant %s: %d missing, %d extra, %d divergent", tenantID, tenant.Missing, tenant.Extra, tenant.Divergent)
+
+		if publisher != nil {
+			if err := checker.Republish(ctx, tenant, publisher); err != nil {
+				log.Fatalf("failed to republish events for tenant %s: %v", tenantID, err)
+			}
+		}
+		report.Tenants = append(report.Tenants, tenant)
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		if output, err = os.Create(*out); err != nil {
+			log.Fatalf("failed to create %s: %v", *out, err)
+		}
+		defer output.Close()
+	}
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("failed to write report: %v", err)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
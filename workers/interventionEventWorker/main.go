@@ -5,111 +5,143 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log"
+	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+
+	internalevents "github.com/lambda/internal/events"
+	"github.com/lambda/internal/metrics"
+	"github.com/lambda/internal/projection"
+	"github.com/lambda/internal/requestctx"
 )
 
-var readDB *gorm.DB
+// queueSampleInterval limits how often a warm Lambda reads queue depths.
+const queueSampleInterval = time.Minute
 
-func init() {
-	// This would be initialized in the Lambda environment
-	// For now, we'll leave it as nil since the Lambda handler
-	// will receive the DB connection through the context or
-	// environment variables
-}
-
-func HandleRequest(ctx context.Context, sqsEvent events.SQSEvent) error {
-	for _, record := range sqsEvent.Records {
-		log.Printf("Processing message: %s", record.MessageId)
+var (
+	projector    *projection.InterventionProjector
+	deadLetters  *projection.DeadLetterQueue
+	recorder     metrics.Recorder
+	queueMonitor *metrics.QueueMonitor
+)
 
-		var domainEvent map[string]interface{}
-		if err := json.Unmarshal([]byte(record.Body), &domainEvent); err != nil {
-			log.Printf("Failed to unmarshal event: %v", err)
-			continue
-		}
+func init() {
+	dsn := os.Getenv("READ_DB_URL")
+	if dsn == "" {
+		host := getEnv("READ_DB_HOST", "localhost")
+		port := getEnv("READ_DB_PORT", "5433")
+		user := getEnv("READ_DB_USER", "postgres")
+		password := getEnv("READ_DB_PASSWORD", "postgres")
+		dbname := getEnv("READ_DB_NAME", "read_model")
+		sslmode := getEnv("READ_DB_SSLMODE", "disable")
+		dsn = "host=" + host + " port=" + port + " user=" + user + " password=" + password + " dbname=" + dbname + " sslmode=" + sslmode
+	}
 
-		if err := processEvent(ctx, domainEvent); err != nil {
-			log.Printf("Failed to process event %s: %v", domainEvent["event_id"], err)
-			return err
-		}
+	readDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to read database: " + err.Error())
+	}
+	projector = projection.NewInterventionProjector(readDB)
+	recorder = metrics.NewEMFRecorder(getEnv("METRICS_NAMESPACE", "InterventionPipeline"), os.Stdout)
 
-		log.Printf("Successfully processed event: %s (type: %s)", domainEvent["event_id"], domainEvent["event_type"])
+	queueURL := os.Getenv("SQS_QUEUE_URL")
+	dlqURL := os.Getenv("DLQ_QUEUE_URL")
+	if queueURL == "" && dlqURL == "" {
+		return
 	}
 
-	return nil
-}
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		panic("failed to load AWS config: " + err.Error())
+	}
+	sqsClient := sqs.NewFromConfig(cfg)
 
-func processEvent(ctx context.Context, event map[string]interface{}) error {
-	eventType := event["event_type"].(string)
-	
-	switch eventType {
-	case "intervention.created":
-		return handleInterventionCreated(ctx, event)
-	case "intervention.updated":
-		return handleInterventionUpdated(ctx, event)
-	case "intervention.completed":
-		return handleInterventionCompleted(ctx, event)
-	case "intervention.cancelled":
-		return handleInterventionCancelled(ctx, event)
-	default:
-		log.Printf("Unknown event type: %s", eventType)
-		return nil
+	var queues, deadLetterQueues []string
+	if queueURL != "" {
+		queues = append(queues, queueURL)
+	}
+	if dlqURL != "" {
+		deadLetters = projection.NewDeadLetterQueue(sqsClient, dlqURL)
+		deadLetterQueues = append(deadLetterQueues, dlqURL)
 	}
+	queueMonitor = metrics.NewQueueMonitor(sqsClient, recorder, queues, deadLetterQueues)
 }
 
-func handleInterventionCreated(ctx context.Context, event map[string]interface{}) error {
-	// This is a simplified version for Lambda that would typically
-	// call a service layer function
-	log.Printf("Handling intervention created event: %s", event["event_id"])
-	return nil
-}
+// HandleRequest applies each record independently and reports the ones that
+// failed, so SQS only redelivers those instead of the whole batch. The event
+// source mapping must enable ReportBatchItemFailures. On a FIFO queue, once a
+// record fails the rest of its message group is reported as failed without
+// being applied, so the group is redelivered in order.
+func HandleRequest(ctx context.Context, sqsEvent events.SQSEvent) (events.SQSEventResponse, error) {
+	response := events.SQSEventResponse{BatchItemFailures: []events.SQSBatchItemFailure{}}
+	failedGroups := map[string]bool{}
 
-func handleInterventionUpdated(ctx context.Context, event map[string]interface{}) error {
-	// This is a simplified version for Lambda that would typically
-	// call a service layer function
-	log.Printf("Handling intervention updated event: %s", event["event_id"])
-	return nil
-}
-
-func handleInterventionCompleted(ctx context.Context, event map[string]interface{}) error {
-	// This is a simplified version for Lambda that would typically
-	// call a service layer function
-	log.Printf("Handling intervention completed event: %s", event["event_id"])
-	return nil
-}
+	for _, record := range sqsEvent.Records {
+		groupID := record.Attributes["MessageGroupId"]
+		if groupID != "" && failedGroups[groupID] {
+			log.Printf("Deferring message %s behind a failed message in group %s", record.MessageId, groupID)
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{
+				ItemIdentifier: record.MessageId,
+			})
+			continue
+		}
 
-func handleInterventionCancelled(ctx context.Context, event map[string]interface{}) error {
-	// This is a simplified version for Lambda that would typically
-	// call a service layer function
-	log.Printf("Handling intervention cancelled event: %s", event["event_id"])
-	return nil
-}
+		log.Printf("Processing message: %s", record.MessageId)
 
-// Helper functions
-func getString(v interface{}) string {
-	if v == nil {
-		return ""
+		if err := processRecord(ctx, record); err != nil {
+			log.Printf("Failed to process message %s: %v", record.MessageId, err)
+			if groupID != "" {
+				failedGroups[groupID] = true
+			}
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{
+				ItemIdentifier: record.MessageId,
+			})
+		}
 	}
-	if s, ok := v.(string); ok {
-		return s
+
+	if queueMonitor != nil {
+		queueMonitor.SampleIfDue(ctx, queueSampleInterval)
 	}
-	return ""
+	return response, nil
 }
 
-func getStringPtr(v interface{}) *string {
-	if v == nil {
-		return nil
+func processRecord(ctx context.Context, record events.SQSMessage) error {
+	event, err := projection.DecodeEvent(record.MessageId, []byte(record.Body))
+	if err == nil {
+		err = projector.Apply(ctx, event)
 	}
-	if s, ok := v.(string); ok {
-		return &s
+
+	// Events that can never be applied go straight to the DLQ with their
+	// reason when one is configured; otherwise they are left to the queue's
+	// redrive policy.
+	if errors.Is(err, internalevents.ErrInvalidEvent) && deadLetters != nil {
+		log.Printf("Rejecting message %s: %v", record.MessageId, err)
+		return deadLetters.Send(ctx, record.MessageId, record.Body, err)
 	}
+	if err != nil {
+		return err
+	}
+
+	metrics.Applied(recorder, event)
+	log.Printf("Successfully processed event: %s (type: %s, correlation: %s)", event.EventID, event.EventType, event.Metadata[requestctx.MetadataCorrelationID])
 	return nil
 }
 
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
 func main() {
 	lambda.Start(HandleRequest)
-}
\ No newline at end of file
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/repository"
+	"github.com/lambda/internal/service"
+)
+
+// The sweeper only makes invitations past their expiry say so. Accepting
+// and listing already treat them as expired by expires_at, so a late sweep
+// never lets one be accepted.
+var invitationService *service.InvitationService
+
+func init() {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "host=localhost user=postgres password=postgres dbname=write_model port=5432 sslmode=disable"
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to database: " + err.Error())
+	}
+
+	invitationService = service.NewInvitationService(repository.NewInvitationRepository(db), nil, os.Getenv("JWT_SECRET"))
+}
+
+func HandleRequest(ctx context.Context, event events.CloudWatchEvent) error {
+	expired, err := invitationService.ExpireInvitations(ctx)
+	if err != nil {
+		log.Printf("Invitation sweeper failed: %v", err)
+		return err
+	}
+
+	log.Printf("Invitation sweeper expired %d invitations", expired)
+	return nil
+}
+
+func main() {
+	lambda.Start(HandleRequest)
+}
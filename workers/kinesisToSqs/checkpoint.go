@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// shardEnd is stored as a shard's checkpoint once every record in a closed
+// shard has been forwarded. Child shards created by resharding wait for it.
+const shardEnd = "SHARD_END"
+
+// CheckpointStore records, per shard, the sequence number of the last
+// record that was forwarded successfully.
+type CheckpointStore interface {
+	Get(ctx context.Context, shardID string) (string, error)
+	Save(ctx context.Context, shardID, sequenceNumber string) error
+}
+
+type redisCheckpointStore struct {
+	client *redis.Client
+	key    string
+}
+
+// newRedisCheckpointStore keeps the checkpoints of one stream in a single
+// Redis hash keyed by shard ID.
+func newRedisCheckpointStore(client *redis.Client, streamName string) *redisCheckpointStore {
+	return &redisCheckpointStore{
+		client: client,
+		key:    "kinesis-to-sqs:checkpoints:" + streamName,
+	}
+}
+
+func (s *redisCheckpointStore) Get(ctx context.Context, shardID string) (string, error) {
+	sequenceNumber, err := s.client.HGet(ctx, s.key, shardID).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return sequenceNumber, err
+}
+
+func (s *redisCheckpointStore) Save(ctx context.Context, shardID, sequenceNumber string) error {
+	return s.client.HSet(ctx, s.key, shardID, sequenceNumber).Err()
+}
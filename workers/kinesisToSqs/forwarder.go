@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"github.com/lambda/internal/metrics"
+)
+
+const (
+	shardDiscoveryInterval = 30 * time.Second
+	pollInterval           = 2 * time.Second
+	retryInterval          = 5 * time.Second
+)
+
+// forwarder reads every shard of a stream and hands each batch of records to
+// forward in sequence order. A shard is only read once its parent shards
+// have been read to the end, so ordering per partition key survives
+// resharding.
+type forwarder struct {
+	client      *kinesis.Client
+	streamName  string
+	checkpoints CheckpointStore
+	forward     func(ctx context.Context, records []types.Record) error
+	metrics     metrics.Recorder
+
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+func newForwarder(client *kinesis.Client, streamName string, checkpoints CheckpointStore, recorder metrics.Recorder, forward func(ctx context.Context, records []types.Record) error) *forwarder {
+	return &forwarder{
+		client:      client,
+		streamName:  streamName,
+		checkpoints: checkpoints,
+		forward:     forward,
+		metrics:     recorder,
+		running:     map[string]bool{},
+	}
+}
+
+// Run follows the stream until ctx is cancelled, rediscovering shards
+// periodically so that children created by resharding are picked up.
+func (f *forwarder) Run(ctx context.Context) {
+	for {
+		if err := f.startReadyShards(ctx); err != nil {
+			log.Printf("Error discovering shards: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			f.wg.Wait()
+			return
+		case <-time.After(shardDiscoveryInterval):
+		}
+	}
+}
+
+func (f *forwarder) startReadyShards(ctx context.Context) error {
+	shards, err := f.listShards(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		known[aws.ToString(shard.ShardId)] = true
+	}
+
+	for _, shard := range shards {
+		shardID := aws.ToString(shard.ShardId)
+		if f.isRunning(shardID) {
+			continue
+		}
+
+		checkpoint, err := f.checkpoints.Get(ctx, shardID)
+		if err != nil {
+			return err
+		}
+		if checkpoint == shardEnd {
+			continue
+		}
+
+		ready, err := f.parentsFinished(ctx, shard, known)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			continue
+		}
+
+		f.setRunning(shardID, true)
+		f.wg.Add(1)
+		go f.consumeShard(ctx, shardID, checkpoint)
+	}
+	return nil
+}
+
+func (f *forwarder) listShards(ctx context.Context) ([]types.Shard, error) {
+	var shards []types.Shard
+	input := &kinesis.ListShardsInput{StreamName: aws.String(f.streamName)}
+	for {
+		resp, err := f.client.ListShards(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, resp.Shards...)
+		if resp.NextToken == nil {
+			return shards, nil
+		}
+		// StreamName must not be set together with NextToken.
+		input = &kinesis.ListShardsInput{NextToken: resp.NextToken}
+	}
+}
+
+// parentsFinished reports whether every parent of the shard that is still
+// within the stream's retention period has been read to the end.
+func (f *forwarder) parentsFinished(ctx context.Context, shard types.Shard, known map[string]bool) (bool, error) {
+	for _, parent := range []*string{shard.ParentShardId, shard.AdjacentParentShardId} {
+		parentID := aws.ToString(parent)
+		if parentID == "" || !known[parentID] {
+			continue
+		}
+		checkpoint, err := f.checkpoints.Get(ctx, parentID)
+		if err != nil {
+			return false, err
+		}
+		if checkpoint != shardEnd {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (f *forwarder) consumeShard(ctx context.Context, shardID, checkpoint string) {
+	defer f.wg.Done()
+	defer f.setRunning(shardID, false)
+
+	log.Printf("Reading shard %s from checkpoint %q", shardID, checkpoint)
+
+	var iterator *string
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if iterator == nil {
+			var err error
+			iterator, err = f.shardIterator(ctx, shardID, checkpoint)
+			if err != nil {
+				log.Printf("Error getting iterator for shard %s: %v", shardID, err)
+				sleep(ctx, retryInterval)
+				continue
+			}
+		}
+
+		resp, err := f.client.GetRecords(ctx, &kinesis.GetRecordsInput{
+			ShardIterator: iterator,
+			Limit:         aws.Int32(100),
+		})
+		if err != nil {
+			// The iterator may have expired; get a new one from the checkpoint.
+			log.Printf("Error getting records from shard %s: %v", shardID, err)
+			iterator = nil
+			sleep(ctx, retryInterval)
+			continue
+		}
+		if resp.MillisBehindLatest != nil {
+			f.metrics.IteratorAge(f.streamName, shardID, time.Duration(*resp.MillisBehindLatest)*time.Millisecond)
+		}
+
+		if len(resp.Records) > 0 {
+			if !f.forwardWithRetry(ctx, shardID, resp.Records) {
+				return
+			}
+			checkpoint = aws.ToString(resp.Records[len(resp.Records)-1].SequenceNumber)
+			if err := f.checkpoints.Save(ctx, shardID, checkpoint); err != nil {
+				log.Printf("Error saving checkpoint for shard %s: %v", shardID, err)
+			}
+		}
+
+		if resp.NextShardIterator == nil {
+			if err := f.checkpoints.Save(ctx, shardID, shardEnd); err != nil {
+				log.Printf("Error marking shard %s finished: %v", shardID, err)
+				iterator = nil
+				sleep(ctx, retryInterval)
+				continue
+			}
+			log.Printf("Shard %s is closed and fully forwarded", shardID)
+			return
+		}
+		iterator = resp.NextShardIterator
+
+		if len(resp.Records) == 0 {
+			sleep(ctx, pollInterval)
+		}
+	}
+}
+
+// forwardWithRetry retries a batch until it is forwarded, so a failing
+// target holds the shard back instead of dropping events. Records that were
+// already delivered before a failure are sent again. It returns false if ctx
+// is cancelled first.
+func (f *forwarder) forwardWithRetry(ctx context.Context, shardID string, records []types.Record) bool {
+	for {
+		err := f.forward(ctx, records)
+		if err == nil {
+			return true
+		}
+		log.Printf("Error forwarding %d records from shard %s: %v", len(records), shardID, err)
+		if !sleep(ctx, retryInterval) {
+			return false
+		}
+	}
+}
+
+func (f *forwarder) shardIterator(ctx context.Context, shardID, checkpoint string) (*string, error) {
+	input := &kinesis.GetShardIteratorInput{
+		StreamName:        aws.String(f.streamName),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: types.ShardIteratorTypeTrimHorizon,
+	}
+	if checkpoint != "" {
+		input.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		input.StartingSequenceNumber = aws.String(checkpoint)
+	}
+
+	resp, err := f.client.GetShardIterator(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ShardIterator, nil
+}
+
+func (f *forwarder) isRunning(shardID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.running[shardID]
+}
+
+func (f *forwarder) setRunning(shardID string, running bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if running {
+		f.running[shardID] = true
+	} else {
+		delete(f.running, shardID)
+	}
+}
+
+// sleep waits for d and reports false if ctx was cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
@@ -2,15 +2,23 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"os"
-	"time"
+	"os/signal"
+	"syscall"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+
+	internalevents "github.com/lambda/internal/events"
+	"github.com/lambda/internal/metrics"
 )
 
 func main() {
@@ -44,84 +52,100 @@ func main() {
 	sqsClient := sqs.NewFromConfig(cfg)
 
 	streamName := getEnv("KINESIS_STREAM_NAME", "intervention-events")
-	queueURL := getEnv("SQS_QUEUE_URL", "http://localhost:4566/000000000000/intervention-events")
+	queueURL := getEnv("SQS_QUEUE_URL", "http://localhost:4566/000000000000/intervention-events.fifo")
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", getEnv("REDIS_HOST", "localhost"), getEnv("REDIS_PORT", "6380")),
+		Password: getEnv("REDIS_PASSWORD", ""),
+	})
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		log.Fatalf("failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
 
 	log.Printf("Starting Kinesis to SQS forwarder...")
 	log.Printf("Kinesis stream: %s", streamName)
-	log.Printf("SQS queue: %s", queueURL)
+	log.Printf("Default SQS queue: %s", queueURL)
+
+	ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
 
-	// Get shard iterator
-	shardIterator, err := getShardIterator(ctx, kinesisClient, streamName)
+	router, err := loadRoutingTable(queueURL)
 	if err != nil {
-		log.Fatalf("failed to get shard iterator: %v", err)
+		log.Fatalf("failed to load routing config: %v", err)
 	}
+	sender := newBatchSender(sqsClient)
 
-	// Poll Kinesis and forward to SQS
-	for {
-		records, nextIterator, err := getRecords(ctx, kinesisClient, shardIterator)
-		if err != nil {
-			log.Printf("Error getting records: %v", err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
+	recorder := metrics.NewPrometheusRecorder(prometheus.DefaultRegisterer)
+	go metrics.Serve(ctx, getEnv("METRICS_ADDR", ":2112"))
 
-		for _, record := range records {
-			if err := forwardToSQS(ctx, sqsClient, queueURL, record); err != nil {
-				log.Printf("Error forwarding to SQS: %v", err)
-			} else {
-				log.Printf("Forwarded event to SQS: %s", string(record.Data))
-			}
-		}
+	checkpoints := newRedisCheckpointStore(redisClient, streamName)
+	fwd := newForwarder(kinesisClient, streamName, checkpoints, recorder, func(ctx context.Context, records []types.Record) error {
+		return forwardToSQS(ctx, sender, router, records)
+	})
+	fwd.Run(ctx)
 
-		shardIterator = nextIterator
-		time.Sleep(2 * time.Second)
-	}
+	log.Printf("Kinesis to SQS forwarder stopped")
 }
 
-func getShardIterator(ctx context.Context, client *kinesis.Client, streamName string) (*string, error) {
-	describeResp, err := client.DescribeStream(ctx, &kinesis.DescribeStreamInput{
-		StreamName: aws.String(streamName),
-	})
-	if err != nil {
-		return nil, err
+// loadRoutingTable reads SQS_ROUTES, falling back to sending every event to
+// the default queue.
+func loadRoutingTable(defaultQueueURL string) (*RoutingTable, error) {
+	if config := os.Getenv("SQS_ROUTES"); config != "" {
+		return ParseRoutingTable(config)
 	}
+	return NewRoutingTable([]Route{{EventTypes: []string{"*"}, Queues: []string{defaultQueueURL}}})
+}
 
-	if len(describeResp.StreamDescription.Shards) == 0 {
-		return nil, nil
-	}
+// forwardToSQS fans each record out to the queues its event type is routed
+// to. Records whose type has no route are skipped. FIFO queues get the
+// event's aggregate as message group and its event ID for deduplication.
+func forwardToSQS(ctx context.Context, sender *batchSender, router *RoutingTable, records []types.Record) error {
+	var queues []string
+	byQueue := map[string][]outgoingMessage{}
+
+	for _, record := range records {
+		var event internalevents.DomainEvent
+		if err := json.Unmarshal(record.Data, &event); err != nil {
+			log.Printf("Record %s is not a domain event, routing it without an event type: %v", aws.ToString(record.SequenceNumber), err)
+		}
 
-	shardId := describeResp.StreamDescription.Shards[0].ShardId
+		targets := router.Targets(string(event.EventType))
+		if len(targets) == 0 {
+			log.Printf("No route for event type %q, skipping record %s", event.EventType, aws.ToString(record.SequenceNumber))
+			continue
+		}
+		// Records that are not domain events fall back to the Kinesis
+		// partition key and sequence number for FIFO queues.
+		groupID := event.MessageGroupID()
+		if groupID == "" {
+			groupID = aws.ToString(record.PartitionKey)
+		}
+		deduplicationID := event.DeduplicationID()
+		if deduplicationID == "" {
+			deduplicationID = aws.ToString(record.SequenceNumber)
+		}
 
-	resp, err := client.GetShardIterator(ctx, &kinesis.GetShardIteratorInput{
-		StreamName:        aws.String(streamName),
-		ShardId:           shardId,
-		ShardIteratorType: types.ShardIteratorTypeTrimHorizon,
-	})
-	if err != nil {
-		return nil, err
+		for _, queue := range targets {
+			if _, ok := byQueue[queue]; !ok {
+				queues = append(queues, queue)
+			}
+			byQueue[queue] = append(byQueue[queue], outgoingMessage{
+				ID:              aws.ToString(record.SequenceNumber),
+				Body:            string(record.Data),
+				GroupID:         groupID,
+				DeduplicationID: deduplicationID,
+			})
+		}
 	}
 
-	return resp.ShardIterator, nil
-}
-
-func getRecords(ctx context.Context, client *kinesis.Client, shardIterator *string) ([]types.Record, *string, error) {
-	resp, err := client.GetRecords(ctx, &kinesis.GetRecordsInput{
-		ShardIterator: shardIterator,
-		Limit:         aws.Int32(10),
-	})
-	if err != nil {
-		return nil, nil, err
+	for _, queue := range queues {
+		if err := sender.Send(ctx, queue, byQueue[queue]); err != nil {
+			return err
+		}
+		log.Printf("Forwarded %d events to %s", len(byQueue[queue]), queue)
 	}
-
-	return resp.Records, resp.NextShardIterator, nil
-}
-
-func forwardToSQS(ctx context.Context, client *sqs.Client, queueURL string, record types.Record) error {
-	_, err := client.SendMessage(ctx, &sqs.SendMessageInput{
-		QueueUrl:    aws.String(queueURL),
-		MessageBody: aws.String(string(record.Data)),
-	})
-	return err
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
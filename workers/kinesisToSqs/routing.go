@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+)
+
+// Route sends every event whose type matches one of EventTypes to each of
+// Queues. Patterns use path.Match syntax, so "intervention.*" matches every
+// intervention event and "*" matches all events.
+type Route struct {
+	EventTypes []string `json:"event_types"`
+	Queues     []string `json:"queues"`
+}
+
+// RoutingTable resolves an event type to the queues it fans out to. It does
+// not talk to AWS.
+type RoutingTable struct {
+	routes []Route
+}
+
+func NewRoutingTable(routes []Route) (*RoutingTable, error) {
+	for i, route := range routes {
+		if len(route.EventTypes) == 0 {
+			return nil, fmt.Errorf("route %d has no event types", i)
+		}
+		if len(route.Queues) == 0 {
+			return nil, fmt.Errorf("route %d has no queues", i)
+		}
+		for _, pattern := range route.EventTypes {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("route %d has invalid event type pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+	return &RoutingTable{routes: routes}, nil
+}
+
+// ParseRoutingTable reads a JSON array of routes, for example
+//
+//	[{"event_types": ["intervention.*"], "queues": ["<projection-url>", "<audit-url>"]},
+//	 {"event_types": ["intervention.completed"], "queues": ["<notification-url>"]}]
+func ParseRoutingTable(config string) (*RoutingTable, error) {
+	var routes []Route
+	if err := json.Unmarshal([]byte(config), &routes); err != nil {
+		return nil, fmt.Errorf("invalid routing config: %w", err)
+	}
+	if len(routes) == 0 {
+		return nil, errors.New("routing config has no routes")
+	}
+	return NewRoutingTable(routes)
+}
+
+// Targets returns every queue the event type is routed to, in route order
+// and without duplicates. It returns nil if no route matches.
+func (t *RoutingTable) Targets(eventType string) []string {
+	var targets []string
+	seen := map[string]bool{}
+	for _, route := range t.routes {
+		if !route.matches(eventType) {
+			continue
+		}
+		for _, queue := range route.Queues {
+			if !seen[queue] {
+				seen[queue] = true
+				targets = append(targets, queue)
+			}
+		}
+	}
+	return targets
+}
+
+func (r Route) matches(eventType string) bool {
+	for _, pattern := range r.EventTypes {
+		if ok, _ := path.Match(pattern, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoutingTableTargets(t *testing.T) {
+	table, err := ParseRoutingTable(`[
+		{"event_types": ["intervention.*"], "queues": ["projection", "audit"]},
+		{"event_types": ["intervention.completed", "patient.created"], "queues": ["notification", "audit"]},
+		{"event_types": ["*.deleted"], "queues": ["cleanup"]}
+	]`)
+	if err != nil {
+		t.Fatalf("ParseRoutingTable: %v", err)
+	}
+
+	tests := []struct {
+		eventType string
+		want      []string
+	}{
+		{"intervention.created", []string{"projection", "audit"}},
+		{"intervention.completed", []string{"projection", "audit", "notification"}},
+		{"patient.created", []string{"notification", "audit"}},
+		{"patient.deleted", []string{"cleanup"}},
+		{"intervention.deleted", []string{"projection", "audit", "cleanup"}},
+		{"patient.updated", nil},
+		{"", nil},
+		// path.Match's * does not cross a slash.
+		{"intervention/created", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			if got := table.Targets(tt.eventType); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Targets(%q) = %v, want %v", tt.eventType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutingTableCatchAll(t *testing.T) {
+	table, err := NewRoutingTable([]Route{{EventTypes: []string{"*"}, Queues: []string{"default"}}})
+	if err != nil {
+		t.Fatalf("NewRoutingTable: %v", err)
+	}
+	for _, eventType := range []string{"intervention.created", "anything"} {
+		if got := table.Targets(eventType); !reflect.DeepEqual(got, []string{"default"}) {
+			t.Errorf("Targets(%q) = %v, want [default]", eventType, got)
+		}
+	}
+}
+
+func TestParseRoutingTableErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"not json", `intervention.* -> projection`},
+		{"truncated", `[{"event_types": ["intervention.*"], "queues": ["a"]`},
+		{"object instead of array", `{"event_types": ["*"], "queues": ["a"]}`},
+		{"wrong field type", `[{"event_types": "intervention.*", "queues": ["a"]}]`},
+		{"empty array", `[]`},
+		{"null", `null`},
+		{"route without event types", `[{"queues": ["a"]}]`},
+		{"route without queues", `[{"event_types": ["*"]}]`},
+		{"bad pattern", `[{"event_types": ["intervention.["], "queues": ["a"]}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if table, err := ParseRoutingTable(tt.config); err == nil {
+				t.Fatalf("ParseRoutingTable(%s) = %v, want an error", tt.config, table)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// maxBatchEntries is the SendMessageBatch limit.
+const maxBatchEntries = 10
+
+type outgoingMessage struct {
+	// ID must be unique within the messages sent in one call.
+	ID   string
+	Body string
+	// GroupID and DeduplicationID are only sent to FIFO queues.
+	GroupID         string
+	DeduplicationID string
+}
+
+// batchSender sends messages with SendMessageBatch and retries only the
+// entries SQS reports as failed. On a FIFO queue it instead resends
+// everything from the first failed entry on, so a later message of a group
+// never overtakes an earlier one; the deduplication IDs make the resent
+// messages that had already been accepted no-ops.
+type batchSender struct {
+	client      sqsBatchAPI
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// sqsBatchAPI is the part of *sqs.Client the sender uses.
+type sqsBatchAPI interface {
+	SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error)
+}
+
+func newBatchSender(client sqsBatchAPI) *batchSender {
+	return &batchSender{client: client, maxAttempts: 3, backoff: 500 * time.Millisecond}
+}
+
+func (s *batchSender) Send(ctx context.Context, queueURL string, messages []outgoingMessage) error {
+	for start := 0; start < len(messages); start += maxBatchEntries {
+		end := min(start+maxBatchEntries, len(messages))
+		if err := s.sendBatch(ctx, queueURL, messages[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *batchSender) sendBatch(ctx context.Context, queueURL string, messages []outgoingMessage) error {
+	fifo := isFIFOQueue(queueURL)
+	pending := messages
+	for attempt := 1; ; attempt++ {
+		entries := make([]types.SendMessageBatchRequestEntry, len(pending))
+		for i, message := range pending {
+			entries[i] = types.SendMessageBatchRequestEntry{
+				Id:          aws.String(message.ID),
+				MessageBody: aws.String(message.Body),
+			}
+			if fifo {
+				entries[i].MessageGroupId = aws.String(message.GroupID)
+				entries[i].MessageDeduplicationId = aws.String(message.DeduplicationID)
+			}
+		}
+
+		var reasons []string
+		resp, err := s.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(queueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			reasons = []string{err.Error()}
+		} else {
+			if len(resp.Failed) == 0 {
+				return nil
+			}
+			failed := map[string]bool{}
+			for _, entry := range resp.Failed {
+				failed[aws.ToString(entry.Id)] = true
+				reasons = append(reasons, fmt.Sprintf("%s: %s", aws.ToString(entry.Code), aws.ToString(entry.Message)))
+			}
+			var retry []outgoingMessage
+			for i, message := range pending {
+				if fifo && failed[message.ID] {
+					retry = pending[i:]
+					break
+				}
+				if failed[message.ID] {
+					retry = append(retry, message)
+				}
+			}
+			pending = retry
+		}
+
+		if attempt >= s.maxAttempts {
+			return fmt.Errorf("%d of %d messages not sent to %s: %s",
+				len(pending), len(messages), queueURL, strings.Join(reasons, "; "))
+		}
+		log.Printf("Retrying %d messages to %s (attempt %d): %s", len(pending), queueURL, attempt, strings.Join(reasons, "; "))
+		if !sleep(ctx, s.backoff*time.Duration(attempt)) {
+			return ctx.Err()
+		}
+	}
+}
+
+func isFIFOQueue(queueURL string) bool {
+	return strings.HasSuffix(queueURL, ".fifo")
+}
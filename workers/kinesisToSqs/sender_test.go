@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fakeSQS records the entry IDs of each SendMessageBatch call and fails the
+// entries, or the whole call, its script says to.
+type fakeSQS struct {
+	// failures[i] lists the entry IDs call i reports as failed.
+	failures [][]string
+	// errs[i], when set, fails call i outright.
+	errs  []error
+	calls [][]string
+	input []*sqs.SendMessageBatchInput
+}
+
+func (f *fakeSQS) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	call := len(f.calls)
+	var ids []string
+	for _, entry := range params.Entries {
+		ids = append(ids, aws.ToString(entry.Id))
+	}
+	f.calls = append(f.calls, ids)
+	f.input = append(f.input, params)
+
+	if call < len(f.errs) && f.errs[call] != nil {
+		return nil, f.errs[call]
+	}
+	output := &sqs.SendMessageBatchOutput{}
+	if call < len(f.failures) {
+		for _, id := range f.failures[call] {
+			output.Failed = append(output.Failed, types.BatchResultErrorEntry{
+				Id:      aws.String(id),
+				Code:    aws.String("InternalError"),
+				Message: aws.String("try again"),
+			})
+		}
+	}
+	return output, nil
+}
+
+func messages(ids ...string) []outgoingMessage {
+	out := make([]outgoingMessage, len(ids))
+	for i, id := range ids {
+		out[i] = outgoingMessage{ID: id, Body: "body-" + id, GroupID: "group", DeduplicationID: "dedup-" + id}
+	}
+	return out
+}
+
+func TestBatchSenderRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		queueURL  string
+		failures  [][]string
+		errs      []error
+		wantCalls [][]string
+		wantErr   bool
+	}{
+		{
+			name:      "all sent",
+			queueURL:  "http://sqs/queue",
+			wantCalls: [][]string{{"a", "b", "c", "d"}},
+		},
+		{
+			name:      "standard queue retries only the failed entries",
+			queueURL:  "http://sqs/queue",
+			failures:  [][]string{{"b", "d"}},
+			wantCalls: [][]string{{"a", "b", "c", "d"}, {"b", "d"}},
+		},
+		{
+			name:      "fifo queue resends from the first failed entry",
+			queueURL:  "http://sqs/queue.fifo",
+			failures:  [][]string{{"b", "d"}},
+			wantCalls: [][]string{{"a", "b", "c", "d"}, {"b", "c", "d"}},
+		},
+		{
+			name:      "fifo queue narrows again on a second partial failure",
+			queueURL:  "http://sqs/queue.fifo",
+			failures:  [][]string{{"b"}, {"c"}},
+			wantCalls: [][]string{{"a", "b", "c", "d"}, {"b", "c", "d"}, {"c", "d"}},
+		},
+		{
+			name:      "request error resends the whole batch",
+			queueURL:  "http://sqs/queue",
+			errs:      []error{errors.New("connection reset")},
+			wantCalls: [][]string{{"a", "b", "c", "d"}, {"a", "b", "c", "d"}},
+		},
+		{
+			name:      "gives up after the last attempt",
+			queueURL:  "http://sqs/queue.fifo",
+			failures:  [][]string{{"c"}, {"c"}, {"c"}},
+			wantCalls: [][]string{{"a", "b", "c", "d"}, {"c", "d"}, {"c", "d"}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSQS{failures: tt.failures, errs: tt.errs}
+			sender := &batchSender{client: client, maxAttempts: 3}
+
+			err := sender.Send(context.Background(), tt.queueURL, messages("a", "b", "c", "d"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(client.calls, tt.wantCalls) {
+				t.Fatalf("calls = %v, want %v", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestBatchSenderFIFOAttributes(t *testing.T) {
+	for _, queueURL := range []string{"http://sqs/queue", "http://sqs/queue.fifo"} {
+		client := &fakeSQS{}
+		sender := &batchSender{client: client, maxAttempts: 1}
+		if err := sender.Send(context.Background(), queueURL, messages("a")); err != nil {
+			t.Fatalf("Send(%s): %v", queueURL, err)
+		}
+
+		entry := client.input[0].Entries[0]
+		fifo := isFIFOQueue(queueURL)
+		if got := entry.MessageGroupId != nil; got != fifo {
+			t.Errorf("%s: MessageGroupId set = %v, want %v", queueURL, got, fifo)
+		}
+		if got := entry.MessageDeduplicationId != nil; got != fifo {
+			t.Errorf("%s: MessageDeduplicationId set = %v, want %v", queueURL, got, fifo)
+		}
+	}
+}
+
+func TestBatchSenderSplitsIntoBatches(t *testing.T) {
+	ids := make([]string, 23)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("m%02d", i)
+	}
+	client := &fakeSQS{}
+	sender := &batchSender{client: client, maxAttempts: 1}
+	if err := sender.Send(context.Background(), "http://sqs/queue", messages(ids...)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var sizes []int
+	for _, call := range client.calls {
+		sizes = append(sizes, len(call))
+	}
+	if !reflect.DeepEqual(sizes, []int{10, 10, 3}) {
+		t.Fatalf("batch sizes = %v, want [10 10 3]", sizes)
+	}
+}
@@ -0,0 +1,81 @@
+//go:build !standalone
+// +build !standalone
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	internalevents "github.com/lambda/internal/events"
+	"github.com/lambda/internal/outbox"
+	"github.com/lambda/internal/repository"
+)
+
+var relay *outbox.Relay
+
+func init() {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "host=localhost user=postgres password=postgres dbname=write_model port=5432 sslmode=disable"
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to database: " + err.Error())
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		panic("failed to load AWS config: " + err.Error())
+	}
+
+	streamName := getEnv("KINESIS_STREAM_NAME", "intervention-events")
+	publisher := internalevents.NewKinesisEventPublisher(cfg, streamName)
+
+	relay = outbox.NewRelay(
+		repository.NewOutboxRepository(db),
+		publisher,
+		getEnvInt("OUTBOX_BATCH_SIZE", outbox.DefaultBatchSize),
+		getEnvInt("OUTBOX_MAX_ATTEMPTS", outbox.DefaultMaxAttempts),
+		time.Duration(getEnvInt("OUTBOX_PUBLISH_TIMEOUT_MS", 0))*time.Millisecond,
+	)
+}
+
+func HandleRequest(ctx context.Context, event events.CloudWatchEvent) error {
+	published, err := relay.Drain(ctx)
+	if err != nil {
+		log.Printf("Outbox relay stopped after publishing %d messages: %v", published, err)
+		return err
+	}
+
+	log.Printf("Outbox relay published %d messages", published)
+	return nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func main() {
+	lambda.Start(HandleRequest)
+}
@@ -0,0 +1,128 @@
+//go:build standalone
+// +build standalone
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/lambda/internal/events"
+	"github.com/lambda/internal/outbox"
+	"github.com/lambda/internal/repository"
+)
+
+var relay *outbox.Relay
+var pollInterval time.Duration
+
+func init() {
+	dsn := os.Getenv("WRITE_DB_URL")
+	if dsn == "" {
+		host := getEnv("WRITE_DB_HOST", "localhost")
+		port := getEnv("WRITE_DB_PORT", "5434")
+		user := getEnv("WRITE_DB_USER", "postgres")
+		password := getEnv("WRITE_DB_PASSWORD", "postgres")
+		dbname := getEnv("WRITE_DB_NAME", "write_model")
+		sslmode := getEnv("WRITE_DB_SSLMODE", "disable")
+		dsn = "host=" + host + " port=" + port + " user=" + user + " password=" + password + " dbname=" + dbname + " sslmode=" + sslmode
+	}
+
+	writeDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to connect to write database: %v", err)
+	}
+	log.Println("Connected to Write DB successfully")
+
+	ctx := context.Background()
+	customResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+		localstackURL := getEnv("LOCALSTACK_URL", "http://localhost:4566")
+		return aws.Endpoint{
+			PartitionID:   "aws",
+			URL:           localstackURL,
+			SigningRegion: "us-east-1",
+		}, nil
+	})
+
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion("us-east-1"),
+		config.WithEndpointResolverWithOptions(customResolver),
+		config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     "test",
+				SecretAccessKey: "test",
+			}, nil
+		})),
+	)
+	if err != nil {
+		log.Fatalf("unable to load SDK config: %v", err)
+	}
+
+	streamName := getEnv("KINESIS_STREAM_NAME", "intervention-events")
+	publisher := events.NewKinesisEventPublisher(cfg, streamName)
+	log.Printf("Publishing outbox to Kinesis stream: %s", streamName)
+
+	relay = outbox.NewRelay(
+		repository.NewOutboxRepository(writeDB),
+		publisher,
+		getEnvInt("OUTBOX_BATCH_SIZE", outbox.DefaultBatchSize),
+		getEnvInt("OUTBOX_MAX_ATTEMPTS", outbox.DefaultMaxAttempts),
+		time.Duration(getEnvInt("OUTBOX_PUBLISH_TIMEOUT_MS", 0))*time.Millisecond,
+	)
+	pollInterval = time.Duration(getEnvInt("OUTBOX_POLL_INTERVAL_MS", 1000)) * time.Millisecond
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-quit
+		log.Println("Shutting down outbox relay...")
+		cancel()
+	}()
+
+	log.Println("Outbox relay started. Polling outbox for messages...")
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay stopped")
+			return
+		default:
+			published, err := relay.Drain(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Error draining outbox: %v", err)
+			}
+			if published > 0 {
+				log.Printf("Published %d outbox messages", published)
+			}
+			time.Sleep(pollInterval)
+		}
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}